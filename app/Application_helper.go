@@ -7,6 +7,19 @@ import (
 	"github.com/johannes-kuhfuss/services_utils/logger"
 )
 
+func dbConnUrl() string {
+	return fmt.Sprintf("host=%v port=%v user=%v password=%v dbname=%v sslmode=disable", cfg.Db.Host, cfg.Db.Port, cfg.Db.Username, cfg.Db.Password, cfg.Db.Name)
+}
+
+func writeTimeout() time.Duration {
+	timeout := 5 * time.Second
+	dequeueTimeout := time.Duration(cfg.Dequeue.MaxWaitSeconds+5) * time.Second
+	if dequeueTimeout > timeout {
+		return dequeueTimeout
+	}
+	return timeout
+}
+
 func formatAsDate(t time.Time) string {
 	year, month, day := t.Date()
 	hour, minute, second := t.Clock()
@@ -21,3 +34,39 @@ func (c cleanJobs) Run() {
 		logger.Error("Error while cleaning jobs from database", nil)
 	}
 }
+
+type cleanIdempotencyKeys struct{}
+
+func (c cleanIdempotencyKeys) Run() {
+	err := idemService.CleanKeys()
+	if err != nil {
+		logger.Error("Error while cleaning expired idempotency keys from database", nil)
+	}
+}
+
+type reapLeases struct{}
+
+func (r reapLeases) Run() {
+	err := jobService.ReleaseExpiredLeases()
+	if err != nil {
+		logger.Error("Error while releasing expired job leases", nil)
+	}
+}
+
+type syncSchedules struct{}
+
+func (s syncSchedules) Run() {
+	err := schedService.SyncSchedules()
+	if err != nil {
+		logger.Error("Error while syncing job schedules", nil)
+	}
+}
+
+type advanceWorkflows struct{}
+
+func (a advanceWorkflows) Run() {
+	err := wfService.AdvanceWorkflows()
+	if err != nil {
+		logger.Error("Error while advancing workflows", nil)
+	}
+}
@@ -25,7 +25,7 @@ import (
 	"github.com/robfig/cron/v3"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
@@ -33,12 +33,27 @@ var (
 	jobRepo      domain.JobRepository
 	jobService   service.DefaultJobService
 	jobHandler   handler.JobHandler
+	schedRepo    domain.ScheduleRepository
+	schedService service.DefaultScheduleService
+	schedHandler handler.ScheduleHandler
+	wfRepo       domain.WorkflowRepository
+	wfService    service.DefaultWorkflowService
+	wfHandler    handler.WorkflowHandler
+	typeRepo     domain.JobTypeRepository
+	typeService  service.DefaultJobTypeService
+	typeHandler  handler.JobTypeHandler
+	tmplRepo     domain.JobTemplateRepository
+	tmplService  service.DefaultJobTemplateService
+	tmplHandler  handler.JobTemplateHandler
+	idemRepo     domain.IdempotencyKeyRepository
+	idemService  service.DefaultIdempotencyService
 	server       http.Server
 	appEnd       chan os.Signal
 	ctx          context.Context
 	cancel       context.CancelFunc
 	jobUiHandler handler.JobUiHandler
 	bgJobs       *cron.Cron
+	jobListener  *pq.Listener
 )
 
 func StartApp() {
@@ -52,6 +67,7 @@ func StartApp() {
 	initDb()
 	initMetrics()
 	wireApp()
+	initJobListener()
 	mapUrls()
 	RegisterForOsSignals()
 	createSanitizers()
@@ -111,7 +127,7 @@ func initServer() {
 		Handler:           cfg.RunTime.Router,
 		ReadTimeout:       5 * time.Second,
 		ReadHeaderTimeout: 0,
-		WriteTimeout:      5 * time.Second,
+		WriteTimeout:      writeTimeout(),
 		IdleTimeout:       120 * time.Second,
 		MaxHeaderBytes:    0,
 	}
@@ -123,8 +139,7 @@ func initServer() {
 
 func initDb() {
 	logger.Info(fmt.Sprintf("Connecting to database at %v:%v", cfg.Db.Host, cfg.Db.Port))
-	connUrl := fmt.Sprintf("host=%v port=%v user=%v password=%v dbname=%v sslmode=disable", cfg.Db.Host, cfg.Db.Port, cfg.Db.Username, cfg.Db.Password, cfg.Db.Name)
-	conn, err := sqlx.Connect("postgres", connUrl)
+	conn, err := sqlx.Connect("postgres", dbConnUrl())
 	if err != nil {
 		logger.Error(fmt.Sprintf("Could not connect to database at %v:%v", cfg.Db.Host, cfg.Db.Port), err)
 		panic(err)
@@ -138,15 +153,48 @@ func initDb() {
 	logger.Info("Successfully connected to database")
 }
 
+func initJobListener() {
+	jobListener = pq.NewListener(dbConnUrl(), 10*time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Error("Error in job notification listener", err)
+		}
+	})
+	err := jobListener.Listen(cfg.Db.NotifyChannel)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not listen on notification channel %v", cfg.Db.NotifyChannel), err)
+		panic(err)
+	}
+	go func() {
+		for range jobListener.Notify {
+			jobService.WakeDequeuers()
+		}
+	}()
+	logger.Info(fmt.Sprintf("Listening for job notifications on channel %v", cfg.Db.NotifyChannel))
+}
+
 func initMetrics() {
 	prometheusRegister()
 }
 
 func wireApp() {
+	typeRepo = repositories.NewJobTypeRepositoryDb(&cfg)
+	typeService = service.NewJobTypeService(&cfg, typeRepo)
+	typeHandler = handler.NewJobTypeHandler(&cfg, typeService)
+	tmplRepo = repositories.NewJobTemplateRepositoryDb(&cfg)
+	tmplService = service.NewJobTemplateService(&cfg, tmplRepo)
+	tmplHandler = handler.NewJobTemplateHandler(&cfg, tmplService)
+	idemRepo = repositories.NewIdempotencyKeyRepositoryDb(&cfg)
+	idemService = service.NewIdempotencyService(&cfg, idemRepo)
 	jobRepo = repositories.NewJobRepositoryDb(&cfg)
-	jobService = service.NewJobService(&cfg, jobRepo)
-	jobHandler = handler.NewJobHandler(&cfg, jobService)
+	jobService = service.NewJobService(&cfg, jobRepo, typeRepo)
+	jobHandler = handler.NewJobHandler(&cfg, jobService, typeService, tmplService, idemService)
 	jobUiHandler = handler.NewJobUiHandler(&cfg, jobService)
+	schedRepo = repositories.NewScheduleRepositoryDb(&cfg)
+	schedService = service.NewScheduleService(&cfg, schedRepo, jobService, typeService, tmplService)
+	schedHandler = handler.NewScheduleHandler(&cfg, schedService, typeService)
+	wfRepo = repositories.NewWorkflowRepositoryDb(&cfg)
+	wfService = service.NewWorkflowService(&cfg, wfRepo, jobRepo, typeRepo)
+	wfHandler = handler.NewWorkflowHandler(&cfg, wfService, typeService)
 }
 
 func mapUrls() {
@@ -160,9 +208,53 @@ func mapUrls() {
 		api.PUT("/:job_id", jobHandler.UpdateJob)
 		api.PUT("/:job_id/status", jobHandler.SetStatusById)
 		api.PUT("/:job_id/history", jobHandler.SetHistoryById)
+		api.GET("/:job_id/history", jobHandler.GetJobHistory)
+		api.PUT("/:job_id/heartbeat", jobHandler.Heartbeat)
+		api.PUT("/:job_id/progress", jobHandler.SetProgressById)
+		api.POST("/:job_id/cancel", jobHandler.CancelJob)
+		api.POST("/:job_id/children", jobHandler.CreateChildJobs)
+		api.GET("/:job_id/children", jobHandler.GetChildJobs)
 		api.PUT("/dequeue", jobHandler.Dequeue)
+		api.GET("/transitions", jobHandler.GetStatusTransitions)
 
 	}
+	deadLetters := cfg.RunTime.Router.Group("/deadletters", validateAuth(), prometheusMetrics())
+	{
+		deadLetters.GET("/", jobHandler.GetAllDeadLetters)
+		deadLetters.GET("/:job_id", jobHandler.GetDeadLetterById)
+		deadLetters.PUT("/:job_id/requeue", jobHandler.RequeueDeadLetter)
+	}
+	schedules := cfg.RunTime.Router.Group("/schedules", validateAuth(), prometheusMetrics())
+	{
+		schedules.POST("/", schedHandler.CreateSchedule)
+		schedules.GET("/", schedHandler.GetAllSchedules)
+		schedules.GET("/:schedule_id", schedHandler.GetScheduleById)
+		schedules.PUT("/:schedule_id", schedHandler.UpdateSchedule)
+		schedules.DELETE("/:schedule_id", schedHandler.DeleteScheduleById)
+	}
+	workflows := cfg.RunTime.Router.Group("/workflows", validateAuth(), prometheusMetrics())
+	{
+		workflows.POST("/", wfHandler.CreateWorkflow)
+		workflows.GET("/", wfHandler.GetAllWorkflows)
+		workflows.GET("/:workflow_id", wfHandler.GetWorkflowById)
+	}
+	jobTypes := cfg.RunTime.Router.Group("/jobtypes", validateAuth(), prometheusMetrics())
+	{
+		jobTypes.POST("/", typeHandler.CreateJobType)
+		jobTypes.GET("/", typeHandler.GetAllJobTypes)
+		jobTypes.GET("/:job_type", typeHandler.GetJobTypeByName)
+		jobTypes.PUT("/:job_type", typeHandler.UpdateJobType)
+		jobTypes.DELETE("/:job_type", typeHandler.DeleteJobTypeByName)
+	}
+	templates := cfg.RunTime.Router.Group("/templates", validateAuth(), prometheusMetrics())
+	{
+		templates.POST("/", tmplHandler.CreateJobTemplate)
+		templates.GET("/", tmplHandler.GetAllJobTemplates)
+		templates.GET("/:template_name", tmplHandler.GetJobTemplateByName)
+		templates.GET("/:template_name/versions", tmplHandler.GetJobTemplateVersions)
+		templates.PUT("/:template_name", tmplHandler.UpdateJobTemplate)
+		templates.DELETE("/:template_name", tmplHandler.DeleteJobTemplateByName)
+	}
 	ui := cfg.RunTime.Router.Group("/")
 	{
 		ui.GET("/", jobUiHandler.JobListPage)
@@ -191,6 +283,16 @@ func scheduleBgJobs() {
 	bgJobs = cron.New()
 	cleanJobcycle := fmt.Sprintf("@every %dh", cfg.Cleanup.CycleHours)
 	bgJobs.AddJob(cleanJobcycle, cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger)).Then(&cleanJobs{}))
+	bgJobs.AddJob(cleanJobcycle, cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger)).Then(&cleanIdempotencyKeys{}))
+	reapLeasesCycle := fmt.Sprintf("@every %ds", cfg.Lease.ReapCycleSeconds)
+	bgJobs.AddJob(reapLeasesCycle, cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger)).Then(&reapLeases{}))
+	syncSchedulesCycle := fmt.Sprintf("@every %ds", cfg.Scheduling.ScheduleSyncSeconds)
+	bgJobs.AddJob(syncSchedulesCycle, cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger)).Then(&syncSchedules{}))
+	advanceWorkflowsCycle := fmt.Sprintf("@every %ds", cfg.Scheduling.WorkflowAdvanceSeconds)
+	bgJobs.AddJob(advanceWorkflowsCycle, cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger)).Then(&advanceWorkflows{}))
+	if err := schedService.Start(bgJobs); err != nil {
+		logger.Error("Could not load job schedules", err)
+	}
 	bgJobs.Start()
 }
 
@@ -216,6 +318,7 @@ func cleanUp() {
 	defer func() {
 		logger.Info("Cleaning up")
 		bgJobs.Stop()
+		jobListener.Close()
 		cfg.RunTime.DbConn.Close()
 		logger.Info("Done cleaning up")
 		cancel()
@@ -28,22 +28,55 @@ type AppConfig struct {
 		Mode string `envconfig:"GIN_MODE" default:"release"`
 	}
 	Db struct {
-		Username string `envconfig:"DB_USERNAME" required:"true"`
-		Password string `envconfig:"DB_PASSWORD" required:"true"`
-		Host     string `envconfig:"DB_HOST" required:"true"`
-		Port     int32  `envconfig:"DB_PORT" required:"true"`
-		Name     string `envconfig:"DB_NAME" required:"true"`
-		JobTable string `envconfig:"DB_TABLE" default:"joblist"`
+		Username         string `envconfig:"DB_USERNAME" required:"true"`
+		Password         string `envconfig:"DB_PASSWORD" required:"true"`
+		Host             string `envconfig:"DB_HOST" required:"true"`
+		Port             int32  `envconfig:"DB_PORT" required:"true"`
+		Name             string `envconfig:"DB_NAME" required:"true"`
+		JobTable         string `envconfig:"DB_TABLE" default:"joblist"`
+		DeadLetterTable  string `envconfig:"DB_DEAD_LETTER_TABLE" default:"joblist_deadletter"`
+		ScheduleTable    string `envconfig:"DB_SCHEDULE_TABLE" default:"schedules"`
+		WorkflowTable    string `envconfig:"DB_WORKFLOW_TABLE" default:"workflows"`
+		EventTable       string `envconfig:"DB_EVENT_TABLE" default:"job_events"`
+		JobTypeTable     string `envconfig:"DB_JOB_TYPE_TABLE" default:"job_types"`
+		JobTemplateTable string `envconfig:"DB_JOB_TEMPLATE_TABLE" default:"job_templates"`
+		IdempotencyTable string `envconfig:"DB_IDEMPOTENCY_TABLE" default:"idempotency_keys"`
+		NotifyChannel    string `envconfig:"DB_NOTIFY_CHANNEL" default:"jobsvc_job_created"`
 	}
 	Misc struct {
 		MaxResultLimit int      `envconfig:"MAX_RESULT_LIMIT" default:"100"`
 		ApiKeys        []string `envconfig:"API_KEYS"`
 	}
 	Cleanup struct {
-		CycleHours             int `envconfig:"CLEANUP_CYCLE_HOURS" default:"1"`
-		FailedRetentionDays    int `envconfig:"CLEANUP_FAILED_RETEN_DAYS" default:"2"`
-		SuccessRetentionDays   int `envconfig:"CLEANUP_SUCCESS_RETEN_DAYS" default:"1"`
-		InProgressWarningHours int `envconfig:"IN_PROGRESS_WARNING_HOURS" default:"6"`
+		CycleHours              int `envconfig:"CLEANUP_CYCLE_HOURS" default:"1"`
+		SuccessRetentionDays    int `envconfig:"CLEANUP_SUCCESS_RETEN_DAYS" default:"1"`
+		InProgressWarningHours  int `envconfig:"IN_PROGRESS_WARNING_HOURS" default:"6"`
+		DeadLetterRetentionDays int `envconfig:"CLEANUP_DEAD_LETTER_RETEN_DAYS" default:"30"`
+		CancelledRetentionDays  int `envconfig:"CLEANUP_CANCELLED_RETEN_DAYS" default:"2"`
+	}
+	Scheduling struct {
+		PriorityAgingPerMinute float64 `envconfig:"PRIORITY_AGING_PER_MINUTE" default:"0"`
+		ScheduleSyncSeconds    int     `envconfig:"SCHEDULE_SYNC_SECONDS" default:"60"`
+		WorkflowAdvanceSeconds int     `envconfig:"WORKFLOW_ADVANCE_SECONDS" default:"10"`
+	}
+	Dequeue struct {
+		MaxWaitSeconds      int `envconfig:"DEQUEUE_MAX_WAIT_SECONDS" default:"30"`
+		PollIntervalSeconds int `envconfig:"DEQUEUE_POLL_INTERVAL_SECONDS" default:"5"`
+		MaxCount            int `envconfig:"DEQUEUE_MAX_COUNT" default:"50"`
+	}
+	Progress struct {
+		HistoryIntervalSeconds int `envconfig:"PROGRESS_HISTORY_INTERVAL_SECONDS" default:"60"`
+	}
+	JobTypes struct {
+		Strict bool `envconfig:"JOB_TYPES_STRICT" default:"false"`
+	}
+	Idempotency struct {
+		WindowHours    int `envconfig:"IDEMPOTENCY_WINDOW_HOURS" default:"24"`
+		PendingSeconds int `envconfig:"IDEMPOTENCY_PENDING_SECONDS" default:"60"`
+	}
+	Lease struct {
+		DurationSeconds  int `envconfig:"LEASE_DURATION_SECONDS" default:"300"`
+		ReapCycleSeconds int `envconfig:"LEASE_REAP_CYCLE_SECONDS" default:"60"`
 	}
 	RunTime struct {
 		Router     *gin.Engine
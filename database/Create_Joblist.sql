@@ -1,4 +1,11 @@
-DROP TABLE joblist;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS job_templates;
+DROP TABLE IF EXISTS job_types;
+DROP TABLE IF EXISTS job_events;
+DROP TABLE IF EXISTS workflows;
+DROP TABLE IF EXISTS schedules;
+DROP TABLE IF EXISTS joblist_deadletter;
+DROP TABLE IF EXISTS joblist;
 
 CREATE TABLE joblist (
 	"id" varchar NOT NULL,
@@ -16,9 +23,113 @@ CREATE TABLE joblist (
 	"action" varchar NULL,
 	"action_details" varchar NULL,
 	"progress" int4 NULL,
-	"history" varchar NULL,
-	"extra_data" varchar NULL,
+	"extra_data" jsonb NULL,
 	"priority" int4 NULL,
 	"rank" int4 NULL,
+	"lease_owner" varchar NOT NULL DEFAULT '',
+	"lease_expires_at" timestamptz NULL,
+	"attempts" int4 NOT NULL DEFAULT 0,
+	"max_attempts" int4 NOT NULL DEFAULT 1,
+	"backoff_policy" varchar NOT NULL DEFAULT 'exponential',
+	"backoff_seconds" int4 NOT NULL DEFAULT 30,
+	"not_before" timestamptz NULL,
+	"run_at" timestamptz NULL,
+	"depends_on" varchar[] NOT NULL DEFAULT '{}',
+	"dependency_policy" varchar NOT NULL DEFAULT 'fail',
+	"parent_id" varchar NOT NULL DEFAULT '',
+	"cancel_requested" bool NOT NULL DEFAULT false,
+	"eta" timestamptz NULL,
+	"progress_message" varchar NOT NULL DEFAULT '',
+	"progress_logged_at" timestamptz NULL,
+	"result" jsonb NULL,
+	"error_code" varchar NOT NULL DEFAULT '',
+	"error_message" varchar NOT NULL DEFAULT '',
+	"error_retryable" bool NOT NULL DEFAULT false,
+	"labels" jsonb NOT NULL DEFAULT '{}',
+	"unique_key" varchar NOT NULL DEFAULT '',
 	CONSTRAINT joblist_pk PRIMARY KEY (id)
-);
\ No newline at end of file
+);
+
+CREATE INDEX joblist_dequeue_idx ON joblist ("type", status, priority DESC, "rank" DESC);
+CREATE INDEX joblist_depends_on_idx ON joblist USING GIN (depends_on);
+CREATE INDEX joblist_parent_id_idx ON joblist (parent_id);
+CREATE INDEX joblist_error_code_idx ON joblist (error_code);
+CREATE INDEX joblist_labels_idx ON joblist USING GIN (labels);
+CREATE UNIQUE INDEX joblist_unique_key_idx ON joblist (unique_key) WHERE unique_key <> '' AND status NOT IN ('finished', 'failed', 'cancelled');
+
+CREATE TABLE joblist_deadletter (LIKE joblist INCLUDING ALL);
+
+CREATE TABLE job_events (
+	"id" bigserial NOT NULL,
+	"job_id" varchar NOT NULL,
+	"created_at" timestamptz NOT NULL,
+	"event_type" varchar NOT NULL,
+	"actor" varchar NOT NULL DEFAULT '',
+	"message" varchar NOT NULL DEFAULT '',
+	"old_status" varchar NOT NULL DEFAULT '',
+	"new_status" varchar NOT NULL DEFAULT '',
+	CONSTRAINT job_events_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX job_events_job_id_idx ON job_events (job_id, id);
+
+CREATE TABLE schedules (
+	"id" varchar NOT NULL,
+	"name" varchar NULL,
+	"cron_expr" varchar NOT NULL,
+	"time_zone" varchar NOT NULL DEFAULT 'UTC',
+	"job_template" varchar NOT NULL,
+	"enabled" bool NOT NULL DEFAULT true,
+	"missed_run_policy" varchar NOT NULL DEFAULT 'skip',
+	"last_run_at" timestamptz NULL,
+	"next_run_at" timestamptz NULL,
+	"created_at" timestamptz NULL,
+	"modified_at" timestamptz NULL,
+	CONSTRAINT schedules_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE workflows (
+	"id" varchar NOT NULL,
+	"name" varchar NULL,
+	"correlation_id" varchar NULL,
+	"status" varchar NOT NULL,
+	"stages" varchar NOT NULL,
+	"current_stage" int4 NOT NULL DEFAULT 0,
+	"created_at" timestamptz NULL,
+	"modified_at" timestamptz NULL,
+	CONSTRAINT workflows_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX workflows_status_idx ON workflows (status);
+
+CREATE TABLE job_types (
+	"name" varchar NOT NULL,
+	"description" varchar NOT NULL DEFAULT '',
+	"sub_types" varchar[] NOT NULL DEFAULT '{}',
+	"actions" varchar[] NOT NULL DEFAULT '{}',
+	"required_fields" varchar[] NOT NULL DEFAULT '{}',
+	"unique_fields" varchar[] NOT NULL DEFAULT '{}',
+	"extra_data_schema" jsonb NULL,
+	"created_at" timestamptz NULL,
+	"modified_at" timestamptz NULL,
+	CONSTRAINT job_types_pk PRIMARY KEY (name)
+);
+
+CREATE TABLE job_templates (
+	"name" varchar NOT NULL,
+	"version" int4 NOT NULL,
+	"description" varchar NOT NULL DEFAULT '',
+	"job_template" jsonb NOT NULL,
+	"created_at" timestamptz NULL,
+	CONSTRAINT job_templates_pk PRIMARY KEY (name, version)
+);
+
+CREATE TABLE idempotency_keys (
+	"key" varchar NOT NULL,
+	"request_hash" varchar NOT NULL,
+	"status_code" int4 NOT NULL DEFAULT 0,
+	"response" jsonb NULL,
+	"created_at" timestamptz NOT NULL,
+	CONSTRAINT idempotency_keys_pk PRIMARY KEY (key)
+);
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
@@ -0,0 +1,6 @@
+BEGIN;
+
+INSERT INTO joblist_deadletter SELECT * FROM joblist WHERE status = 'failed' ON CONFLICT (id) DO NOTHING;
+DELETE FROM joblist WHERE status = 'failed';
+
+COMMIT;
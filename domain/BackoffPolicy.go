@@ -0,0 +1,20 @@
+package domain
+
+import "strings"
+
+type BackoffPolicy string
+
+const (
+	BackoffFixed       BackoffPolicy = "fixed"
+	BackoffExponential BackoffPolicy = "exponential"
+)
+
+func IsValidBackoffPolicy(policyVal string) bool {
+	val := strings.TrimSpace(strings.ToLower(policyVal))
+	if (val == string(BackoffFixed)) ||
+		(val == string(BackoffExponential)) {
+		return true
+	} else {
+		return false
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsValidBackoffPolicy_InvalidPolicy_Returns_False(t *testing.T) {
+	valid := IsValidBackoffPolicy("bogus")
+
+	assert.NotNil(t, valid)
+	assert.EqualValues(t, false, valid)
+}
+
+func Test_IsValidBackoffPolicy_ValidPolicy_Returns_True(t *testing.T) {
+	validPolicies := []string{"fixed", "exponential"}
+
+	for _, policy := range validPolicies {
+		valid := IsValidBackoffPolicy(policy)
+
+		assert.NotNil(t, valid)
+		assert.EqualValues(t, true, valid)
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "strings"
+
+type DependencyPolicy string
+
+type DependencyState string
+
+const (
+	DependencyFail  DependencyPolicy = "fail"
+	DependencyBlock DependencyPolicy = "block"
+)
+
+const (
+	DependencyWaiting   DependencyState = "waiting"
+	DependencySatisfied DependencyState = "satisfied"
+	DependencyBlocked   DependencyState = "blocked"
+	DependencyFailed    DependencyState = "failed"
+)
+
+func IsValidDependencyPolicy(policyVal string) bool {
+	val := strings.TrimSpace(strings.ToLower(policyVal))
+	if (val == string(DependencyFail)) ||
+		(val == string(DependencyBlock)) {
+		return true
+	} else {
+		return false
+	}
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsValidDependencyPolicy_InvalidPolicy_Returns_False(t *testing.T) {
+	valid := IsValidDependencyPolicy("bogus")
+
+	assert.NotNil(t, valid)
+	assert.EqualValues(t, false, valid)
+}
+
+func Test_IsValidDependencyPolicy_ValidPolicy_Returns_True(t *testing.T) {
+	validPolicies := []string{"fail", "block"}
+
+	for _, policy := range validPolicies {
+		valid := IsValidDependencyPolicy(policy)
+
+		assert.NotNil(t, valid)
+		assert.EqualValues(t, true, valid)
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+)
+
+const (
+	MaxIdempotencyKeyLength int = 255
+)
+
+type IdempotencyKey struct {
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	StatusCode  int       `db:"status_code"`
+	Response    *string   `db:"response"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+//go:generate mockgen -destination=../mocks/domain/mockIdempotencyKeyRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain IdempotencyKeyRepository
+type IdempotencyKeyRepository interface {
+	Store(IdempotencyKey) api_error.ApiErr
+	FindByKey(string) (*IdempotencyKey, api_error.ApiErr)
+	Complete(IdempotencyKey) api_error.ApiErr
+	DeleteByKey(string) api_error.ApiErr
+	DeleteExpired(time.Time) api_error.ApiErr
+}
+
+func NewIdempotencyKey(key string, jobReq dto.CreateUpdateJobRequest) IdempotencyKey {
+	return IdempotencyKey{
+		Key:         key,
+		RequestHash: HashJobRequest(jobReq),
+		CreatedAt:   date.GetNowUtc(),
+	}
+}
+
+func HashJobRequest(jobReq dto.CreateUpdateJobRequest) string {
+	reqJson, _ := json.Marshal(jobReq)
+	hash := sha256.Sum256(reqJson)
+	return hex.EncodeToString(hash[:])
+}
+
+func (k *IdempotencyKey) SetResponse(statusCode int, jobResp dto.JobResponse) {
+	respJson, _ := json.Marshal(jobResp)
+	response := string(respJson)
+	k.StatusCode = statusCode
+	k.Response = &response
+}
+
+func (k IdempotencyKey) IsCompleted() bool {
+	return k.Response != nil
+}
+
+func (k IdempotencyKey) IsExpired(window time.Duration) bool {
+	return k.CreatedAt.Add(window).Before(date.GetNowUtc())
+}
+
+func (k IdempotencyKey) ToIdempotentJobResponseDto() (*dto.IdempotentJobResponse, api_error.ApiErr) {
+	var jobResp dto.JobResponse
+	if err := json.Unmarshal([]byte(*k.Response), &jobResp); err != nil {
+		return nil, api_error.NewInternalServerError("Could not read stored response for idempotency key", err)
+	}
+	return &dto.IdempotentJobResponse{
+		StatusCode: k.StatusCode,
+		Job:        jobResp,
+	}, nil
+}
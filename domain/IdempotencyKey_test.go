@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HashJobRequest_SameRequest_Returns_SameHash(t *testing.T) {
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding", Labels: map[string]string{"a": "1", "b": "2"}}
+
+	assert.EqualValues(t, HashJobRequest(jobReq), HashJobRequest(jobReq))
+}
+
+func Test_HashJobRequest_DifferentRequest_Returns_DifferentHash(t *testing.T) {
+	jobReq1 := dto.CreateUpdateJobRequest{Type: "encoding", Source: "a.mxf"}
+	jobReq2 := dto.CreateUpdateJobRequest{Type: "encoding", Source: "b.mxf"}
+
+	assert.NotEqualValues(t, HashJobRequest(jobReq1), HashJobRequest(jobReq2))
+}
+
+func Test_IsExpired_OldKey_Returns_True(t *testing.T) {
+	idemKey := IdempotencyKey{CreatedAt: date.GetNowUtc().Add(-25 * time.Hour)}
+
+	assert.True(t, idemKey.IsExpired(24*time.Hour))
+}
+
+func Test_IsExpired_NewKey_Returns_False(t *testing.T) {
+	idemKey := NewIdempotencyKey("abc", dto.CreateUpdateJobRequest{})
+
+	assert.False(t, idemKey.IsExpired(24*time.Hour))
+	assert.False(t, idemKey.IsCompleted())
+}
+
+func Test_ToIdempotentJobResponseDto_Returns_StoredResponse(t *testing.T) {
+	idemKey := NewIdempotencyKey("abc", dto.CreateUpdateJobRequest{})
+	idemKey.SetResponse(http.StatusCreated, dto.JobResponse{Id: "2G7VHsD6jkOXq2RmBAbVE1bBqIW", Type: "encoding"})
+
+	resp, err := idemKey.ToIdempotentJobResponseDto()
+
+	assert.Nil(t, err)
+	assert.True(t, idemKey.IsCompleted())
+	assert.EqualValues(t, http.StatusCreated, resp.StatusCode)
+	assert.EqualValues(t, "2G7VHsD6jkOXq2RmBAbVE1bBqIW", resp.Job.Id)
+	assert.EqualValues(t, "encoding", resp.Job.Type)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+)
+
+type JobEventType string
+
+const (
+	EventCreated       JobEventType = "created"
+	EventStatusChanged JobEventType = "status_changed"
+	EventProgress      JobEventType = "progress"
+	EventNote          JobEventType = "note"
+)
+
+const (
+	ActorSystem string = "system"
+	ActorApi    string = "api"
+)
+
+type JobEvent struct {
+	Id        int64        `db:"id"`
+	JobId     string       `db:"job_id"`
+	CreatedAt time.Time    `db:"created_at"`
+	EventType JobEventType `db:"event_type"`
+	Actor     string       `db:"actor"`
+	Message   string       `db:"message"`
+	OldStatus string       `db:"old_status"`
+	NewStatus string       `db:"new_status"`
+}
+
+func (e *JobEvent) Render() string {
+	return fmt.Sprintf("%v: %v\n", e.CreatedAt.UTC().Format(time.RFC3339), e.Message)
+}
+
+func RenderHistory(events []JobEvent) string {
+	var sb strings.Builder
+	for _, event := range events {
+		sb.WriteString(event.Render())
+	}
+	return sb.String()
+}
+
+func (e *JobEvent) ToJobEventResponseDto() dto.JobEventResponse {
+	return dto.JobEventResponse{
+		Id:        e.Id,
+		JobId:     e.JobId,
+		CreatedAt: e.CreatedAt,
+		EventType: string(e.EventType),
+		Actor:     e.Actor,
+		Message:   e.Message,
+		OldStatus: e.OldStatus,
+		NewStatus: e.NewStatus,
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderHistory_NoEvents_Returns_EmptyString(t *testing.T) {
+	history := RenderHistory([]JobEvent{})
+
+	assert.EqualValues(t, "", history)
+}
+
+func Test_RenderHistory_WithEvents_Returns_RenderedLines(t *testing.T) {
+	events := []JobEvent{
+		{CreatedAt: time.Date(2022, 1, 5, 6, 7, 55, 0, time.UTC), EventType: EventCreated, Message: "Job created"},
+		{CreatedAt: time.Date(2022, 1, 5, 6, 8, 0, 0, time.UTC), EventType: EventStatusChanged, Message: "Dequeuing job for processing"},
+	}
+
+	history := RenderHistory(events)
+
+	assert.EqualValues(t, "2022-01-05T06:07:55Z: Job created\n2022-01-05T06:08:00Z: Dequeuing job for processing\n", history)
+}
+
+func Test_ToJobEventResponseDto_Returns_Dto(t *testing.T) {
+	event := JobEvent{
+		Id:        12,
+		JobId:     "23GaSImHjnOuKwdxYGP9fY8KmPC",
+		CreatedAt: time.Date(2022, 1, 5, 6, 7, 55, 0, time.UTC),
+		EventType: EventStatusChanged,
+		Actor:     "worker 1",
+		Message:   "Encoding done",
+		OldStatus: "running",
+		NewStatus: "finished",
+	}
+
+	eventResp := event.ToJobEventResponseDto()
+
+	assert.EqualValues(t, event.Id, eventResp.Id)
+	assert.EqualValues(t, event.JobId, eventResp.JobId)
+	assert.EqualValues(t, event.CreatedAt, eventResp.CreatedAt)
+	assert.EqualValues(t, "status_changed", eventResp.EventType)
+	assert.EqualValues(t, event.Actor, eventResp.Actor)
+	assert.EqualValues(t, event.Message, eventResp.Message)
+	assert.EqualValues(t, event.OldStatus, eventResp.OldStatus)
+	assert.EqualValues(t, event.NewStatus, eventResp.NewStatus)
+}
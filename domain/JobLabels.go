@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type JobLabels map[string]string
+
+func (l JobLabels) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	labelsJson, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(labelsJson), nil
+}
+
+func (l *JobLabels) Scan(src interface{}) error {
+	var labelsJson []byte
+	switch val := src.(type) {
+	case nil:
+		*l = JobLabels{}
+		return nil
+	case []byte:
+		labelsJson = val
+	case string:
+		labelsJson = []byte(val)
+	default:
+		return fmt.Errorf("cannot scan %T into job labels", src)
+	}
+	labels := JobLabels{}
+	if err := json.Unmarshal(labelsJson, &labels); err != nil {
+		return err
+	}
+	*l = labels
+	return nil
+}
+
+func (l JobLabels) SortedKeys() []string {
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (l JobLabels) String() string {
+	pairs := make([]string, 0, len(l))
+	for _, key := range l.SortedKeys() {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", key, l[key]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func IsValidLabelKey(key string) bool {
+	return keyRegex.MatchString(key)
+}
+
+func SplitLabelFilter(key string) (string, bool) {
+	labelKey, found := strings.CutPrefix(key, "label.")
+	if !found || !IsValidLabelKey(labelKey) {
+		return "", false
+	}
+	return labelKey, true
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JobLabels_Value_Returns_Json(t *testing.T) {
+	labels := JobLabels{"region": "eu", "customer": "acme"}
+
+	val, err := labels.Value()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, `{"customer":"acme","region":"eu"}`, val)
+}
+
+func Test_JobLabels_NilValue_Returns_EmptyObject(t *testing.T) {
+	var labels JobLabels
+
+	val, err := labels.Value()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "{}", val)
+}
+
+func Test_JobLabels_Scan_Returns_Labels(t *testing.T) {
+	var labels JobLabels
+
+	err := labels.Scan([]byte(`{"customer":"acme","region":"eu"}`))
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, JobLabels{"region": "eu", "customer": "acme"}, labels)
+}
+
+func Test_JobLabels_ScanNil_Returns_EmptyLabels(t *testing.T) {
+	labels := JobLabels{"region": "eu"}
+
+	err := labels.Scan(nil)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(labels))
+}
+
+func Test_JobLabels_ScanWrongType_Returns_Error(t *testing.T) {
+	var labels JobLabels
+
+	err := labels.Scan(42)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "cannot scan int into job labels", err.Error())
+}
+
+func Test_JobLabels_String_Returns_SortedPairs(t *testing.T) {
+	labels := JobLabels{"region": "eu", "customer": "acme"}
+
+	assert.EqualValues(t, "customer=acme,region=eu", labels.String())
+}
+
+func Test_SplitLabelFilter_Returns_LabelKey(t *testing.T) {
+	key, ok := SplitLabelFilter("label.customer")
+
+	assert.True(t, ok)
+	assert.EqualValues(t, "customer", key)
+}
+
+func Test_SplitLabelFilter_InvalidKey_Returns_NotOk(t *testing.T) {
+	for _, key := range []string{"customer", "label.", "label.cust'omer", "labels.customer"} {
+		_, ok := SplitLabelFilter(key)
+
+		assert.False(t, ok, key)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 const (
 	DefaultJobPriority int32 = 30
+	MaxJobPriority     int32 = 50
 )
 
 var (
@@ -1,28 +1,89 @@
 package domain
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+)
 
 type JobStatus string
 
 const (
-	StatusCreated  JobStatus = "created"
-	StatusQueued   JobStatus = "queued"
-	StatusRunning  JobStatus = "running"
-	StatusPaused   JobStatus = "paused"
-	StatusFinished JobStatus = "finished"
-	StatusFailed   JobStatus = "failed"
+	StatusCreated   JobStatus = "created"
+	StatusScheduled JobStatus = "scheduled"
+	StatusQueued    JobStatus = "queued"
+	StatusRunning   JobStatus = "running"
+	StatusPaused    JobStatus = "paused"
+	StatusFinished  JobStatus = "finished"
+	StatusFailed    JobStatus = "failed"
+	StatusCancelled JobStatus = "cancelled"
+)
+
+var (
+	jobStatusOrder = []JobStatus{
+		StatusCreated,
+		StatusScheduled,
+		StatusQueued,
+		StatusRunning,
+		StatusPaused,
+		StatusFinished,
+		StatusFailed,
+		StatusCancelled,
+	}
+	jobStatusTransitions = map[JobStatus][]JobStatus{
+		StatusCreated:   {StatusScheduled, StatusQueued, StatusRunning, StatusPaused, StatusCancelled},
+		StatusScheduled: {StatusCreated, StatusQueued, StatusRunning, StatusPaused, StatusCancelled},
+		StatusQueued:    {StatusCreated, StatusRunning, StatusPaused, StatusCancelled},
+		StatusRunning:   {StatusRunning, StatusPaused, StatusFinished, StatusFailed, StatusCancelled},
+		StatusPaused:    {StatusCreated, StatusQueued, StatusRunning, StatusFailed, StatusCancelled},
+		StatusFinished:  {},
+		StatusFailed:    {},
+		StatusCancelled: {},
+	}
 )
 
 func IsValidJobStatus(statusVal string) bool {
 	val := strings.TrimSpace(strings.ToLower(statusVal))
 	if (val == string(StatusCreated)) ||
+		(val == string(StatusScheduled)) ||
 		(val == string(StatusQueued)) ||
 		(val == string(StatusRunning)) ||
 		(val == string(StatusPaused)) ||
 		(val == string(StatusFinished)) ||
-		(val == string(StatusFailed)) {
+		(val == string(StatusFailed)) ||
+		(val == string(StatusCancelled)) {
 		return true
 	} else {
 		return false
 	}
 }
+
+func IsValidTransition(from JobStatus, to JobStatus) bool {
+	for _, next := range jobStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func IsTerminalStatus(status JobStatus) bool {
+	next, found := jobStatusTransitions[status]
+	return found && len(next) == 0
+}
+
+func StatusTransitionsToDto() []dto.StatusTransitionResponse {
+	transitions := make([]dto.StatusTransitionResponse, 0)
+	for _, status := range jobStatusOrder {
+		next := make([]string, 0)
+		for _, nextStatus := range jobStatusTransitions[status] {
+			next = append(next, string(nextStatus))
+		}
+		transitions = append(transitions, dto.StatusTransitionResponse{
+			Status:   string(status),
+			Next:     next,
+			Terminal: IsTerminalStatus(status),
+		})
+	}
+	return transitions
+}
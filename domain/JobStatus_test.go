@@ -14,7 +14,7 @@ func Test_IsValidJobStatus_InvalidStatus_Returns_False(t *testing.T) {
 }
 
 func Test_IsValidJobStatus_ValidStatus_Returns_True(t *testing.T) {
-	validStatus := []string{"created", "queued", "running", "paused", "finished", "failed"}
+	validStatus := []string{"created", "scheduled", "queued", "running", "paused", "finished", "failed", "cancelled"}
 
 	for _, status := range validStatus {
 		valid := IsValidJobStatus(status)
@@ -24,3 +24,26 @@ func Test_IsValidJobStatus_ValidStatus_Returns_True(t *testing.T) {
 	}
 
 }
+
+func Test_IsValidTransition_InvalidTransition_Returns_False(t *testing.T) {
+	assert.EqualValues(t, false, IsValidTransition(StatusFinished, StatusCreated))
+	assert.EqualValues(t, false, IsValidTransition(StatusCreated, StatusFinished))
+}
+
+func Test_IsValidTransition_ValidTransition_Returns_True(t *testing.T) {
+	assert.EqualValues(t, true, IsValidTransition(StatusCreated, StatusQueued))
+	assert.EqualValues(t, true, IsValidTransition(StatusQueued, StatusRunning))
+	assert.EqualValues(t, true, IsValidTransition(StatusRunning, StatusPaused))
+	assert.EqualValues(t, true, IsValidTransition(StatusRunning, StatusFinished))
+}
+
+func Test_StatusTransitionsToDto_Returns_AllStatuses(t *testing.T) {
+	transitions := StatusTransitionsToDto()
+
+	assert.EqualValues(t, 8, len(transitions))
+	assert.EqualValues(t, "created", transitions[0].Status)
+	assert.EqualValues(t, false, transitions[0].Terminal)
+	assert.EqualValues(t, "finished", transitions[5].Status)
+	assert.EqualValues(t, true, transitions[5].Terminal)
+	assert.EqualValues(t, []string{}, transitions[5].Next)
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+)
+
+type JobTemplate struct {
+	Name        string    `db:"name"`
+	Version     int32     `db:"version"`
+	Description string    `db:"description"`
+	JobTemplate string    `db:"job_template"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+//go:generate mockgen -destination=../mocks/domain/mockJobTemplateRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain JobTemplateRepository
+type JobTemplateRepository interface {
+	Store(JobTemplate) api_error.ApiErr
+	FindAll() (*[]JobTemplate, api_error.ApiErr)
+	FindByName(string, int32) (*JobTemplate, api_error.ApiErr)
+	FindVersions(string) (*[]JobTemplate, api_error.ApiErr)
+	DeleteByName(string) api_error.ApiErr
+}
+
+func NewJobTemplateFromRequestDto(tmplReq dto.CreateUpdateJobTemplateRequest) (*JobTemplate, api_error.ApiErr) {
+	if !IsValidJobTemplateName(tmplReq.Name) {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("Job template name %v is not valid. Must only contain letters, digits, underscores or hyphens", tmplReq.Name))
+	}
+	if tmplReq.Job == nil {
+		return nil, api_error.NewBadRequestError("Job template must have a job")
+	}
+	newTemplate := JobTemplate{
+		Name:        tmplReq.Name,
+		Version:     1,
+		Description: tmplReq.Description,
+	}
+	err := newTemplate.setJobRequest(*tmplReq.Job)
+	if err != nil {
+		return nil, err
+	}
+	return &newTemplate, nil
+}
+
+func (t *JobTemplate) NextVersion(tmplReq dto.CreateUpdateJobTemplateRequest) (*JobTemplate, api_error.ApiErr) {
+	jobReq, err := t.JobRequest()
+	if err != nil {
+		return nil, err
+	}
+	nextTemplate := JobTemplate{
+		Name:        t.Name,
+		Version:     t.Version + 1,
+		Description: t.Description,
+	}
+	if tmplReq.Description != "" {
+		nextTemplate.Description = tmplReq.Description
+	}
+	if tmplReq.Job != nil {
+		*jobReq = MergeJobRequests(*jobReq, *tmplReq.Job)
+	}
+	err = nextTemplate.setJobRequest(*jobReq)
+	if err != nil {
+		return nil, err
+	}
+	return &nextTemplate, nil
+}
+
+func (t *JobTemplate) setJobRequest(jobReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+	if jobReq.Template != "" || jobReq.TemplateVersion != 0 {
+		return api_error.NewBadRequestError("Job template cannot reference another template")
+	}
+	template, err := json.Marshal(jobReq)
+	if err != nil {
+		return api_error.NewBadRequestError("Could not store job of template")
+	}
+	t.JobTemplate = string(template)
+	t.CreatedAt = date.GetNowUtc()
+	return nil
+}
+
+func (t *JobTemplate) JobRequest() (*dto.CreateUpdateJobRequest, api_error.ApiErr) {
+	var jobReq dto.CreateUpdateJobRequest
+	err := json.Unmarshal([]byte(t.JobTemplate), &jobReq)
+	if err != nil {
+		return nil, api_error.NewInternalServerError(fmt.Sprintf("Could not read job of template %v version %d", t.Name, t.Version), err)
+	}
+	return &jobReq, nil
+}
+
+func (t *JobTemplate) Apply(overrides dto.CreateUpdateJobRequest) (*dto.CreateUpdateJobRequest, api_error.ApiErr) {
+	jobReq, err := t.JobRequest()
+	if err != nil {
+		return nil, err
+	}
+	merged := MergeJobRequests(*jobReq, overrides)
+	merged.Template = t.Name
+	merged.TemplateVersion = t.Version
+	return &merged, nil
+}
+
+func (t *JobTemplate) ToJobTemplateResponseDto() dto.JobTemplateResponse {
+	var jobReq dto.CreateUpdateJobRequest
+	json.Unmarshal([]byte(t.JobTemplate), &jobReq)
+	return dto.JobTemplateResponse{
+		Name:        t.Name,
+		Version:     t.Version,
+		Description: t.Description,
+		Job:         jobReq,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+func IsValidJobTemplateName(name string) bool {
+	return keyRegex.MatchString(name)
+}
+
+func MergeJobRequests(base dto.CreateUpdateJobRequest, overrides dto.CreateUpdateJobRequest) dto.CreateUpdateJobRequest {
+	merged, _ := MergeJobRequestChanges(base, overrides)
+	return merged
+}
+
+// MergeJobRequestChanges applies every field set in overrides to base and reports the fields whose value changed.
+func MergeJobRequestChanges(base dto.CreateUpdateJobRequest, overrides dto.CreateUpdateJobRequest) (dto.CreateUpdateJobRequest, map[string]string) {
+	merged := base
+	changed := make(map[string]string)
+	mergeString := func(field string, target *string, val string) {
+		if val == "" {
+			return
+		}
+		if val != *target {
+			changed[field] = val
+		}
+		*target = val
+	}
+	mergeInt := func(field string, target *int32, val int32) {
+		if val == 0 {
+			return
+		}
+		if val != *target {
+			changed[field] = fmt.Sprintf("%v", val)
+		}
+		*target = val
+	}
+	mergeString("CorrelationId", &merged.CorrelationId, overrides.CorrelationId)
+	mergeString("Name", &merged.Name, overrides.Name)
+	mergeString("Source", &merged.Source, overrides.Source)
+	mergeString("Destination", &merged.Destination, overrides.Destination)
+	mergeString("Type", &merged.Type, overrides.Type)
+	mergeString("SubType", &merged.SubType, overrides.SubType)
+	mergeString("Action", &merged.Action, overrides.Action)
+	mergeString("ActionDetails", &merged.ActionDetails, overrides.ActionDetails)
+	if overrides.HasExtraData() {
+		if string(overrides.ExtraData) != string(merged.ExtraData) {
+			changed["ExtraData"] = string(overrides.ExtraData)
+		}
+		merged.ExtraData = overrides.ExtraData
+	}
+	mergeString("Priority", &merged.Priority, overrides.Priority)
+	mergeInt("Rank", &merged.Rank, overrides.Rank)
+	mergeInt("MaxAttempts", &merged.MaxAttempts, overrides.MaxAttempts)
+	mergeString("BackoffPolicy", &merged.BackoffPolicy, overrides.BackoffPolicy)
+	mergeInt("BackoffSeconds", &merged.BackoffSeconds, overrides.BackoffSeconds)
+	mergeString("RunAt", &merged.RunAt, overrides.RunAt)
+	if len(overrides.DependsOn) > 0 {
+		if strings.Join(overrides.DependsOn, ",") != strings.Join(merged.DependsOn, ",") {
+			changed["DependsOn"] = strings.Join(overrides.DependsOn, ",")
+		}
+		merged.DependsOn = overrides.DependsOn
+	}
+	mergeString("DependencyPolicy", &merged.DependencyPolicy, overrides.DependencyPolicy)
+	if len(overrides.Labels) > 0 {
+		if JobLabels(overrides.Labels).String() != JobLabels(merged.Labels).String() {
+			changed["Labels"] = JobLabels(overrides.Labels).String()
+		}
+		merged.Labels = overrides.Labels
+	}
+	mergeString("UniqueKey", &merged.UniqueKey, overrides.UniqueKey)
+	mergeString("UniquePolicy", &merged.UniquePolicy, overrides.UniquePolicy)
+	return merged, changed
+}
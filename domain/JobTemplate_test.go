@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestJobTemplate() *JobTemplate {
+	template, _ := NewJobTemplateFromRequestDto(dto.CreateUpdateJobTemplateRequest{
+		Name: "encode_hd",
+		Job: &dto.CreateUpdateJobRequest{
+			Type:      "encoding",
+			SubType:   "h264",
+			Priority:  "high",
+			ExtraData: json.RawMessage(`{"resolution":"1080p"}`),
+			Labels:    map[string]string{"team": "media"},
+		},
+	})
+	return template
+}
+
+func Test_NewJobTemplateFromRequestDto_NoJob_Returns_BadRequestError(t *testing.T) {
+	template, err := NewJobTemplateFromRequestDto(dto.CreateUpdateJobTemplateRequest{Name: "encode_hd"})
+
+	assert.Nil(t, template)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Job template must have a job", err.Message())
+}
+
+func Test_NewJobTemplateFromRequestDto_NestedTemplate_Returns_BadRequestError(t *testing.T) {
+	template, err := NewJobTemplateFromRequestDto(dto.CreateUpdateJobTemplateRequest{
+		Name: "encode_hd",
+		Job:  &dto.CreateUpdateJobRequest{Template: "encode_sd"},
+	})
+
+	assert.Nil(t, template)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Job template cannot reference another template", err.Message())
+}
+
+func Test_NewJobTemplateFromRequestDto_ValidValues_Returns_FirstVersion(t *testing.T) {
+	template := newTestJobTemplate()
+
+	assert.NotNil(t, template)
+	assert.EqualValues(t, "encode_hd", template.Name)
+	assert.EqualValues(t, 1, template.Version)
+	assert.Contains(t, template.JobTemplate, `"type":"encoding"`)
+}
+
+func Test_NextVersion_MergesJob_Returns_NewVersion(t *testing.T) {
+	template := newTestJobTemplate()
+
+	next, err := template.NextVersion(dto.CreateUpdateJobTemplateRequest{
+		Description: "Encode to HD",
+		Job:         &dto.CreateUpdateJobRequest{Priority: "low"},
+	})
+	jobReq, _ := next.JobRequest()
+	oldReq, _ := template.JobRequest()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, next.Version)
+	assert.EqualValues(t, "Encode to HD", next.Description)
+	assert.EqualValues(t, "low", jobReq.Priority)
+	assert.EqualValues(t, "h264", jobReq.SubType)
+	assert.EqualValues(t, "high", oldReq.Priority)
+}
+
+func Test_Apply_Overrides_Returns_MergedRequest(t *testing.T) {
+	template := newTestJobTemplate()
+
+	jobReq, err := template.Apply(dto.CreateUpdateJobRequest{
+		Template: "encode_hd",
+		Name:     "Trailer",
+		SubType:  "hevc",
+	})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encoding", jobReq.Type)
+	assert.EqualValues(t, "hevc", jobReq.SubType)
+	assert.EqualValues(t, "Trailer", jobReq.Name)
+	assert.EqualValues(t, "high", jobReq.Priority)
+	assert.JSONEq(t, `{"resolution":"1080p"}`, string(jobReq.ExtraData))
+	assert.EqualValues(t, map[string]string{"team": "media"}, jobReq.Labels)
+	assert.EqualValues(t, "encode_hd", jobReq.Template)
+	assert.EqualValues(t, 1, jobReq.TemplateVersion)
+}
+
+func Test_MergeJobRequests_EmptyOverrides_Returns_Base(t *testing.T) {
+	base := dto.CreateUpdateJobRequest{Type: "encoding", Rank: 5, DependsOn: []string{"a"}}
+
+	merged := MergeJobRequests(base, dto.CreateUpdateJobRequest{})
+
+	assert.EqualValues(t, base, merged)
+}
+
+func Test_ToJobTemplateResponseDto_Returns_Job(t *testing.T) {
+	template := newTestJobTemplate()
+
+	response := template.ToJobTemplateResponseDto()
+
+	assert.EqualValues(t, "encode_hd", response.Name)
+	assert.EqualValues(t, 1, response.Version)
+	assert.EqualValues(t, "encoding", response.Job.Type)
+}
+
+func Test_MergeJobRequestChanges_Reports_Only_Changed_Fields(t *testing.T) {
+	base := dto.CreateUpdateJobRequest{Name: "base", Type: "encoding", Rank: 3, UniqueKey: "key-1"}
+	overrides := dto.CreateUpdateJobRequest{Name: "override", Rank: 5, UniqueKey: "key-1", Labels: map[string]string{"region": "eu"}}
+
+	merged, changed := MergeJobRequestChanges(base, overrides)
+
+	assert.EqualValues(t, "override", merged.Name)
+	assert.EqualValues(t, "encoding", merged.Type)
+	assert.EqualValues(t, 5, merged.Rank)
+	assert.EqualValues(t, map[string]string{"Name": "override", "Rank": "5", "Labels": "region=eu"}, changed)
+}
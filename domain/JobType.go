@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+	"github.com/lib/pq"
+)
+
+type JobType struct {
+	Name            string         `db:"name"`
+	Description     string         `db:"description"`
+	SubTypes        pq.StringArray `db:"sub_types"`
+	Actions         pq.StringArray `db:"actions"`
+	RequiredFields  pq.StringArray `db:"required_fields"`
+	UniqueFields    pq.StringArray `db:"unique_fields"`
+	ExtraDataSchema *string        `db:"extra_data_schema"`
+	CreatedAt       time.Time      `db:"created_at"`
+	ModifiedAt      time.Time      `db:"modified_at"`
+}
+
+//go:generate mockgen -destination=../mocks/domain/mockJobTypeRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain JobTypeRepository
+type JobTypeRepository interface {
+	Store(JobType) api_error.ApiErr
+	FindAll() (*[]JobType, api_error.ApiErr)
+	FindByName(string) (*JobType, api_error.ApiErr)
+	Update(JobType) api_error.ApiErr
+	DeleteByName(string) api_error.ApiErr
+}
+
+func NewJobTypeFromRequestDto(typeReq dto.CreateUpdateJobTypeRequest) (*JobType, api_error.ApiErr) {
+	if !IsValidJobTypeName(typeReq.Name) {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("Job type name %v is not valid. Must only contain letters, digits, underscores or hyphens", typeReq.Name))
+	}
+	now := date.GetNowUtc()
+	newType := JobType{
+		Name:           typeReq.Name,
+		SubTypes:       pq.StringArray{},
+		Actions:        pq.StringArray{},
+		RequiredFields: pq.StringArray{},
+		UniqueFields:   pq.StringArray{},
+		CreatedAt:      now,
+		ModifiedAt:     now,
+	}
+	err := newType.ApplyRequest(typeReq)
+	if err != nil {
+		return nil, err
+	}
+	return &newType, nil
+}
+
+func (jt *JobType) ApplyRequest(typeReq dto.CreateUpdateJobTypeRequest) api_error.ApiErr {
+	if typeReq.Description != "" {
+		jt.Description = typeReq.Description
+	}
+	if typeReq.SubTypes != nil {
+		jt.SubTypes = pq.StringArray(typeReq.SubTypes)
+	}
+	if typeReq.Actions != nil {
+		jt.Actions = pq.StringArray(typeReq.Actions)
+	}
+	if typeReq.RequiredFields != nil {
+		for _, field := range typeReq.RequiredFields {
+			if !misc.SliceContainsString(GetJobRequestFieldsAsStrings(), field) {
+				return api_error.NewBadRequestError(fmt.Sprintf("Required field %v does not exist", field))
+			}
+		}
+		jt.RequiredFields = pq.StringArray(typeReq.RequiredFields)
+	}
+	if typeReq.UniqueFields != nil {
+		for _, field := range typeReq.UniqueFields {
+			if !misc.SliceContainsString(GetJobRequestFieldsAsStrings(), field) {
+				return api_error.NewBadRequestError(fmt.Sprintf("Unique field %v does not exist", field))
+			}
+		}
+		jt.UniqueFields = pq.StringArray(typeReq.UniqueFields)
+	}
+	if len(typeReq.ExtraDataSchema) > 0 {
+		if string(typeReq.ExtraDataSchema) == "null" {
+			jt.ExtraDataSchema = nil
+		} else {
+			if _, err := ParseJsonSchema(typeReq.ExtraDataSchema); err != nil {
+				return api_error.NewBadRequestError(fmt.Sprintf("Extra data schema is not valid: %v", err.Error()))
+			}
+			schema := string(typeReq.ExtraDataSchema)
+			jt.ExtraDataSchema = &schema
+		}
+	}
+	jt.ModifiedAt = date.GetNowUtc()
+	return nil
+}
+
+func (jt *JobType) ValidateJobRequest(jobReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+	if len(jt.SubTypes) > 0 && jobReq.SubType != "" && !misc.SliceContainsString(jt.SubTypes, jobReq.SubType) {
+		return api_error.NewBadRequestError(fmt.Sprintf("Sub type %v is not allowed for job type %v", jobReq.SubType, jt.Name))
+	}
+	if len(jt.Actions) > 0 && jobReq.Action != "" && !misc.SliceContainsString(jt.Actions, jobReq.Action) {
+		return api_error.NewBadRequestError(fmt.Sprintf("Action %v is not allowed for job type %v", jobReq.Action, jt.Name))
+	}
+	val := reflect.ValueOf(jobReq)
+	for idx := 0; idx < val.Type().NumField(); idx++ {
+		field := jobRequestFieldName(val.Type().Field(idx))
+		if misc.SliceContainsString(jt.RequiredFields, field) && val.Field(idx).IsZero() {
+			return api_error.NewBadRequestError(fmt.Sprintf("Field %v is required for job type %v", field, jt.Name))
+		}
+	}
+	if jt.ExtraDataSchema != nil && jobReq.HasExtraData() {
+		schema, err := ParseJsonSchema([]byte(*jt.ExtraDataSchema))
+		if err != nil {
+			return api_error.NewInternalServerError(fmt.Sprintf("Extra data schema of job type %v is not valid", jt.Name), err)
+		}
+		if err := schema.Validate(jobReq.ExtraData); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Extra data does not match schema of job type %v: %v", jt.Name, err.Error()))
+		}
+	}
+	return nil
+}
+
+func (jt *JobType) UniqueKey(jobReq dto.CreateUpdateJobRequest) string {
+	if len(jt.UniqueFields) == 0 {
+		return ""
+	}
+	values := make(map[string]string)
+	val := reflect.ValueOf(jobReq)
+	for idx := 0; idx < val.Type().NumField(); idx++ {
+		fieldJson, _ := json.Marshal(val.Field(idx).Interface())
+		values[jobRequestFieldName(val.Type().Field(idx))] = string(fieldJson)
+	}
+	parts := make([]string, 0, len(jt.UniqueFields))
+	for _, field := range jt.UniqueFields {
+		parts = append(parts, fmt.Sprintf("%v=%v", field, values[field]))
+	}
+	return strings.Join(parts, "|")
+}
+
+func (jt *JobType) ToJobTypeResponseDto() dto.JobTypeResponse {
+	return dto.JobTypeResponse{
+		Name:            jt.Name,
+		Description:     jt.Description,
+		SubTypes:        jt.SubTypes,
+		Actions:         jt.Actions,
+		RequiredFields:  jt.RequiredFields,
+		UniqueFields:    jt.UniqueFields,
+		ExtraDataSchema: jsonValue(jt.ExtraDataSchema),
+		CreatedAt:       jt.CreatedAt,
+		ModifiedAt:      jt.ModifiedAt,
+	}
+}
+
+func IsValidJobTypeName(name string) bool {
+	return keyRegex.MatchString(name)
+}
+
+func GetJobRequestFieldsAsStrings() []string {
+	var fields []string
+	val := reflect.ValueOf(dto.CreateUpdateJobRequest{})
+	for i := 0; i < val.Type().NumField(); i++ {
+		fields = append(fields, jobRequestFieldName(val.Type().Field(i)))
+	}
+	return fields
+}
+
+func jobRequestFieldName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewJobTypeFromRequestDto_InvalidName_Returns_BadRequestError(t *testing.T) {
+	jobType, err := NewJobTypeFromRequestDto(dto.CreateUpdateJobTypeRequest{Name: "media encoding"})
+
+	assert.Nil(t, jobType)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Job type name media encoding is not valid. Must only contain letters, digits, underscores or hyphens", err.Message())
+}
+
+func Test_NewJobTypeFromRequestDto_UnknownRequiredField_Returns_BadRequestError(t *testing.T) {
+	jobType, err := NewJobTypeFromRequestDto(dto.CreateUpdateJobTypeRequest{Name: "encoding", RequiredFields: []string{"sourc"}})
+
+	assert.Nil(t, jobType)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Required field sourc does not exist", err.Message())
+}
+
+func Test_NewJobTypeFromRequestDto_ValidValues_Returns_JobType(t *testing.T) {
+	typeReq := dto.CreateUpdateJobTypeRequest{
+		Name:            "encoding",
+		Description:     "Transcode media",
+		SubTypes:        []string{"h264"},
+		RequiredFields:  []string{"source", "extra_data"},
+		ExtraDataSchema: json.RawMessage(`{"type":"object"}`),
+	}
+
+	jobType, err := NewJobTypeFromRequestDto(typeReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encoding", jobType.Name)
+	assert.EqualValues(t, "Transcode media", jobType.Description)
+	assert.EqualValues(t, pq.StringArray{"h264"}, jobType.SubTypes)
+	assert.EqualValues(t, pq.StringArray{}, jobType.Actions)
+	assert.EqualValues(t, pq.StringArray{"source", "extra_data"}, jobType.RequiredFields)
+	assert.EqualValues(t, `{"type":"object"}`, *jobType.ExtraDataSchema)
+}
+
+func Test_ApplyRequest_NullSchema_Removes_Schema(t *testing.T) {
+	schema := `{"type":"object"}`
+	jobType := JobType{Name: "encoding", ExtraDataSchema: &schema}
+
+	err := jobType.ApplyRequest(dto.CreateUpdateJobTypeRequest{ExtraDataSchema: json.RawMessage(`null`)})
+
+	assert.Nil(t, err)
+	assert.Nil(t, jobType.ExtraDataSchema)
+}
+
+func Test_JobTypeValidateJobRequest_WrongSubType_Returns_BadRequestError(t *testing.T) {
+	jobType := JobType{Name: "encoding", SubTypes: pq.StringArray{"h264", "hevc"}}
+
+	err := jobType.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encoding", SubType: "vp9"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Sub type vp9 is not allowed for job type encoding", err.Message())
+}
+
+func Test_JobTypeValidateJobRequest_WrongAction_Returns_BadRequestError(t *testing.T) {
+	jobType := JobType{Name: "encoding", Actions: pq.StringArray{"transcode"}}
+
+	err := jobType.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encoding", Action: "delete"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Action delete is not allowed for job type encoding", err.Message())
+}
+
+func Test_JobTypeValidateJobRequest_MissingRequiredField_Returns_BadRequestError(t *testing.T) {
+	jobType := JobType{Name: "encoding", RequiredFields: pq.StringArray{"source", "destination"}}
+
+	err := jobType.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encoding", Source: "/in/a.mxf"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Field destination is required for job type encoding", err.Message())
+}
+
+func Test_JobTypeValidateJobRequest_ValidRequest_Returns_NoError(t *testing.T) {
+	schema := `{"type":"object","properties":{"asset":{"type":"object","required":["id"]}}}`
+	jobType := JobType{
+		Name:            "encoding",
+		SubTypes:        pq.StringArray{"h264"},
+		RequiredFields:  pq.StringArray{"source"},
+		ExtraDataSchema: &schema,
+	}
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		SubType:   "h264",
+		Source:    "/in/a.mxf",
+		ExtraData: json.RawMessage(`{"asset":{"id":123}}`),
+	}
+
+	err := jobType.ValidateJobRequest(jobReq)
+
+	assert.Nil(t, err)
+}
+
+func Test_NewJobTypeFromRequestDto_UnknownUniqueField_Returns_BadRequestError(t *testing.T) {
+	jobType, err := NewJobTypeFromRequestDto(dto.CreateUpdateJobTypeRequest{Name: "encoding", UniqueFields: []string{"sourc"}})
+
+	assert.Nil(t, jobType)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Unique field sourc does not exist", err.Message())
+}
+
+func Test_JobTypeUniqueKey_NoUniqueFields_Returns_Empty(t *testing.T) {
+	jobType := JobType{Name: "encoding"}
+
+	key := jobType.UniqueKey(dto.CreateUpdateJobRequest{Type: "encoding", Source: "a.mxf"})
+
+	assert.EqualValues(t, "", key)
+}
+
+func Test_JobTypeUniqueKey_UniqueFields_Returns_Key(t *testing.T) {
+	jobType := JobType{Name: "encoding", UniqueFields: pq.StringArray{"type", "source", "action"}}
+
+	key := jobType.UniqueKey(dto.CreateUpdateJobRequest{Type: "encoding", Source: "a.mxf", Action: "transcode", Name: "ignored"})
+
+	assert.EqualValues(t, `type="encoding"|source="a.mxf"|action="transcode"`, key)
+}
+
+func Test_GetJobRequestFieldsAsStrings_Returns_JsonNames(t *testing.T) {
+	fields := GetJobRequestFieldsAsStrings()
+
+	assert.Contains(t, fields, "correlationId")
+	assert.Contains(t, fields, "sub_type")
+	assert.Contains(t, fields, "extra_data")
+	assert.Contains(t, fields, "labels")
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -45,6 +46,7 @@ func TestConstants(t *testing.T) {
 	assert.EqualValues(t, md.Val, "medium")
 	assert.EqualValues(t, lo.Val, "low")
 	assert.EqualValues(t, id.Val, "idle")
+	assert.EqualValues(t, rt.Idx, MaxJobPriority)
 }
 
 func Test_CreateJobName_EmptyName_ReturnsGeneratedName(t *testing.T) {
@@ -89,6 +91,8 @@ func Test_NewJob_WithJobType_ReturnsNewJob(t *testing.T) {
 	assert.Empty(t, newJob.Action)
 	assert.Empty(t, newJob.ActionDetails)
 	assert.Contains(t, newJob.History, "Job created")
+	assert.EqualValues(t, 1, len(newJob.PendingEvents()))
+	assert.EqualValues(t, EventCreated, newJob.PendingEvents()[0].EventType)
 	assert.Empty(t, newJob.ExtraData)
 	assert.EqualValues(t, 30, newJob.Priority)
 	assert.EqualValues(t, 0, newJob.Rank)
@@ -119,7 +123,7 @@ func Test_ToJobResponseDto_Returns_JobResponseDto(t *testing.T) {
 	assert.EqualValues(t, newJob.Action, jobResp.Action)
 	assert.EqualValues(t, newJob.ActionDetails, jobResp.ActionDetails)
 	assert.EqualValues(t, newJob.History, jobResp.History)
-	assert.EqualValues(t, newJob.ExtraData, jobResp.ExtraData)
+	assert.Nil(t, jobResp.ExtraData)
 	assert.EqualValues(t, prio, jobResp.Priority)
 	assert.EqualValues(t, newJob.Rank, jobResp.Rank)
 }
@@ -187,9 +191,10 @@ func fillJobRequest() dto.CreateUpdateJobRequest {
 		SubType:       "subtype",
 		Action:        "action",
 		ActionDetails: "action details",
-		ExtraData:     "extra data",
+		ExtraData:     json.RawMessage(`{"asset":{"id":123}}`),
 		Priority:      "High",
 		Rank:          25,
+		Labels:        map[string]string{"customer": "acme"},
 	}
 }
 
@@ -208,16 +213,438 @@ func Test_NewJobFromJobRequestDto_ValidValues(t *testing.T) {
 	assert.EqualValues(t, newJobReq.SubType, newJob.SubType)
 	assert.EqualValues(t, newJobReq.Action, newJob.Action)
 	assert.EqualValues(t, newJobReq.ActionDetails, newJob.ActionDetails)
-	assert.EqualValues(t, newJobReq.ExtraData, newJob.ExtraData)
+	assert.EqualValues(t, string(newJobReq.ExtraData), *newJob.ExtraData)
+	assert.EqualValues(t, JobLabels{"customer": "acme"}, newJob.Labels)
 	assert.EqualValues(t, prio, newJob.Priority)
 	assert.EqualValues(t, newJobReq.Rank, newJob.Rank)
 }
 
+func Test_NewJobFromJobRequestDto_InvalidBackoffPolicy_Returns_BadRequestError(t *testing.T) {
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:          "encoding",
+		BackoffPolicy: "bogus",
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, newJob)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Backoff policy bogus does not exist", err.Message())
+}
+
+func Test_NewJobFromJobRequestDto_NoRetrySettings_Returns_Defaults(t *testing.T) {
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type: "encoding",
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, newJob.Attempts)
+	assert.EqualValues(t, DefaultMaxAttempts, newJob.MaxAttempts)
+	assert.EqualValues(t, BackoffExponential, newJob.BackoffPolicy)
+	assert.EqualValues(t, DefaultBackoffSeconds, newJob.BackoffSeconds)
+}
+
+func Test_CanRetry_AttemptsLeft_Returns_True(t *testing.T) {
+	job := Job{Attempts: 1, MaxAttempts: 3}
+
+	assert.EqualValues(t, true, job.CanRetry())
+}
+
+func Test_CanRetry_NoAttemptsLeft_Returns_False(t *testing.T) {
+	job := Job{Attempts: 3, MaxAttempts: 3}
+
+	assert.EqualValues(t, false, job.CanRetry())
+}
+
+func Test_RetryDelay_FixedPolicy_Returns_BackoffSeconds(t *testing.T) {
+	job := Job{Attempts: 3, BackoffPolicy: BackoffFixed, BackoffSeconds: 10}
+
+	assert.EqualValues(t, 10*time.Second, job.RetryDelay())
+}
+
+func Test_RetryDelay_ExponentialPolicy_Returns_DoubledDelay(t *testing.T) {
+	job := Job{Attempts: 3, BackoffPolicy: BackoffExponential, BackoffSeconds: 10}
+
+	assert.EqualValues(t, 40*time.Second, job.RetryDelay())
+}
+
+func Test_RetryDelay_ExponentialPolicy_Returns_CappedDelay(t *testing.T) {
+	job := Job{Attempts: 30, BackoffPolicy: BackoffExponential, BackoffSeconds: 3600}
+
+	assert.EqualValues(t, MaxBackoff, job.RetryDelay())
+}
+
+func Test_NewJobFromJobRequestDto_InvalidRunAt_Returns_BadRequestError(t *testing.T) {
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:  "encoding",
+		RunAt: "bogus",
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, newJob)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Run at time bogus is not a valid RFC3339 timestamp", err.Message())
+}
+
+func Test_NewJobFromJobRequestDto_FutureRunAt_Returns_ScheduledJob(t *testing.T) {
+	runAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:  "encoding",
+		RunAt: runAt.Format(time.RFC3339),
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, StatusScheduled, newJob.Status)
+	assert.EqualValues(t, runAt, *newJob.RunAt)
+	assert.Contains(t, newJob.History, "Job scheduled to run at")
+}
+
+func Test_NewJobFromJobRequestDto_PastRunAt_Returns_CreatedJob(t *testing.T) {
+	runAt := time.Now().UTC().Add(-time.Hour)
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:  "encoding",
+		RunAt: runAt.Format(time.RFC3339),
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, StatusCreated, newJob.Status)
+	assert.NotNil(t, newJob.RunAt)
+}
+
+func Test_NewJobFromJobRequestDto_InvalidDependency_Returns_BadRequestError(t *testing.T) {
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:      "proxy",
+		DependsOn: []string{"not_a_ksuid"},
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, newJob)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Prerequisite job id not_a_ksuid should be a ksuid", err.Message())
+}
+
+func Test_NewJobFromJobRequestDto_InvalidDependencyPolicy_Returns_BadRequestError(t *testing.T) {
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:             "proxy",
+		DependencyPolicy: "bogus",
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, newJob)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Dependency policy bogus does not exist", err.Message())
+}
+
+func Test_NewJobFromJobRequestDto_WithDependencies_Returns_UniqueDependencies(t *testing.T) {
+	prereqId := ksuid.New().String()
+	newJobReq := dto.CreateUpdateJobRequest{
+		Type:             "proxy",
+		DependsOn:        []string{prereqId, prereqId},
+		DependencyPolicy: "block",
+	}
+	newJob, err := NewJobFromJobRequestDto(newJobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{prereqId}, newJob.DependsOn)
+	assert.EqualValues(t, DependencyBlock, newJob.DependencyPolicy)
+}
+
+func Test_DependencyState_AllFinished_Returns_Satisfied(t *testing.T) {
+	job := Job{DependsOn: []string{"a", "b"}, DependencyPolicy: DependencyFail}
+	deps := []JobDependency{{Id: "a", Status: StatusFinished}, {Id: "b", Status: StatusFinished}}
+
+	assert.EqualValues(t, DependencySatisfied, job.DependencyState(deps))
+}
+
+func Test_DependencyState_OneRunning_Returns_Waiting(t *testing.T) {
+	job := Job{DependsOn: []string{"a", "b"}, DependencyPolicy: DependencyFail}
+	deps := []JobDependency{{Id: "a", Status: StatusFinished}, {Id: "b", Status: StatusRunning}}
+
+	assert.EqualValues(t, DependencyWaiting, job.DependencyState(deps))
+}
+
+func Test_DependencyState_FailedWithFailPolicy_Returns_Failed(t *testing.T) {
+	job := Job{DependsOn: []string{"a", "b"}, DependencyPolicy: DependencyFail}
+	deps := []JobDependency{{Id: "a", Status: StatusRunning}, {Id: "b", Status: StatusFailed}}
+
+	assert.EqualValues(t, DependencyFailed, job.DependencyState(deps))
+}
+
+func Test_DependencyState_FailedWithBlockPolicy_Returns_Blocked(t *testing.T) {
+	job := Job{DependsOn: []string{"a", "b"}, DependencyPolicy: DependencyBlock}
+	deps := []JobDependency{{Id: "a", Status: StatusFailed}, {Id: "b", Status: StatusRunning}}
+
+	assert.EqualValues(t, DependencyBlocked, job.DependencyState(deps))
+}
+
+func Test_ToJobResponseDtoWithDependencies_MissingPrerequisite_Returns_UnknownStatus(t *testing.T) {
+	job := Job{DependsOn: []string{"a", "b"}, DependencyPolicy: DependencyFail}
+	deps := []JobDependency{{Id: "a", Status: StatusFinished}}
+
+	response := job.ToJobResponseDtoWithDependencies(deps)
+
+	assert.EqualValues(t, 2, len(response.Dependencies))
+	assert.EqualValues(t, "finished", response.Dependencies[0].Status)
+	assert.EqualValues(t, "unknown", response.Dependencies[1].Status)
+	assert.EqualValues(t, "satisfied", response.DependencyState)
+}
+
+func Test_AggregateChildren_ChildrenRunning_Returns_AverageProgress(t *testing.T) {
+	parent := Job{Status: StatusRunning}
+	children := []Job{{Status: StatusFinished}, {Status: StatusRunning, Progress: 50}}
+
+	done := parent.AggregateChildren(children)
+
+	assert.EqualValues(t, false, done)
+	assert.EqualValues(t, StatusRunning, parent.Status)
+	assert.EqualValues(t, 75, parent.Progress)
+}
+
+func Test_AggregateChildren_AllFinished_Returns_Finished(t *testing.T) {
+	parent := Job{Status: StatusRunning}
+	children := []Job{{Status: StatusFinished}, {Status: StatusFinished}}
+
+	done := parent.AggregateChildren(children)
+
+	assert.EqualValues(t, true, done)
+	assert.EqualValues(t, StatusFinished, parent.Status)
+	assert.EqualValues(t, 100, parent.Progress)
+	assert.Contains(t, parent.History, "All 2 child jobs finished")
+}
+
+func Test_AggregateChildren_OneFailed_Returns_Failed(t *testing.T) {
+	parent := Job{Status: StatusRunning}
+	children := []Job{{Status: StatusFinished}, {Status: StatusFailed}, {Status: StatusFinished}}
+
+	done := parent.AggregateChildren(children)
+
+	assert.EqualValues(t, true, done)
+	assert.EqualValues(t, StatusFailed, parent.Status)
+	assert.Contains(t, parent.History, "1 of 3 child jobs failed")
+}
+
+func Test_AggregateChildren_ChildCancelled_Returns_Failed(t *testing.T) {
+	parent := Job{Status: StatusRunning}
+	children := []Job{{Status: StatusFinished}, {Status: StatusCancelled}}
+
+	done := parent.AggregateChildren(children)
+
+	assert.EqualValues(t, true, done)
+	assert.EqualValues(t, StatusFailed, parent.Status)
+	assert.Contains(t, parent.History, "1 of 2 child jobs failed")
+}
+
+func Test_ReportProgress_NotRunning_Returns_ConflictError(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	progress := int32(10)
+
+	err := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: &progress}, time.Minute)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Job with id %v is created and cannot report progress", job.Id.String()), err.Message())
+}
+
+func Test_ReportProgress_LowerProgress_Returns_ConflictError(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	job.Status = StatusRunning
+	job.Progress = 50
+	progress := int32(40)
+
+	err := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: &progress}, time.Minute)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Progress 40 of job with id %v is lower than its current progress 50", job.Id.String()), err.Message())
+}
+
+func Test_ReportProgress_WithinInterval_Returns_ThrottledHistory(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	job.Status = StatusRunning
+	first := int32(10)
+	second := int32(20)
+	third := int32(30)
+
+	err1 := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: &first, Message: "Pass 1", Eta: "2026-10-17T12:00:00Z"}, time.Minute)
+	err2 := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: &second, Message: "Pass 1"}, time.Minute)
+	err3 := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: &third, Message: "Pass 2"}, time.Minute)
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.EqualValues(t, 30, job.Progress)
+	assert.EqualValues(t, "Pass 2", job.ProgressMessage)
+	assert.EqualValues(t, "2026-10-17T12:00:00Z", job.Eta.Format(time.RFC3339))
+	assert.Contains(t, job.History, "Progress 10%: Pass 1. ETA 2026-10-17T12:00:00Z")
+	assert.NotContains(t, job.History, "Progress 20%")
+	assert.Contains(t, job.History, "Progress 30%: Pass 2")
+}
+
+func Test_ChangeStatus_Records_StatusChangedEvent(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	job.ClearPendingEvents()
+
+	job.ChangeStatus(StatusRunning, "worker 1", "Dequeuing job for processing")
+
+	events := job.PendingEvents()
+	assert.EqualValues(t, StatusRunning, job.Status)
+	assert.EqualValues(t, 1, len(events))
+	assert.EqualValues(t, job.Id.String(), events[0].JobId)
+	assert.EqualValues(t, EventStatusChanged, events[0].EventType)
+	assert.EqualValues(t, "worker 1", events[0].Actor)
+	assert.EqualValues(t, "created", events[0].OldStatus)
+	assert.EqualValues(t, "running", events[0].NewStatus)
+	assert.Contains(t, job.History, "Dequeuing job for processing")
+}
+
+func Test_SetHistory_Replaces_RenderedHistory(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	events := []JobEvent{
+		{CreatedAt: time.Date(2022, 1, 5, 6, 7, 55, 0, time.UTC), Message: "Job created"},
+	}
+
+	job.SetHistory(events)
+
+	assert.EqualValues(t, "2022-01-05T06:07:55Z: Job created\n", job.History)
+}
+
+func Test_Actor_NoLease_Returns_Api(t *testing.T) {
+	job := Job{}
+
+	assert.EqualValues(t, ActorApi, job.Actor())
+}
+
+func Test_Actor_Leased_Returns_LeaseOwner(t *testing.T) {
+	job := Job{LeaseOwner: "worker 1"}
+
+	assert.EqualValues(t, "worker 1", job.Actor())
+}
+
+func Test_SetOutcome_ResultAndError_Sets_Outcome(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	statusReq := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Result: json.RawMessage(`{"files":["part1.mp4"]}`),
+		Error:  &dto.JobErrorRequest{Code: "E_TIMEOUT", Message: "Encoder timed out"},
+	}
+
+	job.SetOutcome(statusReq)
+
+	assert.EqualValues(t, `{"files":["part1.mp4"]}`, *job.Result)
+	assert.EqualValues(t, "E_TIMEOUT", job.ErrorCode)
+	assert.EqualValues(t, "Encoder timed out", job.ErrorMessage)
+	assert.EqualValues(t, true, job.ErrorRetryable)
+}
+
+func Test_SetOutcome_NotRetryable_Sets_RetryableFalse(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+	retryable := false
+	statusReq := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Error:  &dto.JobErrorRequest{Code: "E_SOURCE_MISSING", Retryable: &retryable},
+	}
+
+	job.SetOutcome(statusReq)
+
+	assert.Nil(t, job.Result)
+	assert.EqualValues(t, "E_SOURCE_MISSING", job.ErrorCode)
+	assert.EqualValues(t, false, job.ErrorRetryable)
+}
+
+func Test_ToJobResponseDto_WithOutcome_Returns_ResultAndError(t *testing.T) {
+	result := `{"checksum":"abc123"}`
+	job := Job{
+		Result:         &result,
+		ErrorCode:      "E_TIMEOUT",
+		ErrorMessage:   "Encoder timed out",
+		ErrorRetryable: true,
+	}
+
+	jobResp := job.ToJobResponseDto()
+
+	assert.EqualValues(t, result, string(jobResp.Result))
+	assert.EqualValues(t, "E_TIMEOUT", jobResp.Error.Code)
+	assert.EqualValues(t, "Encoder timed out", jobResp.Error.Message)
+	assert.EqualValues(t, true, jobResp.Error.Retryable)
+}
+
+func Test_ToJobResponseDto_NoOutcome_Returns_NoResultAndError(t *testing.T) {
+	job, _ := NewJob("Job 1", "encoding")
+
+	jobResp := job.ToJobResponseDto()
+
+	assert.Nil(t, jobResp.Result)
+	assert.Nil(t, jobResp.Error)
+}
+
+func Test_ToJobResponseDto_WithExtraData_Returns_ExtraData(t *testing.T) {
+	extraData := `{"asset":{"id":123}}`
+	job := Job{
+		ExtraData: &extraData,
+	}
+
+	jobResp := job.ToJobResponseDto()
+
+	assert.EqualValues(t, extraData, string(jobResp.ExtraData))
+}
+
+func Test_SplitJsonPath_ValidPath_Returns_ColumnAndPath(t *testing.T) {
+	column, path, ok := SplitJsonPath("extra_data.asset.id")
+
+	assert.True(t, ok)
+	assert.EqualValues(t, "extra_data", column)
+	assert.EqualValues(t, []string{"asset", "id"}, path)
+}
+
+func Test_SplitJsonPath_InvalidPath_Returns_NotOk(t *testing.T) {
+	for _, key := range []string{"extra_data", "name.first", "extra_data.asset..id", "extra_data.asset'id"} {
+		_, _, ok := SplitJsonPath(key)
+
+		assert.False(t, ok, key)
+	}
+}
+
+func Test_IsValidExtraData_Returns_ObjectsOnly(t *testing.T) {
+	assert.True(t, IsValidExtraData(json.RawMessage(`{"asset":{"id":123}}`)))
+	assert.False(t, IsValidExtraData(json.RawMessage(`["asset"]`)))
+	assert.False(t, IsValidExtraData(json.RawMessage(`"asset"`)))
+	assert.False(t, IsValidExtraData(json.RawMessage(`null`)))
+}
+
 func Test_GetJobDbFieldsAsStrings(t *testing.T) {
 	expectedFields := []string{"id", "correlation_id", "name", "created_at", "created_by", "modified_at", "modified_by", "status",
-		"source", "destination", "type", "sub_type", "action", "action_details", "progress", "history", "extra_data", "priority", "rank"}
+		"source", "destination", "type", "sub_type", "action", "action_details", "progress", "extra_data", "priority", "rank",
+		"lease_owner", "lease_expires_at", "attempts", "max_attempts", "backoff_policy", "backoff_seconds", "not_before", "run_at", "depends_on", "dependency_policy", "parent_id", "cancel_requested", "eta", "progress_message", "progress_logged_at", "result", "error_code", "error_message", "error_retryable", "labels", "unique_key"}
 
 	jobFields := GetJobDbFieldsAsStrings()
 
 	assert.Equal(t, expectedFields, jobFields)
 }
+
+func Test_ToJobRequestDto_Returns_JobRequest(t *testing.T) {
+	jobReq := dto.CreateUpdateJobRequest{
+		Name:      "job 1",
+		Type:      "encoding",
+		Source:    "a.mxf",
+		Priority:  "high",
+		RunAt:     "2030-01-02T03:04:05Z",
+		Labels:    map[string]string{"team": "media"},
+		UniqueKey: "encode-a.mxf",
+	}
+	job, _ := NewJobFromJobRequestDto(jobReq)
+
+	result := job.ToJobRequestDto()
+
+	assert.EqualValues(t, "job 1", result.Name)
+	assert.EqualValues(t, "a.mxf", result.Source)
+	assert.EqualValues(t, "high", result.Priority)
+	assert.EqualValues(t, "2030-01-02T03:04:05Z", result.RunAt)
+	assert.EqualValues(t, "media", result.Labels["team"])
+	assert.EqualValues(t, "encode-a.mxf", result.UniqueKey)
+}
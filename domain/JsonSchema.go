@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/johannes-kuhfuss/services_utils/misc"
+)
+
+type JsonSchema struct {
+	Type                 interface{}            `json:"type"`
+	Properties           map[string]*JsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *JsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	pattern              *regexp.Regexp
+}
+
+// jsonSchemaKeywords lists the keywords the validator implements plus annotations without
+// validation semantics. Schemas using any other keyword are rejected instead of being
+// silently accepted with the keyword ignored.
+var jsonSchemaKeywords = []string{
+	"type", "properties", "required", "additionalProperties", "items", "enum",
+	"minimum", "maximum", "minLength", "maxLength", "pattern", "minItems", "maxItems",
+	"$schema", "$id", "$comment", "title", "description", "default", "examples",
+}
+
+func ParseJsonSchema(schemaJson []byte) (*JsonSchema, error) {
+	var schema JsonSchema
+	if err := json.Unmarshal(schemaJson, &schema); err != nil {
+		return nil, err
+	}
+	if err := schema.compile(); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (s *JsonSchema) UnmarshalJSON(data []byte) error {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for keyword, val := range keywords {
+		if !misc.SliceContainsString(jsonSchemaKeywords, keyword) {
+			return fmt.Errorf("unsupported keyword %v", keyword)
+		}
+		if keyword == "additionalProperties" {
+			var allowed bool
+			if err := json.Unmarshal(val, &allowed); err != nil {
+				return fmt.Errorf("additionalProperties must be a boolean")
+			}
+		}
+	}
+	type plainSchema JsonSchema
+	return json.Unmarshal(data, (*plainSchema)(s))
+}
+
+func (s *JsonSchema) compile() error {
+	for _, schemaType := range s.types() {
+		if !isJsonSchemaType(schemaType) {
+			return fmt.Errorf("unknown type %v", schemaType)
+		}
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %v", s.Pattern)
+		}
+		s.pattern = pattern
+	}
+	for _, prop := range s.Properties {
+		if prop == nil {
+			continue
+		}
+		if err := prop.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+func (s *JsonSchema) Validate(docJson []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(docJson, &doc); err != nil {
+		return err
+	}
+	return s.validate(doc, "extra_data")
+}
+
+func (s *JsonSchema) types() []string {
+	switch val := s.Type.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		types := make([]string, 0, len(val))
+		for _, elem := range val {
+			types = append(types, fmt.Sprintf("%v", elem))
+		}
+		return types
+	}
+	return nil
+}
+
+func (s *JsonSchema) validate(val interface{}, path string) error {
+	if types := s.types(); len(types) > 0 && !hasJsonSchemaType(val, types) {
+		return fmt.Errorf("%v must be of type %v", path, strings.Join(types, " or "))
+	}
+	if len(s.Enum) > 0 && !containsJsonValue(s.Enum, val) {
+		return fmt.Errorf("%v has a value that is not allowed", path)
+	}
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		return s.validateObject(typedVal, path)
+	case []interface{}:
+		return s.validateArray(typedVal, path)
+	case string:
+		return s.validateString(typedVal, path)
+	case float64:
+		return s.validateNumber(typedVal, path)
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateObject(obj map[string]interface{}, path string) error {
+	for _, key := range s.Required {
+		if _, found := obj[key]; !found {
+			return fmt.Errorf("%v.%v is required", path, key)
+		}
+	}
+	for key, val := range obj {
+		prop, found := s.Properties[key]
+		if !found {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return fmt.Errorf("%v.%v is not allowed", path, key)
+			}
+			continue
+		}
+		if prop == nil {
+			continue
+		}
+		if err := prop.validate(val, fmt.Sprintf("%v.%v", path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateArray(arr []interface{}, path string) error {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		return fmt.Errorf("%v must have at least %d items", path, *s.MinItems)
+	}
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		return fmt.Errorf("%v must have at most %d items", path, *s.MaxItems)
+	}
+	if s.Items == nil {
+		return nil
+	}
+	for idx, elem := range arr {
+		if err := s.Items.validate(elem, fmt.Sprintf("%v[%d]", path, idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateString(str string, path string) error {
+	if s.MinLength != nil && len([]rune(str)) < *s.MinLength {
+		return fmt.Errorf("%v must be at least %d characters long", path, *s.MinLength)
+	}
+	if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+		return fmt.Errorf("%v must be at most %d characters long", path, *s.MaxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		return fmt.Errorf("%v does not match pattern %v", path, s.Pattern)
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateNumber(num float64, path string) error {
+	if s.Minimum != nil && num < *s.Minimum {
+		return fmt.Errorf("%v must be at least %v", path, *s.Minimum)
+	}
+	if s.Maximum != nil && num > *s.Maximum {
+		return fmt.Errorf("%v must be at most %v", path, *s.Maximum)
+	}
+	return nil
+}
+
+func isJsonSchemaType(schemaType string) bool {
+	switch schemaType {
+	case "object", "array", "string", "number", "integer", "boolean", "null":
+		return true
+	}
+	return false
+}
+
+func hasJsonSchemaType(val interface{}, types []string) bool {
+	for _, schemaType := range types {
+		switch typedVal := val.(type) {
+		case map[string]interface{}:
+			if schemaType == "object" {
+				return true
+			}
+		case []interface{}:
+			if schemaType == "array" {
+				return true
+			}
+		case string:
+			if schemaType == "string" {
+				return true
+			}
+		case float64:
+			if schemaType == "number" || (schemaType == "integer" && typedVal == math.Trunc(typedVal)) {
+				return true
+			}
+		case bool:
+			if schemaType == "boolean" {
+				return true
+			}
+		case nil:
+			if schemaType == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsJsonValue(values []interface{}, val interface{}) bool {
+	valJson, _ := json.Marshal(val)
+	for _, allowed := range values {
+		allowedJson, _ := json.Marshal(allowed)
+		if string(allowedJson) == string(valJson) {
+			return true
+		}
+	}
+	return false
+}
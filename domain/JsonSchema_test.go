@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseJsonSchema_UnknownType_Returns_Error(t *testing.T) {
+	schema, err := ParseJsonSchema([]byte(`{"properties":{"id":{"type":"int"}}}`))
+
+	assert.Nil(t, schema)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "unknown type int", err.Error())
+}
+
+func Test_ParseJsonSchema_InvalidPattern_Returns_Error(t *testing.T) {
+	schema, err := ParseJsonSchema([]byte(`{"type":"string","pattern":"[a-"}`))
+
+	assert.Nil(t, schema)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "invalid pattern [a-", err.Error())
+}
+
+func Test_ParseJsonSchema_UnsupportedKeyword_Returns_Error(t *testing.T) {
+	schema, err := ParseJsonSchema([]byte(`{"type":"object","properties":{"id":{"oneOf":[{"type":"string"},{"type":"integer"}]}}}`))
+
+	assert.Nil(t, schema)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "unsupported keyword oneOf", err.Error())
+}
+
+func Test_ParseJsonSchema_AdditionalPropertiesSchema_Returns_Error(t *testing.T) {
+	schema, err := ParseJsonSchema([]byte(`{"type":"object","additionalProperties":{"type":"string"}}`))
+
+	assert.Nil(t, schema)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "additionalProperties must be a boolean", err.Error())
+}
+
+func Test_ParseJsonSchema_Annotations_Returns_Schema(t *testing.T) {
+	schema, err := ParseJsonSchema([]byte(`{"$schema":"http://json-schema.org/draft-07/schema#","title":"asset","description":"asset data","type":"object"}`))
+
+	assert.Nil(t, err)
+	assert.NotNil(t, schema)
+}
+
+func Test_JsonSchemaValidate_Violations_Returns_Error(t *testing.T) {
+	schema, _ := ParseJsonSchema([]byte(`{
+		"type": "object",
+		"required": ["asset"],
+		"additionalProperties": false,
+		"properties": {
+			"asset": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer", "minimum": 1},
+					"codec": {"enum": ["h264", "hevc"]},
+					"path": {"type": "string", "pattern": "^/"},
+					"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+				}
+			}
+		}
+	}`))
+	tests := map[string]string{
+		`[]`:                               "extra_data must be of type object",
+		`{}`:                               "extra_data.asset is required",
+		`{"asset":{},"note":"x"}`:          "extra_data.note is not allowed",
+		`{"asset":{"id":1.5}}`:             "extra_data.asset.id must be of type integer",
+		`{"asset":{"id":0}}`:               "extra_data.asset.id must be at least 1",
+		`{"asset":{"codec":"vp9"}}`:        "extra_data.asset.codec has a value that is not allowed",
+		`{"asset":{"path":"in/a.mxf"}}`:    "extra_data.asset.path does not match pattern ^/",
+		`{"asset":{"tags":["a","b","c"]}}`: "extra_data.asset.tags must have at most 2 items",
+		`{"asset":{"tags":["a",1]}}`:       "extra_data.asset.tags[1] must be of type string",
+	}
+	for doc, msg := range tests {
+		err := schema.Validate([]byte(doc))
+
+		assert.NotNil(t, err, doc)
+		assert.EqualValues(t, msg, err.Error(), doc)
+	}
+}
+
+func Test_JsonSchemaValidate_ValidDocument_Returns_NoError(t *testing.T) {
+	schema, _ := ParseJsonSchema([]byte(`{"type":"object","properties":{"id":{"type":["integer","string"]},"name":{"type":"string","minLength":1}}}`))
+
+	err := schema.Validate([]byte(`{"id":"A-123","name":"trailer","extra":true}`))
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "strings"
+
+type MissedRunPolicy string
+
+const (
+	MissedRunSkip    MissedRunPolicy = "skip"
+	MissedRunRunOnce MissedRunPolicy = "run_once"
+)
+
+func IsValidMissedRunPolicy(policyVal string) bool {
+	val := strings.TrimSpace(strings.ToLower(policyVal))
+	if (val == string(MissedRunSkip)) ||
+		(val == string(MissedRunRunOnce)) {
+		return true
+	} else {
+		return false
+	}
+}
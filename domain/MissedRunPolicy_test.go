@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsValidMissedRunPolicy_InvalidPolicy_Returns_False(t *testing.T) {
+	valid := IsValidMissedRunPolicy("bogus")
+
+	assert.NotNil(t, valid)
+	assert.EqualValues(t, false, valid)
+}
+
+func Test_IsValidMissedRunPolicy_ValidPolicy_Returns_True(t *testing.T) {
+	validPolicies := []string{"skip", "run_once"}
+
+	for _, policy := range validPolicies {
+		valid := IsValidMissedRunPolicy(policy)
+
+		assert.NotNil(t, valid)
+		assert.EqualValues(t, true, valid)
+	}
+}
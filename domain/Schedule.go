@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/robfig/cron/v3"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	DefaultScheduleTimeZone string = "UTC"
+)
+
+type Schedule struct {
+	Id              ksuid.KSUID     `db:"id"`
+	Name            string          `db:"name"`
+	CronExpr        string          `db:"cron_expr"`
+	TimeZone        string          `db:"time_zone"`
+	JobTemplate     string          `db:"job_template"`
+	Enabled         bool            `db:"enabled"`
+	MissedRunPolicy MissedRunPolicy `db:"missed_run_policy"`
+	LastRunAt       *time.Time      `db:"last_run_at"`
+	NextRunAt       *time.Time      `db:"next_run_at"`
+	CreatedAt       time.Time       `db:"created_at"`
+	ModifiedAt      time.Time       `db:"modified_at"`
+}
+
+//go:generate mockgen -destination=../mocks/domain/mockScheduleRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain ScheduleRepository
+type ScheduleRepository interface {
+	Store(Schedule) api_error.ApiErr
+	FindAll() (*[]Schedule, api_error.ApiErr)
+	FindById(string) (*Schedule, api_error.ApiErr)
+	Update(Schedule) api_error.ApiErr
+	DeleteById(string) api_error.ApiErr
+	ClaimRun(string, *time.Time, *time.Time, *time.Time) (bool, api_error.ApiErr)
+}
+
+func NewScheduleFromRequestDto(schedReq dto.CreateUpdateScheduleRequest) (*Schedule, api_error.ApiErr) {
+	now := date.GetNowUtc()
+	newSchedule := Schedule{
+		Id:              ksuid.New(),
+		Name:            schedReq.Name,
+		CronExpr:        schedReq.CronExpr,
+		TimeZone:        DefaultScheduleTimeZone,
+		Enabled:         true,
+		MissedRunPolicy: MissedRunSkip,
+		CreatedAt:       now,
+		ModifiedAt:      now,
+	}
+	if schedReq.Job == nil {
+		return nil, api_error.NewBadRequestError("Schedule must have a job")
+	}
+	err := newSchedule.ApplyRequest(schedReq)
+	if err != nil {
+		return nil, err
+	}
+	return &newSchedule, nil
+}
+
+func (s *Schedule) ApplyRequest(schedReq dto.CreateUpdateScheduleRequest) api_error.ApiErr {
+	if schedReq.Name != "" {
+		s.Name = schedReq.Name
+	}
+	if schedReq.CronExpr != "" {
+		s.CronExpr = schedReq.CronExpr
+	}
+	if schedReq.TimeZone != "" {
+		s.TimeZone = schedReq.TimeZone
+	}
+	if schedReq.Enabled != nil {
+		s.Enabled = *schedReq.Enabled
+	}
+	if schedReq.MissedRunPolicy != "" {
+		if !IsValidMissedRunPolicy(schedReq.MissedRunPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Missed run policy %v does not exist", schedReq.MissedRunPolicy))
+		}
+		s.MissedRunPolicy = MissedRunPolicy(schedReq.MissedRunPolicy)
+	}
+	if schedReq.Job != nil {
+		if strings.TrimSpace(schedReq.Job.Type) == "" {
+			return api_error.NewBadRequestError("Job must have a type")
+		}
+		template, err := json.Marshal(schedReq.Job)
+		if err != nil {
+			return api_error.NewBadRequestError("Could not store job of schedule")
+		}
+		s.JobTemplate = string(template)
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		s.Name = fmt.Sprintf("schedule %v", s.CronExpr)
+	}
+	s.ModifiedAt = date.GetNowUtc()
+	nextRun, err := s.NextRun(s.ModifiedAt)
+	if err != nil {
+		return err
+	}
+	if s.Enabled {
+		s.NextRunAt = nextRun
+	} else {
+		s.NextRunAt = nil
+	}
+	return nil
+}
+
+func (s *Schedule) CronSpec() string {
+	return fmt.Sprintf("CRON_TZ=%v %v", s.TimeZone, s.CronExpr)
+}
+
+func (s *Schedule) NextRun(after time.Time) (*time.Time, api_error.ApiErr) {
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("Time zone %v does not exist", s.TimeZone))
+	}
+	cronSched, err := cron.ParseStandard(s.CronSpec())
+	if err != nil {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("Cron expression %v is not valid", s.CronExpr))
+	}
+	nextRun := cronSched.Next(after).UTC()
+	return &nextRun, nil
+}
+
+func (s *Schedule) IsMissed(now time.Time) bool {
+	return s.Enabled && s.NextRunAt != nil && s.NextRunAt.Before(now)
+}
+
+func (s *Schedule) JobRequest() (*dto.CreateUpdateJobRequest, api_error.ApiErr) {
+	var jobReq dto.CreateUpdateJobRequest
+	err := json.Unmarshal([]byte(s.JobTemplate), &jobReq)
+	if err != nil {
+		return nil, api_error.NewInternalServerError(fmt.Sprintf("Could not read job of schedule %v", s.Id.String()), err)
+	}
+	return &jobReq, nil
+}
+
+func (s *Schedule) ToScheduleResponseDto() dto.ScheduleResponse {
+	var jobReq dto.CreateUpdateJobRequest
+	json.Unmarshal([]byte(s.JobTemplate), &jobReq)
+	return dto.ScheduleResponse{
+		Id:              s.Id.String(),
+		Name:            s.Name,
+		CronExpr:        s.CronExpr,
+		TimeZone:        s.TimeZone,
+		Enabled:         s.Enabled,
+		MissedRunPolicy: string(s.MissedRunPolicy),
+		Job:             jobReq,
+		LastRunAt:       s.LastRunAt,
+		NextRunAt:       s.NextRunAt,
+		CreatedAt:       s.CreatedAt,
+		ModifiedAt:      s.ModifiedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewScheduleFromRequestDto_NoJob_Returns_BadRequestError(t *testing.T) {
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+	}
+
+	sched, err := NewScheduleFromRequestDto(schedReq)
+
+	assert.Nil(t, sched)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Schedule must have a job", err.Message())
+}
+
+func Test_NewScheduleFromRequestDto_InvalidCron_Returns_BadRequestError(t *testing.T) {
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "every morning",
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	sched, err := NewScheduleFromRequestDto(schedReq)
+
+	assert.Nil(t, sched)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Cron expression every morning is not valid", err.Message())
+}
+
+func Test_NewScheduleFromRequestDto_InvalidTimeZone_Returns_BadRequestError(t *testing.T) {
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		TimeZone: "Mars/Olympus",
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	sched, err := NewScheduleFromRequestDto(schedReq)
+
+	assert.Nil(t, sched)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Time zone Mars/Olympus does not exist", err.Message())
+}
+
+func Test_NewScheduleFromRequestDto_ValidValues_Returns_Schedule(t *testing.T) {
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Name: "Morning encode", Type: "encoding"},
+	}
+
+	sched, err := NewScheduleFromRequestDto(schedReq)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, sched)
+	assert.EqualValues(t, DefaultScheduleTimeZone, sched.TimeZone)
+	assert.EqualValues(t, true, sched.Enabled)
+	assert.EqualValues(t, MissedRunSkip, sched.MissedRunPolicy)
+	assert.EqualValues(t, "schedule 0 6 * * *", sched.Name)
+	assert.NotNil(t, sched.NextRunAt)
+	assert.True(t, sched.NextRunAt.After(time.Now()))
+	assert.EqualValues(t, 6, sched.NextRunAt.Hour())
+	jobReq, _ := sched.JobRequest()
+	assert.EqualValues(t, "Morning encode", jobReq.Name)
+	assert.EqualValues(t, "encoding", jobReq.Type)
+}
+
+func Test_NewScheduleFromRequestDto_Disabled_Returns_NoNextRun(t *testing.T) {
+	enabled := false
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Enabled:  &enabled,
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	sched, err := NewScheduleFromRequestDto(schedReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, false, sched.Enabled)
+	assert.Nil(t, sched.NextRunAt)
+}
+
+func Test_NextRun_WithTimeZone_Returns_UtcTime(t *testing.T) {
+	sched := Schedule{
+		CronExpr: "0 6 * * *",
+		TimeZone: "Europe/Berlin",
+	}
+	after := time.Date(2022, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	nextRun, err := sched.NextRun(after)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, time.Date(2022, 1, 6, 5, 0, 0, 0, time.UTC), *nextRun)
+}
+
+func Test_IsMissed_NextRunInPast_Returns_True(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	sched := Schedule{Enabled: true, NextRunAt: &past}
+
+	assert.EqualValues(t, true, sched.IsMissed(time.Now().UTC()))
+}
+
+func Test_IsMissed_Disabled_Returns_False(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	sched := Schedule{Enabled: false, NextRunAt: &past}
+
+	assert.EqualValues(t, false, sched.IsMissed(time.Now().UTC()))
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "strings"
+
+type UniquePolicy string
+
+const (
+	UniqueReject         UniquePolicy = "reject"
+	UniqueReturnExisting UniquePolicy = "return_existing"
+)
+
+func IsValidUniquePolicy(policyVal string) bool {
+	val := strings.TrimSpace(strings.ToLower(policyVal))
+	if (val == string(UniqueReject)) ||
+		(val == string(UniqueReturnExisting)) {
+		return true
+	} else {
+		return false
+	}
+}
+
+func AsUniquePolicy(policyVal string) UniquePolicy {
+	val := strings.TrimSpace(strings.ToLower(policyVal))
+	if val == string(UniqueReturnExisting) {
+		return UniqueReturnExisting
+	}
+	return UniqueReject
+}
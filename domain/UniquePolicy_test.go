@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsValidUniquePolicy_InvalidPolicy_Returns_False(t *testing.T) {
+	valid := IsValidUniquePolicy("bogus")
+
+	assert.NotNil(t, valid)
+	assert.EqualValues(t, false, valid)
+}
+
+func Test_IsValidUniquePolicy_ValidPolicy_Returns_True(t *testing.T) {
+	validPolicies := []string{"reject", "return_existing"}
+
+	for _, policy := range validPolicies {
+		valid := IsValidUniquePolicy(policy)
+
+		assert.NotNil(t, valid)
+		assert.EqualValues(t, true, valid)
+	}
+}
+
+func Test_AsUniquePolicy_Empty_Returns_Reject(t *testing.T) {
+	assert.EqualValues(t, UniqueReject, AsUniquePolicy(""))
+	assert.EqualValues(t, UniqueReturnExisting, AsUniquePolicy("return_existing"))
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/segmentio/ksuid"
+)
+
+type WorkflowStatus string
+
+const (
+	WorkflowRunning  WorkflowStatus = "running"
+	WorkflowFinished WorkflowStatus = "finished"
+	WorkflowFailed   WorkflowStatus = "failed"
+)
+
+type Workflow struct {
+	Id            ksuid.KSUID    `db:"id"`
+	Name          string         `db:"name"`
+	CorrelationId string         `db:"correlation_id"`
+	Status        WorkflowStatus `db:"status"`
+	Stages        string         `db:"stages"`
+	CurrentStage  int32          `db:"current_stage"`
+	CreatedAt     time.Time      `db:"created_at"`
+	ModifiedAt    time.Time      `db:"modified_at"`
+}
+
+type WorkflowStage struct {
+	Job      dto.CreateUpdateJobRequest `json:"job"`
+	JobId    string                     `json:"jobId,omitempty"`
+	JobName  string                     `json:"jobName,omitempty"`
+	Status   string                     `json:"status,omitempty"`
+	Progress int32                      `json:"progress,omitempty"`
+}
+
+//go:generate mockgen -destination=../mocks/domain/mockWorkflowRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain WorkflowRepository
+type WorkflowRepository interface {
+	Store(Workflow, Job) api_error.ApiErr
+	FindAll() (*[]Workflow, api_error.ApiErr)
+	FindById(string) (*Workflow, api_error.ApiErr)
+	FindRunning() (*[]Workflow, api_error.ApiErr)
+	FindStageJobs([]string) (*[]Job, api_error.ApiErr)
+	AdvanceStage(Workflow, int32, *Job) (bool, api_error.ApiErr)
+}
+
+func NewWorkflowFromRequestDto(wfReq dto.CreateWorkflowRequest) (*Workflow, *Job, api_error.ApiErr) {
+	if len(wfReq.Stages) == 0 {
+		return nil, nil, api_error.NewBadRequestError("Workflow must have at least one stage")
+	}
+	now := date.GetNowUtc()
+	newWorkflow := Workflow{
+		Id:            ksuid.New(),
+		Name:          wfReq.Name,
+		CorrelationId: wfReq.CorrelationId,
+		Status:        WorkflowRunning,
+		CurrentStage:  0,
+		CreatedAt:     now,
+		ModifiedAt:    now,
+	}
+	if strings.TrimSpace(newWorkflow.Name) == "" {
+		newDate, _ := date.GetNowLocalString("")
+		newWorkflow.Name = fmt.Sprintf("new workflow @ %s", *newDate)
+	}
+	if strings.TrimSpace(newWorkflow.CorrelationId) == "" {
+		newWorkflow.CorrelationId = newWorkflow.Id.String()
+	}
+	stages := make([]WorkflowStage, 0)
+	for idx, stageReq := range wfReq.Stages {
+		if strings.TrimSpace(stageReq.Type) == "" {
+			return nil, nil, api_error.NewBadRequestError(fmt.Sprintf("Stage %d of workflow must have a type", idx+1))
+		}
+		stageReq.CorrelationId = newWorkflow.CorrelationId
+		stageReq.DependsOn = nil
+		stages = append(stages, WorkflowStage{Job: stageReq})
+	}
+	firstJob, err := NewJobFromJobRequestDto(stages[0].Job)
+	if err != nil {
+		return nil, nil, err
+	}
+	firstJob.AddHistory(fmt.Sprintf("Created as stage 1 of %d of workflow %v", len(stages), newWorkflow.Id.String()))
+	stages[0].JobId = firstJob.Id.String()
+	err = newWorkflow.SetStageList(stages)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &newWorkflow, firstJob, nil
+}
+
+func (w *Workflow) StageList() ([]WorkflowStage, api_error.ApiErr) {
+	stages := make([]WorkflowStage, 0)
+	err := json.Unmarshal([]byte(w.Stages), &stages)
+	if err != nil {
+		return nil, api_error.NewInternalServerError(fmt.Sprintf("Could not read stages of workflow %v", w.Id.String()), err)
+	}
+	return stages, nil
+}
+
+func (w *Workflow) SetStageList(stages []WorkflowStage) api_error.ApiErr {
+	stageData, err := json.Marshal(stages)
+	if err != nil {
+		return api_error.NewInternalServerError(fmt.Sprintf("Could not store stages of workflow %v", w.Id.String()), err)
+	}
+	w.Stages = string(stageData)
+	return nil
+}
+
+func (w *Workflow) StageJobIds() []string {
+	ids := make([]string, 0)
+	stages, err := w.StageList()
+	if err != nil {
+		return ids
+	}
+	for _, stage := range stages {
+		if stage.JobId != "" && stage.Status == "" {
+			ids = append(ids, stage.JobId)
+		}
+	}
+	return ids
+}
+
+func (w *Workflow) Advance(stageJob *Job) (*Job, bool, api_error.ApiErr) {
+	if w.Status != WorkflowRunning {
+		return nil, false, nil
+	}
+	stages, err := w.StageList()
+	if err != nil {
+		return nil, false, err
+	}
+	if stageJob == nil {
+		w.Status = WorkflowFailed
+		w.ModifiedAt = date.GetNowUtc()
+		return nil, true, nil
+	}
+	if !stageJob.IsDone() {
+		return nil, false, nil
+	}
+	stages[w.CurrentStage].JobName = stageJob.Name
+	stages[w.CurrentStage].Status = string(stageJob.Status)
+	stages[w.CurrentStage].Progress = stageJob.Progress
+	var nextJob *Job
+	switch {
+	case stageJob.Status != StatusFinished:
+		w.Status = WorkflowFailed
+	case int(w.CurrentStage) >= len(stages)-1:
+		stages[w.CurrentStage].Progress = 100
+		w.Status = WorkflowFinished
+	default:
+		stages[w.CurrentStage].Progress = 100
+		nextIdx := w.CurrentStage + 1
+		nextReq := stages[nextIdx].Job
+		if stageJob.Destination != "" {
+			nextReq.Source = stageJob.Destination
+		}
+		nextJob, err = NewJobFromJobRequestDto(nextReq)
+		if err != nil {
+			return nil, false, err
+		}
+		nextJob.AddHistory(fmt.Sprintf("Created as stage %d of %d of workflow %v", nextIdx+1, len(stages), w.Id.String()))
+		stages[nextIdx].Job = nextReq
+		stages[nextIdx].JobId = nextJob.Id.String()
+		w.CurrentStage = nextIdx
+	}
+	err = w.SetStageList(stages)
+	if err != nil {
+		return nil, false, err
+	}
+	w.ModifiedAt = date.GetNowUtc()
+	return nextJob, true, nil
+}
+
+func (w *Workflow) ToWorkflowResponseDto(stageJobs []Job) dto.WorkflowResponse {
+	jobs := make(map[string]Job)
+	for _, job := range stageJobs {
+		jobs[job.Id.String()] = job
+	}
+	stages, _ := w.StageList()
+	stageResponses := make([]dto.WorkflowStageResponse, 0)
+	var totalProgress int32
+	for _, stage := range stages {
+		stageResp := dto.WorkflowStageResponse{
+			Name:    stage.Job.Name,
+			Type:    stage.Job.Type,
+			SubType: stage.Job.SubType,
+			Action:  stage.Job.Action,
+			JobId:   stage.JobId,
+			Status:  "pending",
+		}
+		if stage.Status != "" {
+			stageResp.Name = stage.JobName
+			stageResp.Status = stage.Status
+			stageResp.Progress = stage.Progress
+		} else if job, found := jobs[stage.JobId]; found {
+			stageResp.Name = job.Name
+			stageResp.Status = string(job.Status)
+			stageResp.Progress = job.Progress
+			if job.Status == StatusFinished {
+				stageResp.Progress = 100
+			}
+		}
+		totalProgress += stageResp.Progress
+		stageResponses = append(stageResponses, stageResp)
+	}
+	var progress int32
+	if len(stages) > 0 {
+		progress = totalProgress / int32(len(stages))
+	}
+	return dto.WorkflowResponse{
+		Id:            w.Id.String(),
+		Name:          w.Name,
+		CorrelationId: w.CorrelationId,
+		Status:        string(w.Status),
+		CurrentStage:  w.CurrentStage + 1,
+		StageCount:    int32(len(stages)),
+		Progress:      progress,
+		Stages:        stageResponses,
+		CreatedAt:     w.CreatedAt,
+		ModifiedAt:    w.ModifiedAt,
+	}
+}
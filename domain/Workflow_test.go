@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWorkflow() (*Workflow, *Job) {
+	wfReq := dto.CreateWorkflowRequest{
+		Name: "media pipeline",
+		Stages: []dto.CreateUpdateJobRequest{
+			{Name: "encode", Type: "encoding", Source: "/in/file.mxf", Destination: "/out/file.mp4"},
+			{Name: "proxy", Type: "proxy"},
+			{Name: "techqc", Type: "qc"},
+		},
+	}
+	wf, firstJob, _ := NewWorkflowFromRequestDto(wfReq)
+	return wf, firstJob
+}
+
+func Test_NewWorkflowFromRequestDto_NoStages_Returns_BadRequestError(t *testing.T) {
+	wf, firstJob, err := NewWorkflowFromRequestDto(dto.CreateWorkflowRequest{Name: "empty"})
+
+	assert.Nil(t, wf)
+	assert.Nil(t, firstJob)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Workflow must have at least one stage", err.Message())
+}
+
+func Test_NewWorkflowFromRequestDto_StageWithoutType_Returns_BadRequestError(t *testing.T) {
+	wfReq := dto.CreateWorkflowRequest{
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding"}, {Name: "no type"}},
+	}
+
+	wf, _, err := NewWorkflowFromRequestDto(wfReq)
+
+	assert.Nil(t, wf)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Stage 2 of workflow must have a type", err.Message())
+}
+
+func Test_NewWorkflowFromRequestDto_ValidRequest_Returns_WorkflowAndFirstJob(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+
+	assert.EqualValues(t, WorkflowRunning, wf.Status)
+	assert.EqualValues(t, 0, wf.CurrentStage)
+	assert.EqualValues(t, wf.Id.String(), wf.CorrelationId)
+	assert.EqualValues(t, "encoding", firstJob.Type)
+	assert.EqualValues(t, wf.CorrelationId, firstJob.CorrelationId)
+	assert.EqualValues(t, []string{firstJob.Id.String()}, wf.StageJobIds())
+}
+
+func Test_Advance_StageRunning_Returns_NoChange(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusRunning
+
+	nextJob, changed, err := wf.Advance(firstJob)
+
+	assert.Nil(t, err)
+	assert.Nil(t, nextJob)
+	assert.EqualValues(t, false, changed)
+	assert.EqualValues(t, WorkflowRunning, wf.Status)
+}
+
+func Test_Advance_StageFinished_Returns_NextJobWithSource(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusFinished
+
+	nextJob, changed, err := wf.Advance(firstJob)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, changed)
+	assert.EqualValues(t, 1, wf.CurrentStage)
+	assert.EqualValues(t, "proxy", nextJob.Type)
+	assert.EqualValues(t, "/out/file.mp4", nextJob.Source)
+	assert.EqualValues(t, wf.CorrelationId, nextJob.CorrelationId)
+	assert.EqualValues(t, []string{nextJob.Id.String()}, wf.StageJobIds())
+	stages, _ := wf.StageList()
+	assert.EqualValues(t, "finished", stages[0].Status)
+	assert.EqualValues(t, 100, stages[0].Progress)
+	assert.EqualValues(t, firstJob.Name, stages[0].JobName)
+}
+
+func Test_Advance_LastStageFinished_Returns_WorkflowFinished(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusFinished
+	wf.CurrentStage = 2
+
+	nextJob, changed, err := wf.Advance(firstJob)
+
+	assert.Nil(t, err)
+	assert.Nil(t, nextJob)
+	assert.EqualValues(t, true, changed)
+	assert.EqualValues(t, WorkflowFinished, wf.Status)
+}
+
+func Test_Advance_StageFailed_Returns_WorkflowFailed(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusFailed
+
+	nextJob, changed, err := wf.Advance(firstJob)
+
+	assert.Nil(t, err)
+	assert.Nil(t, nextJob)
+	assert.EqualValues(t, true, changed)
+	assert.EqualValues(t, WorkflowFailed, wf.Status)
+	stages, _ := wf.StageList()
+	assert.EqualValues(t, "failed", stages[0].Status)
+}
+
+func Test_Advance_StageJobMissing_Returns_WorkflowFailed(t *testing.T) {
+	wf, _ := newTestWorkflow()
+
+	_, changed, err := wf.Advance(nil)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, changed)
+	assert.EqualValues(t, WorkflowFailed, wf.Status)
+}
+
+func Test_ToWorkflowResponseDto_Returns_StageProgress(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusFinished
+	nextJob, _, _ := wf.Advance(firstJob)
+	nextJob.Status = StatusRunning
+	nextJob.Progress = 50
+
+	response := wf.ToWorkflowResponseDto([]Job{*firstJob, *nextJob})
+
+	assert.EqualValues(t, "running", response.Status)
+	assert.EqualValues(t, 2, response.CurrentStage)
+	assert.EqualValues(t, 3, response.StageCount)
+	assert.EqualValues(t, 50, response.Progress)
+	assert.EqualValues(t, "finished", response.Stages[0].Status)
+	assert.EqualValues(t, 100, response.Stages[0].Progress)
+	assert.EqualValues(t, "running", response.Stages[1].Status)
+	assert.EqualValues(t, "pending", response.Stages[2].Status)
+}
+
+func Test_ToWorkflowResponseDto_StageJobCleanedUp_Returns_RecordedStage(t *testing.T) {
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = StatusFinished
+	nextJob, _, _ := wf.Advance(firstJob)
+	nextJob.Status = StatusRunning
+	nextJob.Progress = 50
+
+	response := wf.ToWorkflowResponseDto([]Job{*nextJob})
+
+	assert.EqualValues(t, "finished", response.Stages[0].Status)
+	assert.EqualValues(t, 100, response.Stages[0].Progress)
+	assert.EqualValues(t, firstJob.Name, response.Stages[0].Name)
+	assert.EqualValues(t, 50, response.Progress)
+}
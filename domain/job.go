@@ -1,51 +1,105 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+	"github.com/lib/pq"
 	"github.com/segmentio/ksuid"
 )
 
+const (
+	DefaultMaxAttempts    int32 = 1
+	DefaultBackoffSeconds int32 = 30
+	MaxBackoff                  = 24 * time.Hour
+)
+
+var (
+	keyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
 type Job struct {
-	Id            ksuid.KSUID `db:"id"`
-	CorrelationId string      `db:"correlation_id"`
-	Name          string      `db:"name"`
-	CreatedAt     time.Time   `db:"created_at"`
-	CreatedBy     string      `db:"created_by"`
-	ModifiedAt    time.Time   `db:"modified_at"`
-	ModifiedBy    string      `db:"modified_by"`
-	Status        JobStatus   `db:"status"`
-	Source        string      `db:"source"`
-	Destination   string      `db:"destination"`
-	Type          string      `db:"type"`
-	SubType       string      `db:"sub_type"`
-	Action        string      `db:"action"`
-	ActionDetails string      `db:"action_details"`
-	Progress      int32       `db:"progress"`
-	History       string      `db:"history"`
-	ExtraData     string      `db:"extra_data"`
-	Priority      int32       `db:"priority"`
-	Rank          int32       `db:"rank"`
+	Id               ksuid.KSUID      `db:"id"`
+	CorrelationId    string           `db:"correlation_id"`
+	Name             string           `db:"name"`
+	CreatedAt        time.Time        `db:"created_at"`
+	CreatedBy        string           `db:"created_by"`
+	ModifiedAt       time.Time        `db:"modified_at"`
+	ModifiedBy       string           `db:"modified_by"`
+	Status           JobStatus        `db:"status"`
+	Source           string           `db:"source"`
+	Destination      string           `db:"destination"`
+	Type             string           `db:"type"`
+	SubType          string           `db:"sub_type"`
+	Action           string           `db:"action"`
+	ActionDetails    string           `db:"action_details"`
+	Progress         int32            `db:"progress"`
+	History          string           `db:"-"`
+	ExtraData        *string          `db:"extra_data"`
+	Priority         int32            `db:"priority"`
+	Rank             int32            `db:"rank"`
+	LeaseOwner       string           `db:"lease_owner"`
+	LeaseExpiresAt   *time.Time       `db:"lease_expires_at"`
+	Attempts         int32            `db:"attempts"`
+	MaxAttempts      int32            `db:"max_attempts"`
+	BackoffPolicy    BackoffPolicy    `db:"backoff_policy"`
+	BackoffSeconds   int32            `db:"backoff_seconds"`
+	NotBefore        *time.Time       `db:"not_before"`
+	RunAt            *time.Time       `db:"run_at"`
+	DependsOn        pq.StringArray   `db:"depends_on"`
+	DependencyPolicy DependencyPolicy `db:"dependency_policy"`
+	ParentId         string           `db:"parent_id"`
+	CancelRequested  bool             `db:"cancel_requested"`
+	Eta              *time.Time       `db:"eta"`
+	ProgressMessage  string           `db:"progress_message"`
+	ProgressLoggedAt *time.Time       `db:"progress_logged_at"`
+	Result           *string          `db:"result"`
+	ErrorCode        string           `db:"error_code"`
+	ErrorMessage     string           `db:"error_message"`
+	ErrorRetryable   bool             `db:"error_retryable"`
+	Labels           JobLabels        `db:"labels"`
+	UniqueKey        string           `db:"unique_key"`
+	pendingEvents    []JobEvent
+}
+
+type JobDependency struct {
+	Id     string    `db:"id"`
+	Status JobStatus `db:"status"`
 }
 
 //go:generate mockgen -destination=../mocks/domain/mockJobRepository.go -package=domain github.com/johannes-kuhfuss/jobsvc/domain JobRepository
 type JobRepository interface {
 	Store(Job) api_error.ApiErr
+	NotifyJobCreated(string) api_error.ApiErr
 	FindAll(dto.SortAndFilterRequest) (*[]Job, int, api_error.ApiErr)
 	FindById(string) (*Job, api_error.ApiErr)
+	FindActiveByUniqueKey(string) (*Job, api_error.ApiErr)
+	FindAllDeadLetters(dto.SortAndFilterRequest) (*[]Job, int, api_error.ApiErr)
+	FindDeadLetterById(string) (*Job, api_error.ApiErr)
+	FindDependencies([]string) (*[]JobDependency, api_error.ApiErr)
+	FindChildren(string) (*[]Job, api_error.ApiErr)
+	AddChildren(string, []Job) (*Job, api_error.ApiErr)
+	RequeueDeadLetter(string) (*Job, api_error.ApiErr)
 	Update(string, dto.CreateUpdateJobRequest) (*Job, api_error.ApiErr)
 	DeleteById(string) api_error.ApiErr
-	Dequeue(string) (*Job, api_error.ApiErr)
-	SetStatusById(string, string, string) api_error.ApiErr
+	Dequeue(dto.DequeueRequest) (*[]Job, api_error.ApiErr)
+	Heartbeat(string, dto.HeartbeatRequest) (*Job, api_error.ApiErr)
+	SetStatusById(string, dto.UpdateJobStatusRequest, string) api_error.ApiErr
+	Cancel(string) (*Job, api_error.ApiErr)
+	SetProgressById(string, dto.UpdateJobProgressRequest) (*Job, api_error.ApiErr)
 	SetHistoryById(string, string) api_error.ApiErr
+	FindEvents(string, int, int) (*[]JobEvent, int, api_error.ApiErr)
 	DeleteAllJobs() api_error.ApiErr
 	CleanupJobs() api_error.ApiErr
+	ReleaseExpiredLeases() (int, api_error.ApiErr)
 }
 
 func NewJob(jobName string, jobType string) (*Job, api_error.ApiErr) {
@@ -56,27 +110,35 @@ func NewJob(jobName string, jobType string) (*Job, api_error.ApiErr) {
 	prio, _ := JobPriority.AsIndex("medium")
 
 	newJob := Job{
-		Id:            ksuid.New(),
-		CorrelationId: "",
-		Name:          createJobName(jobName),
-		CreatedAt:     date.GetNowUtc(),
-		CreatedBy:     "",
-		ModifiedAt:    date.GetNowUtc(),
-		ModifiedBy:    "",
-		Status:        StatusCreated,
-		Source:        "",
-		Destination:   "",
-		Type:          jobType,
-		SubType:       "",
-		Action:        "",
-		ActionDetails: "",
-		Progress:      0,
-		History:       "",
-		ExtraData:     "",
-		Priority:      prio,
-		Rank:          0,
-	}
-	newJob.AddHistory("Job created")
+		Id:               ksuid.New(),
+		CorrelationId:    "",
+		Name:             createJobName(jobName),
+		CreatedAt:        date.GetNowUtc(),
+		CreatedBy:        "",
+		ModifiedAt:       date.GetNowUtc(),
+		ModifiedBy:       "",
+		Status:           StatusCreated,
+		Source:           "",
+		Destination:      "",
+		Type:             jobType,
+		SubType:          "",
+		Action:           "",
+		ActionDetails:    "",
+		Progress:         0,
+		History:          "",
+		ExtraData:        nil,
+		Labels:           JobLabels{},
+		Priority:         prio,
+		Rank:             0,
+		LeaseOwner:       "",
+		Attempts:         0,
+		MaxAttempts:      DefaultMaxAttempts,
+		BackoffPolicy:    BackoffExponential,
+		BackoffSeconds:   DefaultBackoffSeconds,
+		DependsOn:        pq.StringArray{},
+		DependencyPolicy: DependencyFail,
+	}
+	newJob.recordEvent(EventCreated, ActorSystem, "Job created", "", StatusCreated)
 	return &newJob, nil
 }
 
@@ -92,41 +154,263 @@ func createJobName(name string) string {
 }
 
 func (j *Job) AddHistory(msg string) {
-	var sb strings.Builder
-	now, _ := date.GetNowLocalString("")
-	sb.WriteString(j.History)
-	sb.WriteString(*now)
-	sb.WriteString(": ")
-	sb.WriteString(msg)
-	sb.WriteString("\n")
-	j.History = sb.String()
+	j.AddEvent(EventNote, ActorSystem, msg)
+}
+
+func (j *Job) AddEvent(eventType JobEventType, actor string, msg string) {
+	j.recordEvent(eventType, actor, msg, "", "")
+}
+
+func (j *Job) ChangeStatus(newStatus JobStatus, actor string, msg string) {
+	oldStatus := j.Status
+	j.Status = newStatus
+	j.recordEvent(EventStatusChanged, actor, msg, oldStatus, newStatus)
+}
+
+func (j *Job) recordEvent(eventType JobEventType, actor string, msg string, oldStatus JobStatus, newStatus JobStatus) {
+	event := JobEvent{
+		JobId:     j.Id.String(),
+		CreatedAt: date.GetNowUtc(),
+		EventType: eventType,
+		Actor:     actor,
+		Message:   msg,
+		OldStatus: string(oldStatus),
+		NewStatus: string(newStatus),
+	}
+	j.pendingEvents = append(j.pendingEvents, event)
+	j.History = j.History + event.Render()
+}
+
+func (j *Job) PendingEvents() []JobEvent {
+	return j.pendingEvents
+}
+
+func (j *Job) ClearPendingEvents() {
+	j.pendingEvents = nil
+}
+
+func (j *Job) SetHistory(events []JobEvent) {
+	j.History = RenderHistory(events)
+}
+
+func (j *Job) Actor() string {
+	if j.LeaseOwner != "" {
+		return j.LeaseOwner
+	}
+	return ActorApi
+}
+
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+func (j *Job) RetryDelay() time.Duration {
+	delay := time.Duration(j.BackoffSeconds) * time.Second
+	if j.BackoffPolicy == BackoffExponential && j.Attempts > 1 {
+		for i := int32(1); i < j.Attempts && delay < MaxBackoff; i++ {
+			delay = delay * 2
+		}
+	}
+	if delay > MaxBackoff {
+		delay = MaxBackoff
+	}
+	return delay
 }
 
 func (j *Job) ToJobResponseDto() dto.JobResponse {
 	prio, _ := JobPriority.AsValue(j.Priority)
 	return dto.JobResponse{
-		Id:            j.Id.String(),
-		CorrelationId: j.CorrelationId,
-		Name:          j.Name,
-		CreatedAt:     j.CreatedAt,
-		CreatedBy:     j.CreatedBy,
-		ModifiedAt:    j.ModifiedAt,
-		ModifiedBy:    j.ModifiedBy,
-		Status:        string(j.Status),
-		Source:        j.Source,
-		Destination:   j.Destination,
-		Type:          j.Type,
-		SubType:       j.SubType,
-		Action:        j.Action,
-		ActionDetails: j.ActionDetails,
-		Progress:      j.Progress,
-		History:       j.History,
-		ExtraData:     j.ExtraData,
-		Priority:      prio,
-		Rank:          j.Rank,
+		Id:               j.Id.String(),
+		CorrelationId:    j.CorrelationId,
+		Name:             j.Name,
+		CreatedAt:        j.CreatedAt,
+		CreatedBy:        j.CreatedBy,
+		ModifiedAt:       j.ModifiedAt,
+		ModifiedBy:       j.ModifiedBy,
+		Status:           string(j.Status),
+		Source:           j.Source,
+		Destination:      j.Destination,
+		Type:             j.Type,
+		SubType:          j.SubType,
+		Action:           j.Action,
+		ActionDetails:    j.ActionDetails,
+		Progress:         j.Progress,
+		History:          j.History,
+		ExtraData:        jsonValue(j.ExtraData),
+		Priority:         prio,
+		Rank:             j.Rank,
+		LeaseOwner:       j.LeaseOwner,
+		LeaseExpiresAt:   j.LeaseExpiresAt,
+		Attempts:         j.Attempts,
+		MaxAttempts:      j.MaxAttempts,
+		BackoffPolicy:    string(j.BackoffPolicy),
+		BackoffSeconds:   j.BackoffSeconds,
+		NotBefore:        j.NotBefore,
+		RunAt:            j.RunAt,
+		DependsOn:        j.DependsOn,
+		DependencyPolicy: string(j.DependencyPolicy),
+		ParentId:         j.ParentId,
+		CancelRequested:  j.CancelRequested,
+		Eta:              j.Eta,
+		ProgressMessage:  j.ProgressMessage,
+		Result:           jsonValue(j.Result),
+		Error:            j.errorResponse(),
+		Labels:           j.Labels,
+		UniqueKey:        j.UniqueKey,
 	}
 }
 
+// ToJobRequestDto returns the request that would create a job with the same content.
+func (j *Job) ToJobRequestDto() dto.CreateUpdateJobRequest {
+	prio, _ := JobPriority.AsValue(j.Priority)
+	jobReq := dto.CreateUpdateJobRequest{
+		CorrelationId:    j.CorrelationId,
+		Name:             j.Name,
+		Source:           j.Source,
+		Destination:      j.Destination,
+		Type:             j.Type,
+		SubType:          j.SubType,
+		Action:           j.Action,
+		ActionDetails:    j.ActionDetails,
+		ExtraData:        jsonValue(j.ExtraData),
+		Priority:         prio,
+		Rank:             j.Rank,
+		MaxAttempts:      j.MaxAttempts,
+		BackoffPolicy:    string(j.BackoffPolicy),
+		BackoffSeconds:   j.BackoffSeconds,
+		DependsOn:        j.DependsOn,
+		DependencyPolicy: string(j.DependencyPolicy),
+		Labels:           j.Labels,
+		UniqueKey:        j.UniqueKey,
+	}
+	if j.RunAt != nil {
+		jobReq.RunAt = j.RunAt.Format(time.RFC3339)
+	}
+	return jobReq
+}
+
+func jsonValue(val *string) json.RawMessage {
+	if val == nil {
+		return nil
+	}
+	return json.RawMessage(*val)
+}
+
+func (j *Job) errorResponse() *dto.JobErrorResponse {
+	if j.ErrorCode == "" {
+		return nil
+	}
+	return &dto.JobErrorResponse{
+		Code:      j.ErrorCode,
+		Message:   j.ErrorMessage,
+		Retryable: j.ErrorRetryable,
+	}
+}
+
+func (j *Job) SetOutcome(statusReq dto.UpdateJobStatusRequest) {
+	if statusReq.HasResult() {
+		result := string(statusReq.Result)
+		j.Result = &result
+	}
+	if statusReq.Error != nil {
+		j.ErrorCode = statusReq.Error.Code
+		j.ErrorMessage = statusReq.Error.Message
+		j.ErrorRetryable = statusReq.IsRetryable()
+	}
+}
+
+func (j *Job) ClearOutcome() {
+	j.Result = nil
+	j.ErrorCode = ""
+	j.ErrorMessage = ""
+	j.ErrorRetryable = false
+}
+
+func (j *Job) IsDone() bool {
+	return j.Status == StatusFinished || j.Status == StatusFailed || j.Status == StatusCancelled
+}
+
+func (j *Job) IsLeased() bool {
+	return j.Status == StatusRunning && j.LeaseOwner != ""
+}
+
+func (j *Job) AggregateChildren(children []Job) bool {
+	if len(children) == 0 {
+		return false
+	}
+	var totalProgress int32
+	failed := 0
+	done := 0
+	for _, child := range children {
+		if child.IsDone() {
+			totalProgress += 100
+			done++
+			if child.Status != StatusFinished {
+				failed++
+			}
+		} else {
+			totalProgress += child.Progress
+		}
+	}
+	j.Progress = totalProgress / int32(len(children))
+	if done < len(children) {
+		return false
+	}
+	if failed > 0 {
+		j.ChangeStatus(StatusFailed, ActorSystem, fmt.Sprintf("%d of %d child jobs failed", failed, len(children)))
+	} else {
+		j.ChangeStatus(StatusFinished, ActorSystem, fmt.Sprintf("All %d child jobs finished", len(children)))
+	}
+	return true
+}
+
+func (j *Job) DependencyState(deps []JobDependency) DependencyState {
+	statuses := make(map[string]JobStatus)
+	for _, dep := range deps {
+		statuses[dep.Id] = dep.Status
+	}
+	state := DependencySatisfied
+	for _, id := range j.DependsOn {
+		status, found := statuses[id]
+		switch {
+		case status == StatusFailed || status == StatusCancelled:
+			if j.DependencyPolicy == DependencyFail {
+				return DependencyFailed
+			}
+			state = DependencyBlocked
+		case !found:
+			// Cleanup only removes a failed or cancelled prerequisite once no pending job depends on it.
+			continue
+		case status != StatusFinished && state == DependencySatisfied:
+			state = DependencyWaiting
+		}
+	}
+	return state
+}
+
+func (j *Job) ToJobResponseDtoWithDependencies(deps []JobDependency) dto.JobResponse {
+	response := j.ToJobResponseDto()
+	if len(j.DependsOn) == 0 {
+		return response
+	}
+	statuses := make(map[string]JobStatus)
+	for _, dep := range deps {
+		statuses[dep.Id] = dep.Status
+	}
+	for _, id := range j.DependsOn {
+		status, found := statuses[id]
+		if !found {
+			status = "unknown"
+		}
+		response.Dependencies = append(response.Dependencies, dto.JobDependencyResponse{
+			Id:     id,
+			Status: string(status),
+		})
+	}
+	response.DependencyState = string(j.DependencyState(deps))
+	return response
+}
+
 func NewJobFromJobRequestDto(jobReq dto.CreateUpdateJobRequest) (*Job, api_error.ApiErr) {
 	var prio int32
 	newJob, err := NewJob(jobReq.Name, jobReq.Type)
@@ -147,22 +431,165 @@ func NewJobFromJobRequestDto(jobReq dto.CreateUpdateJobRequest) (*Job, api_error
 	newJob.SubType = jobReq.SubType
 	newJob.Action = jobReq.Action
 	newJob.ActionDetails = jobReq.ActionDetails
-	newJob.ExtraData = jobReq.ExtraData
+	if jobReq.HasExtraData() {
+		extraData := string(jobReq.ExtraData)
+		newJob.ExtraData = &extraData
+	}
+	if len(jobReq.Labels) > 0 {
+		newJob.Labels = JobLabels(jobReq.Labels)
+	}
+	newJob.UniqueKey = jobReq.UniqueKey
 	newJob.Priority = prio
 	if jobReq.Rank >= 0 {
 		newJob.Rank = jobReq.Rank
 	} else {
 		newJob.Rank = 0
 	}
+	if jobReq.MaxAttempts > 0 {
+		newJob.MaxAttempts = jobReq.MaxAttempts
+	}
+	if jobReq.BackoffPolicy != "" {
+		if !IsValidBackoffPolicy(jobReq.BackoffPolicy) {
+			return nil, api_error.NewBadRequestError(fmt.Sprintf("Backoff policy %v does not exist", jobReq.BackoffPolicy))
+		}
+		newJob.BackoffPolicy = BackoffPolicy(jobReq.BackoffPolicy)
+	}
+	if jobReq.BackoffSeconds > 0 {
+		newJob.BackoffSeconds = jobReq.BackoffSeconds
+	}
+	if jobReq.DependencyPolicy != "" {
+		if !IsValidDependencyPolicy(jobReq.DependencyPolicy) {
+			return nil, api_error.NewBadRequestError(fmt.Sprintf("Dependency policy %v does not exist", jobReq.DependencyPolicy))
+		}
+		newJob.DependencyPolicy = DependencyPolicy(jobReq.DependencyPolicy)
+	}
+	for _, depId := range jobReq.DependsOn {
+		if _, err := ksuid.Parse(depId); err != nil {
+			return nil, api_error.NewBadRequestError(fmt.Sprintf("Prerequisite job id %v should be a ksuid", depId))
+		}
+		if !misc.SliceContainsString(newJob.DependsOn, depId) {
+			newJob.DependsOn = append(newJob.DependsOn, depId)
+		}
+	}
+	if jobReq.RunAt != "" {
+		runAt, err := ParseRunAt(jobReq.RunAt)
+		if err != nil {
+			return nil, err
+		}
+		newJob.ScheduleAt(runAt)
+		if newJob.Status == StatusScheduled {
+			newJob.AddHistory(fmt.Sprintf("Job scheduled to run at %v", runAt.Format(time.RFC3339)))
+		}
+	}
 
 	return newJob, nil
 }
 
+func ParseRunAt(runAtStr string) (*time.Time, api_error.ApiErr) {
+	runAt, err := time.Parse(time.RFC3339, runAtStr)
+	if err != nil {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("Run at time %v is not a valid RFC3339 timestamp", runAtStr))
+	}
+	runAt = runAt.UTC()
+	return &runAt, nil
+}
+
+func ParseEta(etaStr string) (*time.Time, api_error.ApiErr) {
+	eta, err := time.Parse(time.RFC3339, etaStr)
+	if err != nil {
+		return nil, api_error.NewBadRequestError(fmt.Sprintf("ETA %v is not a valid RFC3339 timestamp", etaStr))
+	}
+	eta = eta.UTC()
+	return &eta, nil
+}
+
+func (j *Job) ReportProgress(progReq dto.UpdateJobProgressRequest, historyInterval time.Duration) api_error.ApiErr {
+	if j.Status != StatusRunning {
+		return api_error.NewProcessingConflictError(fmt.Sprintf("Job with id %v is %v and cannot report progress", j.Id.String(), j.Status))
+	}
+	if progReq.Progress == nil {
+		return api_error.NewBadRequestError("Progress request must have a progress value")
+	}
+	progress := *progReq.Progress
+	if progress < j.Progress {
+		return api_error.NewProcessingConflictError(fmt.Sprintf("Progress %d of job with id %v is lower than its current progress %d", progress, j.Id.String(), j.Progress))
+	}
+	if progReq.Eta != "" {
+		eta, err := ParseEta(progReq.Eta)
+		if err != nil {
+			return err
+		}
+		j.Eta = eta
+	}
+	now := date.GetNowUtc()
+	stepChanged := progReq.Message != "" && progReq.Message != j.ProgressMessage
+	completed := progress == 100 && j.Progress < 100
+	j.Progress = progress
+	j.ModifiedAt = now
+	if progReq.Message != "" {
+		j.ProgressMessage = progReq.Message
+	}
+	if stepChanged || completed || j.ProgressLoggedAt == nil || now.Sub(*j.ProgressLoggedAt) >= historyInterval {
+		j.AddEvent(EventProgress, j.Actor(), j.progressHistory())
+		j.ProgressLoggedAt = &now
+	}
+	return nil
+}
+
+func (j *Job) progressHistory() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Progress %d%%", j.Progress))
+	if j.ProgressMessage != "" {
+		sb.WriteString(fmt.Sprintf(": %v", j.ProgressMessage))
+	}
+	if j.Eta != nil {
+		sb.WriteString(fmt.Sprintf(". ETA %v", j.Eta.Format(time.RFC3339)))
+	}
+	return sb.String()
+}
+
+func (j *Job) ScheduleAt(runAt *time.Time) {
+	j.RunAt = runAt
+	if j.Status != StatusCreated && j.Status != StatusScheduled {
+		return
+	}
+	if runAt != nil && runAt.After(date.GetNowUtc()) {
+		j.Status = StatusScheduled
+	} else {
+		j.Status = StatusCreated
+	}
+}
+
 func GetJobDbFieldsAsStrings() []string {
 	var fields []string
 	val := reflect.ValueOf(Job{})
 	for i := 0; i < val.Type().NumField(); i++ {
-		fields = append(fields, string(val.Type().Field(i).Tag.Get("db")))
+		field := val.Type().Field(i).Tag.Get("db")
+		if field != "" && field != "-" {
+			fields = append(fields, field)
+		}
 	}
 	return fields
 }
+
+func IsJsonField(field string) bool {
+	return field == "extra_data" || field == "result"
+}
+
+func SplitJsonPath(key string) (string, []string, bool) {
+	keySplit := strings.Split(key, ".")
+	if len(keySplit) < 2 || !IsJsonField(keySplit[0]) {
+		return "", nil, false
+	}
+	for _, elem := range keySplit[1:] {
+		if !keyRegex.MatchString(elem) {
+			return "", nil, false
+		}
+	}
+	return keySplit[0], keySplit[1:], true
+}
+
+func IsValidExtraData(extraData json.RawMessage) bool {
+	var obj map[string]interface{}
+	return json.Unmarshal(extraData, &obj) == nil && obj != nil
+}
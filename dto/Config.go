@@ -21,6 +21,9 @@ type ConfigResp struct {
 	DbName                     string
 	DbJobTable                 string
 	MaxResultLimit             int
+	PriorityAgingPerMinute     float64
+	LeaseDurationSeconds       int
+	LeaseReapCycleSeconds      int
 	StartDate                  time.Time
 }
 
@@ -40,6 +43,9 @@ func GetConfig(cfg *config.AppConfig) ConfigResp {
 		DbName:                     cfg.Db.Name,
 		DbJobTable:                 cfg.Db.JobTable,
 		MaxResultLimit:             cfg.Misc.MaxResultLimit,
+		PriorityAgingPerMinute:     cfg.Scheduling.PriorityAgingPerMinute,
+		LeaseDurationSeconds:       cfg.Lease.DurationSeconds,
+		LeaseReapCycleSeconds:      cfg.Lease.ReapCycleSeconds,
 		StartDate:                  cfg.RunTime.StartDate,
 	}
 	if cfg.Server.Host == "" {
@@ -0,0 +1,5 @@
+package dto
+
+type CreateChildJobsRequest struct {
+	Children []CreateUpdateJobRequest `json:"children"`
+}
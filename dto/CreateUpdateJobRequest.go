@@ -1,15 +1,32 @@
 package dto
 
+import "encoding/json"
+
 type CreateUpdateJobRequest struct {
-	CorrelationId string `json:"correlationId" san:"trim,xss"`
-	Name          string `json:"name" san:"trim,xss"`
-	Source        string `json:"source" san:"trim,xss"`
-	Destination   string `json:"destination" san:"trim,xss"`
-	Type          string `json:"type" san:"trim,xss"`
-	SubType       string `json:"sub_type" san:"trim,xss"`
-	Action        string `json:"action" san:"trim,xss"`
-	ActionDetails string `json:"action_details" san:"trim,xss"`
-	ExtraData     string `json:"extra_data" san:"trim,xss"`
-	Priority      string `json:"priority" san:"trim,xss,lower"`
-	Rank          int32  `json:"rank" san:"def=0,min=0,max=2147483647"`
+	CorrelationId    string            `json:"correlationId" san:"trim,xss"`
+	Name             string            `json:"name" san:"trim,xss"`
+	Source           string            `json:"source" san:"trim,xss"`
+	Destination      string            `json:"destination" san:"trim,xss"`
+	Type             string            `json:"type" san:"trim,xss"`
+	SubType          string            `json:"sub_type" san:"trim,xss"`
+	Action           string            `json:"action" san:"trim,xss"`
+	ActionDetails    string            `json:"action_details" san:"trim,xss"`
+	ExtraData        json.RawMessage   `json:"extra_data,omitempty"`
+	Priority         string            `json:"priority" san:"trim,xss,lower"`
+	Rank             int32             `json:"rank" san:"def=0,min=0,max=2147483647"`
+	MaxAttempts      int32             `json:"max_attempts" san:"def=0,min=0,max=2147483647"`
+	BackoffPolicy    string            `json:"backoff_policy" san:"trim,xss,lower"`
+	BackoffSeconds   int32             `json:"backoff_seconds" san:"def=0,min=0,max=2147483647"`
+	RunAt            string            `json:"runAt" san:"trim,xss"`
+	DependsOn        []string          `json:"depends_on" san:"trim,xss"`
+	DependencyPolicy string            `json:"dependency_policy" san:"trim,xss,lower"`
+	Labels           map[string]string `json:"labels"`
+	Template         string            `json:"template" san:"trim,xss"`
+	TemplateVersion  int32             `json:"template_version" san:"def=0,min=0,max=2147483647"`
+	UniqueKey        string            `json:"unique_key" san:"trim,xss"`
+	UniquePolicy     string            `json:"unique_policy" san:"trim,xss,lower"`
+}
+
+func (jobReq CreateUpdateJobRequest) HasExtraData() bool {
+	return len(jobReq.ExtraData) > 0 && string(jobReq.ExtraData) != "null"
 }
@@ -0,0 +1,7 @@
+package dto
+
+type CreateUpdateJobTemplateRequest struct {
+	Name        string                  `json:"name" san:"trim,xss"`
+	Description string                  `json:"description" san:"trim,xss"`
+	Job         *CreateUpdateJobRequest `json:"job"`
+}
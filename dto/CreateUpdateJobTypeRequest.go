@@ -0,0 +1,13 @@
+package dto
+
+import "encoding/json"
+
+type CreateUpdateJobTypeRequest struct {
+	Name            string          `json:"name" san:"trim,xss"`
+	Description     string          `json:"description" san:"trim,xss"`
+	SubTypes        []string        `json:"sub_types" san:"trim,xss"`
+	Actions         []string        `json:"actions" san:"trim,xss"`
+	RequiredFields  []string        `json:"required_fields" san:"trim,xss"`
+	UniqueFields    []string        `json:"unique_fields" san:"trim,xss"`
+	ExtraDataSchema json.RawMessage `json:"extra_data_schema,omitempty"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+type CreateUpdateScheduleRequest struct {
+	Name            string                  `json:"name" san:"trim,xss"`
+	CronExpr        string                  `json:"cron" san:"trim"`
+	TimeZone        string                  `json:"time_zone" san:"trim,xss"`
+	Enabled         *bool                   `json:"enabled"`
+	MissedRunPolicy string                  `json:"missed_run_policy" san:"trim,xss,lower"`
+	Job             *CreateUpdateJobRequest `json:"job"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type CreateWorkflowRequest struct {
+	Name          string                   `json:"name" san:"trim,xss"`
+	CorrelationId string                   `json:"correlationId" san:"trim,xss"`
+	Stages        []CreateUpdateJobRequest `json:"stages"`
+}
@@ -1,5 +1,26 @@
 package dto
 
 type DequeueRequest struct {
-	Type string `json:"type" san:"trim,xss"`
+	Type          string            `json:"type" san:"trim,xss"`
+	Types         []string          `json:"types" san:"trim,xss"`
+	SubTypes      []string          `json:"sub_types" san:"trim,xss"`
+	Actions       []string          `json:"actions" san:"trim,xss"`
+	Worker        string            `json:"worker" san:"trim,xss"`
+	LeaseSeconds  int               `json:"lease_seconds" san:"def=0,min=0"`
+	Wait          int               `json:"wait" san:"def=0,min=0"`
+	Count         int               `json:"count" san:"def=0,min=0"`
+	Labels        map[string]string `json:"labels"`
+	ExcludeLabels map[string]string `json:"exclude_labels"`
+}
+
+func (dqReq DequeueRequest) AllTypes() []string {
+	types := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, jobType := range append([]string{dqReq.Type}, dqReq.Types...) {
+		if jobType != "" && !seen[jobType] {
+			seen[jobType] = true
+			types = append(types, jobType)
+		}
+	}
+	return types
 }
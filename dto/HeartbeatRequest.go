@@ -0,0 +1,8 @@
+package dto
+
+type HeartbeatRequest struct {
+	Worker       string `json:"worker" san:"trim,xss"`
+	LeaseSeconds int    `json:"lease_seconds" san:"def=0,min=0"`
+	Progress     *int32 `json:"progress"`
+	Message      string `json:"message" san:"trim,xss"`
+}
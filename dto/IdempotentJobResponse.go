@@ -0,0 +1,6 @@
+package dto
+
+type IdempotentJobResponse struct {
+	StatusCode int
+	Job        JobResponse
+}
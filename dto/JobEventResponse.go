@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type JobEventResponse struct {
+	Id        int64     `json:"id"`
+	JobId     string    `json:"jobId"`
+	CreatedAt time.Time `json:"createdAt"`
+	EventType string    `json:"eventType"`
+	Actor     string    `json:"actor"`
+	Message   string    `json:"message"`
+	OldStatus string    `json:"oldStatus,omitempty"`
+	NewStatus string    `json:"newStatus,omitempty"`
+}
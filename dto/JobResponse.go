@@ -1,25 +1,61 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type JobResponse struct {
-	Id            string    `json:"id"`
-	CorrelationId string    `json:"correlationId"`
-	Name          string    `json:"name"`
-	CreatedAt     time.Time `json:"createdAt"`
-	CreatedBy     string    `json:"createdBy"`
-	ModifiedAt    time.Time `json:"modifiedAt"`
-	ModifiedBy    string    `json:"modifiedBy"`
-	Status        string    `json:"status"`
-	Source        string    `json:"source"`
-	Destination   string    `json:"destination"`
-	Type          string    `json:"type"`
-	SubType       string    `json:"subType"`
-	Action        string    `json:"action"`
-	ActionDetails string    `json:"actionDetails"`
-	Progress      int32     `json:"progress"`
-	History       string    `json:"history"`
-	ExtraData     string    `json:"extraData"`
-	Priority      string    `json:"priority"`
-	Rank          int32     `json:"rank"`
+	Id               string                  `json:"id"`
+	CorrelationId    string                  `json:"correlationId"`
+	Name             string                  `json:"name"`
+	CreatedAt        time.Time               `json:"createdAt"`
+	CreatedBy        string                  `json:"createdBy"`
+	ModifiedAt       time.Time               `json:"modifiedAt"`
+	ModifiedBy       string                  `json:"modifiedBy"`
+	Status           string                  `json:"status"`
+	Source           string                  `json:"source"`
+	Destination      string                  `json:"destination"`
+	Type             string                  `json:"type"`
+	SubType          string                  `json:"subType"`
+	Action           string                  `json:"action"`
+	ActionDetails    string                  `json:"actionDetails"`
+	Progress         int32                   `json:"progress"`
+	History          string                  `json:"history"`
+	ExtraData        json.RawMessage         `json:"extraData,omitempty"`
+	Priority         string                  `json:"priority"`
+	Rank             int32                   `json:"rank"`
+	LeaseOwner       string                  `json:"leaseOwner"`
+	LeaseExpiresAt   *time.Time              `json:"leaseExpiresAt,omitempty"`
+	Attempts         int32                   `json:"attempts"`
+	MaxAttempts      int32                   `json:"maxAttempts"`
+	BackoffPolicy    string                  `json:"backoffPolicy"`
+	BackoffSeconds   int32                   `json:"backoffSeconds"`
+	NotBefore        *time.Time              `json:"notBefore,omitempty"`
+	RunAt            *time.Time              `json:"runAt,omitempty"`
+	DependsOn        []string                `json:"dependsOn,omitempty"`
+	DependencyPolicy string                  `json:"dependencyPolicy"`
+	DependencyState  string                  `json:"dependencyState,omitempty"`
+	Dependencies     []JobDependencyResponse `json:"dependencies,omitempty"`
+	ParentId         string                  `json:"parentId,omitempty"`
+	Children         []JobResponse           `json:"children,omitempty"`
+	CancelRequested  bool                    `json:"cancelRequested"`
+	Eta              *time.Time              `json:"eta,omitempty"`
+	ProgressMessage  string                  `json:"progressMessage,omitempty"`
+	Result           json.RawMessage         `json:"result,omitempty"`
+	Error            *JobErrorResponse       `json:"error,omitempty"`
+	Labels           map[string]string       `json:"labels,omitempty"`
+	UniqueKey        string                  `json:"uniqueKey,omitempty"`
+	Deduplicated     bool                    `json:"deduplicated,omitempty"`
+}
+
+type JobDependencyResponse struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type JobErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+type JobTemplateResponse struct {
+	Name        string                 `json:"name"`
+	Version     int32                  `json:"version"`
+	Description string                 `json:"description"`
+	Job         CreateUpdateJobRequest `json:"job"`
+	CreatedAt   time.Time              `json:"createdAt"`
+}
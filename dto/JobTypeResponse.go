@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobTypeResponse struct {
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	SubTypes        []string        `json:"subTypes"`
+	Actions         []string        `json:"actions"`
+	RequiredFields  []string        `json:"requiredFields"`
+	UniqueFields    []string        `json:"uniqueFields"`
+	ExtraDataSchema json.RawMessage `json:"extraDataSchema,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	ModifiedAt      time.Time       `json:"modifiedAt"`
+}
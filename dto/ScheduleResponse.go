@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type ScheduleResponse struct {
+	Id              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	CronExpr        string                 `json:"cron"`
+	TimeZone        string                 `json:"timeZone"`
+	Enabled         bool                   `json:"enabled"`
+	MissedRunPolicy string                 `json:"missedRunPolicy"`
+	Job             CreateUpdateJobRequest `json:"job"`
+	LastRunAt       *time.Time             `json:"lastRunAt,omitempty"`
+	NextRunAt       *time.Time             `json:"nextRunAt,omitempty"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	ModifiedAt      time.Time              `json:"modifiedAt"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type StatusTransitionResponse struct {
+	Status   string   `json:"status"`
+	Next     []string `json:"next"`
+	Terminal bool     `json:"terminal"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type UpdateJobProgressRequest struct {
+	Progress *int32 `json:"progress"`
+	Message  string `json:"message" san:"trim,xss"`
+	Eta      string `json:"eta" san:"trim,xss"`
+}
@@ -1,6 +1,24 @@
 package dto
 
+import "encoding/json"
+
 type UpdateJobStatusRequest struct {
-	Status  string `json:"status" san:"trim,xss,lower"`
-	Message string `json:"message" san:"trim,xss"`
+	Status  string           `json:"status" san:"trim,xss,lower"`
+	Message string           `json:"message" san:"trim,xss"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *JobErrorRequest `json:"error,omitempty"`
+}
+
+type JobErrorRequest struct {
+	Code      string `json:"code" san:"trim,xss"`
+	Message   string `json:"message" san:"trim,xss"`
+	Retryable *bool  `json:"retryable,omitempty"`
+}
+
+func (statusReq UpdateJobStatusRequest) HasResult() bool {
+	return len(statusReq.Result) > 0 && string(statusReq.Result) != "null"
+}
+
+func (statusReq UpdateJobStatusRequest) IsRetryable() bool {
+	return statusReq.Error == nil || statusReq.Error.Retryable == nil || *statusReq.Error.Retryable
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type WorkflowResponse struct {
+	Id            string                  `json:"id"`
+	Name          string                  `json:"name"`
+	CorrelationId string                  `json:"correlationId"`
+	Status        string                  `json:"status"`
+	CurrentStage  int32                   `json:"currentStage"`
+	StageCount    int32                   `json:"stageCount"`
+	Progress      int32                   `json:"progress"`
+	Stages        []WorkflowStageResponse `json:"stages"`
+	CreatedAt     time.Time               `json:"createdAt"`
+	ModifiedAt    time.Time               `json:"modifiedAt"`
+}
+
+type WorkflowStageResponse struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	SubType  string `json:"subType"`
+	Action   string `json:"action"`
+	JobId    string `json:"jobId,omitempty"`
+	Status   string `json:"status"`
+	Progress int32  `json:"progress"`
+}
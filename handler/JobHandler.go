@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/jobsvc/service"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
@@ -14,14 +15,20 @@ import (
 )
 
 type JobHandler struct {
-	Service service.JobService
-	Cfg     *config.AppConfig
+	Service         service.JobService
+	TypeService     service.JobTypeService
+	TemplateService service.JobTemplateService
+	IdemService     service.IdempotencyService
+	Cfg             *config.AppConfig
 }
 
-func NewJobHandler(cfg *config.AppConfig, svc service.JobService) JobHandler {
+func NewJobHandler(cfg *config.AppConfig, svc service.JobService, typeSvc service.JobTypeService, templateSvc service.JobTemplateService, idemSvc service.IdempotencyService) JobHandler {
 	return JobHandler{
-		Cfg:     cfg,
-		Service: svc,
+		Cfg:             cfg,
+		Service:         svc,
+		TypeService:     typeSvc,
+		TemplateService: templateSvc,
+		IdemService:     idemSvc,
 	}
 }
 
@@ -36,6 +43,16 @@ func (jh *JobHandler) getJobId(jobIdParam string) (string, api_error.ApiErr) {
 	return jobId.String(), nil
 }
 
+func (jh *JobHandler) getIdempotencyKey(keyHeader string) (string, api_error.ApiErr) {
+	keyHeader = jh.Cfg.RunTime.BmPolicy.Sanitize(keyHeader)
+	if len(keyHeader) > domain.MaxIdempotencyKeyLength {
+		msg := fmt.Sprintf("Idempotency key must not be longer than %d characters", domain.MaxIdempotencyKeyLength)
+		logger.Error(msg, nil)
+		return "", api_error.NewBadRequestError(msg)
+	}
+	return keyHeader, nil
+}
+
 func (jh *JobHandler) CreateJob(c *gin.Context) {
 	var newJobReq dto.CreateUpdateJobRequest
 	if err := c.ShouldBindJSON(&newJobReq); err != nil {
@@ -46,21 +63,62 @@ func (jh *JobHandler) CreateJob(c *gin.Context) {
 		return
 	}
 	jh.Cfg.RunTime.Sani.Sanitize(&newJobReq)
-	err := validateCreateJobRequest(newJobReq)
+	idemKey, err := jh.getIdempotencyKey(c.GetHeader("Idempotency-Key"))
 	if err != nil {
-		msg := "Could not validate input data for create job request"
-		logger.Error(msg, err)
-		apiErr := api_error.NewBadRequestError(msg)
-		c.JSON(apiErr.StatusCode(), apiErr)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	origJobReq := newJobReq
+	if newJobReq.Template != "" && jh.TemplateService != nil {
+		resolvedReq, err := jh.TemplateService.ResolveJobRequest(newJobReq)
+		if err != nil {
+			logger.Error("Service error while resolving job template", err)
+			c.JSON(err.StatusCode(), err)
+			return
+		}
+		newJobReq = *resolvedReq
+	}
+	err = validateCreateJobRequest(newJobReq, jh.TypeService)
+	if err != nil {
+		logger.Error("Could not validate input data for create job request", err)
+		c.JSON(err.StatusCode(), err)
 		return
 	}
+	useIdemKey := idemKey != "" && jh.IdemService != nil
+	if useIdemKey {
+		stored, err := jh.IdemService.BeginJobRequest(idemKey, origJobReq)
+		if err != nil {
+			logger.Error("Service error while checking idempotency key", err)
+			c.JSON(err.StatusCode(), err)
+			return
+		}
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.JSON(stored.StatusCode, stored.Job)
+			return
+		}
+	}
 	result, err := jh.Service.CreateJob(newJobReq)
 	if err != nil {
 		logger.Error("Service error while creating job", err)
+		if useIdemKey {
+			if abortErr := jh.IdemService.AbortJobRequest(idemKey); abortErr != nil {
+				logger.Error("Service error while releasing idempotency key", abortErr)
+			}
+		}
 		c.JSON(err.StatusCode(), err)
 		return
 	}
-	c.JSON(http.StatusCreated, result)
+	status := http.StatusCreated
+	if result.Deduplicated {
+		status = http.StatusOK
+	}
+	if useIdemKey {
+		if complErr := jh.IdemService.CompleteJobRequest(idemKey, status, *result); complErr != nil {
+			logger.Error("Service error while storing idempotency key result", complErr)
+		}
+	}
+	c.JSON(status, result)
 }
 
 func (jh *JobHandler) GetAllJobs(c *gin.Context) {
@@ -94,6 +152,109 @@ func (jh *JobHandler) GetJobById(c *gin.Context) {
 		c.JSON(err.StatusCode(), err)
 		return
 	}
+	if c.Query("children") == "true" {
+		children, err := jh.Service.GetChildJobs(jobId)
+		if err != nil {
+			logger.Error("Service error while getting child jobs", err)
+			c.JSON(err.StatusCode(), err)
+			return
+		}
+		job.Children = *children
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (jh *JobHandler) CreateChildJobs(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var childReq dto.CreateChildJobsRequest
+	if err := c.ShouldBindJSON(&childReq); err != nil {
+		msg := "Invalid JSON body in create child jobs request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	jh.Cfg.RunTime.Sani.Sanitize(&childReq)
+	err = validateCreateChildJobsRequest(childReq, jh.TypeService)
+	if err != nil {
+		logger.Error("Could not validate input data for create child jobs request", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	children, err := jh.Service.CreateChildJobs(jobId, childReq)
+	if err != nil {
+		logger.Error("Service error while creating child jobs", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusCreated, children)
+}
+
+func (jh *JobHandler) GetChildJobs(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	children, err := jh.Service.GetChildJobs(jobId)
+	if err != nil {
+		logger.Error("Service error while getting child jobs", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, children)
+}
+
+func (jh *JobHandler) GetAllDeadLetters(c *gin.Context) {
+	safParams := c.Request.URL.Query()
+	safQuery, err := jh.validateSortAndFilterRequest(safParams, jh.Cfg.Misc.MaxResultLimit)
+	if err != nil {
+		logger.Error("Error parsing query parameters", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	jobs, totalCount, err := jh.Service.GetAllDeadLetters(*safQuery)
+	if err != nil {
+		logger.Error("Service error while getting all dead-lettered jobs", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	countStr := fmt.Sprintf("%v", totalCount)
+	c.Header("X-Total-Count", countStr)
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (jh *JobHandler) GetDeadLetterById(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	job, err := jh.Service.GetDeadLetterById(jobId)
+	if err != nil {
+		logger.Error("Service error while getting dead-lettered job by id", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (jh *JobHandler) RequeueDeadLetter(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	job, err := jh.Service.RequeueDeadLetter(jobId)
+	if err != nil {
+		logger.Error("Service error while requeuing dead-lettered job", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
 	c.JSON(http.StatusOK, job)
 }
 
@@ -122,7 +283,7 @@ func (jh JobHandler) Dequeue(c *gin.Context) {
 		return
 	}
 	jh.Cfg.RunTime.Sani.Sanitize(&dqReq)
-	err := validateDequeueRequest(dqReq)
+	err := validateDequeueRequest(dqReq, jh.Cfg.Dequeue.MaxWaitSeconds, jh.Cfg.Dequeue.MaxCount)
 	if err != nil {
 		msg := "Could not validate input data for dequeue request"
 		logger.Error(msg, err)
@@ -130,7 +291,7 @@ func (jh JobHandler) Dequeue(c *gin.Context) {
 		c.JSON(apiErr.StatusCode(), apiErr)
 		return
 	}
-	result, err := jh.Service.Dequeue(dqReq)
+	result, err := jh.Service.Dequeue(c.Request.Context(), dqReq)
 	if err != nil {
 		logger.Error("Service error while dequeuing job", err)
 		c.JSON(err.StatusCode(), err)
@@ -139,6 +300,38 @@ func (jh JobHandler) Dequeue(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+func (jh JobHandler) Heartbeat(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var hbReq dto.HeartbeatRequest
+	if err := c.ShouldBindJSON(&hbReq); err != nil {
+		msg := "Invalid JSON body in heartbeat request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	jh.Cfg.RunTime.Sani.Sanitize(&hbReq)
+	err = validateHeartbeatRequest(hbReq)
+	if err != nil {
+		msg := "Could not validate input data for heartbeat request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := jh.Service.Heartbeat(jobId, hbReq)
+	if err != nil {
+		logger.Error("Service error while processing heartbeat", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 func (jh JobHandler) UpdateJob(c *gin.Context) {
 	jobId, err := jh.getJobId(c.Param("job_id"))
 	if err != nil {
@@ -154,12 +347,19 @@ func (jh JobHandler) UpdateJob(c *gin.Context) {
 		return
 	}
 	jh.Cfg.RunTime.Sani.Sanitize(&updJobReq)
-	err = validateUpdateJobRequest(updJobReq)
+	var currentJob *dto.JobResponse
+	if jh.TypeService != nil {
+		currentJob, err = jh.Service.GetJobById(jobId)
+		if err != nil {
+			logger.Error("Service error while updating job", err)
+			c.JSON(err.StatusCode(), err)
+			return
+		}
+	}
+	err = validateUpdateJobRequest(updJobReq, currentJob, jh.TypeService)
 	if err != nil {
-		msg := "Could not validate input data for update job request"
-		logger.Error(msg, err)
-		apiErr := api_error.NewBadRequestError(msg)
-		c.JSON(apiErr.StatusCode(), apiErr)
+		logger.Error("Could not validate input data for update job request", err)
+		c.JSON(err.StatusCode(), err)
 		return
 	}
 	result, err := jh.Service.UpdateJob(jobId, updJobReq)
@@ -203,6 +403,57 @@ func (jh JobHandler) SetStatusById(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+func (jh JobHandler) CancelJob(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	job, err := jh.Service.CancelJob(jobId)
+	if err != nil {
+		logger.Error("Service error while cancelling job", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (jh JobHandler) SetProgressById(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var progReq dto.UpdateJobProgressRequest
+	if err := c.ShouldBindJSON(&progReq); err != nil {
+		msg := "Invalid JSON body in update job progress request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	jh.Cfg.RunTime.Sani.Sanitize(&progReq)
+	err = validateUpdateJobProgressRequest(progReq)
+	if err != nil {
+		msg := "Could not validate input data for update job progress request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	job, err := jh.Service.SetProgressById(jobId, progReq)
+	if err != nil {
+		logger.Error("Service error while setting job progress by id", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (jh JobHandler) GetStatusTransitions(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.StatusTransitionsToDto())
+}
+
 func (jh JobHandler) SetHistoryById(c *gin.Context) {
 	jobId, err := jh.getJobId(c.Param("job_id"))
 	if err != nil {
@@ -235,6 +486,29 @@ func (jh JobHandler) SetHistoryById(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+func (jh JobHandler) GetJobHistory(c *gin.Context) {
+	jobId, err := jh.getJobId(c.Param("job_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	limit, offset, err := jh.extractLimitAndOffset(c.Request.URL.Query(), jh.Cfg.Misc.MaxResultLimit)
+	if err != nil {
+		logger.Error("Error parsing query parameters", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	events, totalCount, err := jh.Service.GetJobHistory(jobId, *limit, *offset)
+	if err != nil {
+		logger.Error("Service error while getting job history", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	countStr := fmt.Sprintf("%v", totalCount)
+	c.Header("X-Total-Count", countStr)
+	c.JSON(http.StatusOK, events)
+}
+
 func (jh JobHandler) DeleteAllJobs(c *gin.Context) {
 	force := jh.Cfg.RunTime.BmPolicy.Sanitize(c.Query("force"))
 	if force != "true" {
@@ -2,18 +2,22 @@ package handler
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/johannes-kuhfuss/jobsvc/domain"
 	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/johannes-kuhfuss/services_utils/logger"
 	"github.com/johannes-kuhfuss/services_utils/misc"
+	"github.com/segmentio/ksuid"
 )
 
-func validateCreateJobRequest(newReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+func validateCreateJobRequest(newReq dto.CreateUpdateJobRequest, jobTypes service.JobTypeService) api_error.ApiErr {
 	if newReq.Type == "" {
 		return api_error.NewBadRequestError("Job create / update request must have a type")
 	}
@@ -22,22 +26,184 @@ func validateCreateJobRequest(newReq dto.CreateUpdateJobRequest) api_error.ApiEr
 			return api_error.NewBadRequestError(fmt.Sprintf("Priority value %v does not exist", newReq.Priority))
 		}
 	}
+	if newReq.BackoffPolicy != "" {
+		if !domain.IsValidBackoffPolicy(newReq.BackoffPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Backoff policy %v does not exist", newReq.BackoffPolicy))
+		}
+	}
+	if newReq.HasExtraData() && !domain.IsValidExtraData(newReq.ExtraData) {
+		return api_error.NewBadRequestError("Extra data must be a JSON object")
+	}
+	if err := validateLabels(newReq.Labels); err != nil {
+		return err
+	}
+	if newReq.RunAt != "" {
+		if _, err := domain.ParseRunAt(newReq.RunAt); err != nil {
+			return err
+		}
+	}
+	if newReq.DependencyPolicy != "" {
+		if !domain.IsValidDependencyPolicy(newReq.DependencyPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Dependency policy %v does not exist", newReq.DependencyPolicy))
+		}
+	}
+	for _, depId := range newReq.DependsOn {
+		if _, err := ksuid.Parse(depId); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Prerequisite job id %v should be a ksuid", depId))
+		}
+	}
+	if newReq.UniquePolicy != "" {
+		if !domain.IsValidUniquePolicy(newReq.UniquePolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Unique policy %v does not exist", newReq.UniquePolicy))
+		}
+	}
+	if jobTypes != nil {
+		return jobTypes.ValidateJobRequest(newReq)
+	}
 	return nil
 }
 
-func validateUpdateJobRequest(newReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+func validateUpdateJobRequest(newReq dto.CreateUpdateJobRequest, currentJob *dto.JobResponse, jobTypes service.JobTypeService) api_error.ApiErr {
 	if newReq.Priority != "" {
 		if !domain.IsValidPriority(newReq.Priority) {
 			return api_error.NewBadRequestError(fmt.Sprintf("Priority value %v does not exist", newReq.Priority))
 		}
 	}
+	if newReq.BackoffPolicy != "" {
+		if !domain.IsValidBackoffPolicy(newReq.BackoffPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Backoff policy %v does not exist", newReq.BackoffPolicy))
+		}
+	}
+	if newReq.HasExtraData() && !domain.IsValidExtraData(newReq.ExtraData) {
+		return api_error.NewBadRequestError("Extra data must be a JSON object")
+	}
+	if err := validateLabels(newReq.Labels); err != nil {
+		return err
+	}
+	if newReq.RunAt != "" {
+		if _, err := domain.ParseRunAt(newReq.RunAt); err != nil {
+			return err
+		}
+	}
+	if newReq.DependencyPolicy != "" {
+		if !domain.IsValidDependencyPolicy(newReq.DependencyPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Dependency policy %v does not exist", newReq.DependencyPolicy))
+		}
+	}
+	if len(newReq.DependsOn) > 0 {
+		return api_error.NewBadRequestError("Prerequisites of an existing job cannot be changed")
+	}
+	if jobTypes != nil && currentJob != nil {
+		return jobTypes.ValidateJobRequest(domain.MergeJobRequests(jobRequestFromResponse(*currentJob), newReq))
+	}
 	return nil
 }
 
-func validateDequeueRequest(newReq dto.DequeueRequest) api_error.ApiErr {
-	if newReq.Type == "" {
+func jobRequestFromResponse(job dto.JobResponse) dto.CreateUpdateJobRequest {
+	jobReq := dto.CreateUpdateJobRequest{
+		CorrelationId:    job.CorrelationId,
+		Name:             job.Name,
+		Source:           job.Source,
+		Destination:      job.Destination,
+		Type:             job.Type,
+		SubType:          job.SubType,
+		Action:           job.Action,
+		ActionDetails:    job.ActionDetails,
+		ExtraData:        job.ExtraData,
+		Priority:         job.Priority,
+		Rank:             job.Rank,
+		MaxAttempts:      job.MaxAttempts,
+		BackoffPolicy:    job.BackoffPolicy,
+		BackoffSeconds:   job.BackoffSeconds,
+		DependsOn:        job.DependsOn,
+		DependencyPolicy: job.DependencyPolicy,
+		Labels:           job.Labels,
+		UniqueKey:        job.UniqueKey,
+	}
+	if job.RunAt != nil {
+		jobReq.RunAt = job.RunAt.Format(time.RFC3339)
+	}
+	return jobReq
+}
+
+func validateCreateChildJobsRequest(newReq dto.CreateChildJobsRequest, jobTypes service.JobTypeService) api_error.ApiErr {
+	if len(newReq.Children) == 0 {
+		return api_error.NewBadRequestError("Create child jobs request must have at least one child job")
+	}
+	for idx, child := range newReq.Children {
+		if err := validateCreateJobRequest(child, jobTypes); err != nil {
+			if err.StatusCode() != http.StatusBadRequest {
+				return err
+			}
+			return api_error.NewBadRequestError(fmt.Sprintf("Child job %d: %v", idx+1, err.Message()))
+		}
+	}
+	return nil
+}
+
+func validateLabels(labels map[string]string) api_error.ApiErr {
+	for key, val := range labels {
+		if !domain.IsValidLabelKey(key) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Label key %v is not valid. Must only contain letters, digits, underscores or hyphens", key))
+		}
+		if val == "" {
+			return api_error.NewBadRequestError(fmt.Sprintf("Label %v must have a value", key))
+		}
+	}
+	return nil
+}
+
+func validateDequeueRequest(newReq dto.DequeueRequest, maxWait int, maxCount int) api_error.ApiErr {
+	if len(newReq.AllTypes()) == 0 {
 		return api_error.NewBadRequestError("Dequeue request must have a type")
 	}
+	if newReq.Wait < 0 {
+		return api_error.NewBadRequestError(fmt.Sprintf("Wait time %v is too low. Must be between 0 and %v seconds", newReq.Wait, maxWait))
+	}
+	if newReq.Wait > maxWait {
+		return api_error.NewBadRequestError(fmt.Sprintf("Wait time %v is too high. Must be between 0 and %v seconds", newReq.Wait, maxWait))
+	}
+	if newReq.Count < 0 {
+		return api_error.NewBadRequestError(fmt.Sprintf("Count %v is too low. Must be between 1 and %v", newReq.Count, maxCount))
+	}
+	if newReq.Count > maxCount {
+		return api_error.NewBadRequestError(fmt.Sprintf("Count %v is too high. Must be between 1 and %v", newReq.Count, maxCount))
+	}
+	if err := validateLabels(newReq.Labels); err != nil {
+		return err
+	}
+	for key := range newReq.ExcludeLabels {
+		if !domain.IsValidLabelKey(key) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Label key %v is not valid. Must only contain letters, digits, underscores or hyphens", key))
+		}
+	}
+	return nil
+}
+
+func validateHeartbeatRequest(newReq dto.HeartbeatRequest) api_error.ApiErr {
+	if newReq.Worker == "" {
+		return api_error.NewBadRequestError("Heartbeat request must have a worker")
+	}
+	if newReq.Progress != nil {
+		if *newReq.Progress < 0 || *newReq.Progress > 100 {
+			return api_error.NewBadRequestError(fmt.Sprintf("Progress value %v must be between 0 and 100", *newReq.Progress))
+		}
+	}
+	return nil
+}
+
+func validateUpdateJobProgressRequest(newReq dto.UpdateJobProgressRequest) api_error.ApiErr {
+	if newReq.Progress == nil {
+		return api_error.NewBadRequestError("Progress request must have a progress value")
+	}
+	if *newReq.Progress < 0 || *newReq.Progress > 100 {
+		return api_error.NewBadRequestError(fmt.Sprintf("Progress value %v must be between 0 and 100", *newReq.Progress))
+	}
+	if newReq.Eta != "" {
+		if _, err := domain.ParseEta(newReq.Eta); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -48,6 +214,17 @@ func validateUpdateJobStatusRequest(newReq dto.UpdateJobStatusRequest) api_error
 	if !domain.IsValidJobStatus(newReq.Status) {
 		return api_error.NewBadRequestError(fmt.Sprintf("Wrong status value %v when updating job status", newReq.Status))
 	}
+	if newReq.HasResult() && newReq.Status != string(domain.StatusFinished) && newReq.Status != string(domain.StatusFailed) {
+		return api_error.NewBadRequestError("Result can only be set when a job is finished or failed")
+	}
+	if newReq.Error != nil {
+		if newReq.Status != string(domain.StatusFailed) {
+			return api_error.NewBadRequestError("Error can only be set when a job failed")
+		}
+		if newReq.Error.Code == "" {
+			return api_error.NewBadRequestError("Error must have a code")
+		}
+	}
 	return nil
 }
 
@@ -148,6 +325,11 @@ func (jh JobHandler) extractLimitAndOffset(safParams url.Values, maxLimit int) (
 			logger.Error(msg, err)
 			return nil, nil, api_error.NewBadRequestError(msg)
 		}
+		if offset < 0 {
+			msg := fmt.Sprintf("Offset was set to %v (too low). Must be 0 or higher", offset)
+			logger.Error(msg, nil)
+			return nil, nil, api_error.NewBadRequestError(msg)
+		}
 	}
 	return &limit, &offset, nil
 }
@@ -158,11 +340,13 @@ func (jh JobHandler) extractFilters(safParams url.Values) ([]dto.FilterBy, api_e
 		filter := dto.FilterBy{}
 		key = jh.Cfg.RunTime.BmPolicy.Sanitize(key)
 		if (key != "sortBy") && (key != "limit") && (key != "offset") {
-			if misc.SliceContainsString(domain.GetJobDbFieldsAsStrings(), key) {
+			_, _, isJsonPath := domain.SplitJsonPath(key)
+			_, isLabel := domain.SplitLabelFilter(key)
+			if misc.SliceContainsString(domain.GetJobDbFieldsAsStrings(), key) || isJsonPath || isLabel {
 				filter.Field = key
 				for _, innerVal := range val {
 					innerVal = jh.Cfg.RunTime.BmPolicy.Sanitize(innerVal)
-					valSplit := strings.Split(innerVal, ":")
+					valSplit := splitFilterValue(key, innerVal)
 					if (len(valSplit) != 1) && (len(valSplit) != 2) {
 						msg := "Malformed filter value. Should either be single value or <operator>:<value>"
 						logger.Error(msg, nil)
@@ -202,6 +386,11 @@ func (jh JobHandler) extractFilters(safParams url.Values) ([]dto.FilterBy, api_e
 							filter.Value = valSplit[1]
 						}
 					}
+					if key == "run_at" {
+						if _, err := domain.ParseRunAt(fmt.Sprintf("%v", filter.Value)); err != nil {
+							return nil, err
+						}
+					}
 				}
 				filters = append(filters, filter)
 			} else {
@@ -211,3 +400,14 @@ func (jh JobHandler) extractFilters(safParams url.Values) ([]dto.FilterBy, api_e
 	}
 	return filters, nil
 }
+
+func splitFilterValue(key string, filterVal string) []string {
+	if key != "run_at" {
+		return strings.Split(filterVal, ":")
+	}
+	valSplit := strings.SplitN(filterVal, ":", 2)
+	if len(valSplit) == 2 && misc.SliceContainsString(dto.Operators, valSplit[0]) {
+		return valSplit
+	}
+	return []string{filterVal}
+}
@@ -1,19 +1,21 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
 
 	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_validateCreateUpdateJobRequest_NoType_Returns_BadRequestError(t *testing.T) {
 	req := dto.CreateUpdateJobRequest{}
 
-	err := validateCreateJobRequest(req)
+	err := validateCreateJobRequest(req, nil)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
@@ -27,7 +29,7 @@ func Test_validateCreateJobRequest_InvalidPriority_Returns_BadRequestError(t *te
 		Priority: prio,
 	}
 
-	err := validateCreateJobRequest(req)
+	err := validateCreateJobRequest(req, nil)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
@@ -39,11 +41,88 @@ func Test_validateCreateJobRequest_ValidRequest_Returns_NoError(t *testing.T) {
 		Type: "encoding",
 	}
 
-	err := validateCreateJobRequest(req)
+	err := validateCreateJobRequest(req, nil)
 
 	assert.Nil(t, err)
 }
 
+func Test_validateCreateJobRequest_InvalidBackoffPolicy_Returns_BadRequestError(t *testing.T) {
+	policy := "bogus"
+	req := dto.CreateUpdateJobRequest{
+		Type:          "encoding",
+		BackoffPolicy: policy,
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Backoff policy %v does not exist", policy), err.Message())
+}
+
+func Test_validateCreateJobRequest_ExtraDataNotObject_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		ExtraData: json.RawMessage(`["asset"]`),
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Extra data must be a JSON object", err.Message())
+}
+
+func Test_validateUpdateJobRequest_ExtraDataNotObject_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		ExtraData: json.RawMessage(`"no extra data"`),
+	}
+
+	err := validateUpdateJobRequest(req, nil, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Extra data must be a JSON object", err.Message())
+}
+
+func Test_validateCreateJobRequest_InvalidLabelKey_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Type:   "encoding",
+		Labels: map[string]string{"cust omer": "acme"},
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Label key cust omer is not valid. Must only contain letters, digits, underscores or hyphens", err.Message())
+}
+
+func Test_validateUpdateJobRequest_EmptyLabelValue_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Labels: map[string]string{"region": ""},
+	}
+
+	err := validateUpdateJobRequest(req, nil, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Label region must have a value", err.Message())
+}
+
+func Test_validateCreateJobRequest_InvalidRunAt_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Type:  "encoding",
+		RunAt: "06:00",
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Run at time 06:00 is not a valid RFC3339 timestamp", err.Message())
+}
+
 func Test_validateUpdateJobRequest_InvalidPriority_Returns_BadRequestError(t *testing.T) {
 	prio := "bogus"
 	req := dto.CreateUpdateJobRequest{
@@ -51,19 +130,32 @@ func Test_validateUpdateJobRequest_InvalidPriority_Returns_BadRequestError(t *te
 		Priority: prio,
 	}
 
-	err := validateUpdateJobRequest(req)
+	err := validateUpdateJobRequest(req, nil, nil)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
 	assert.EqualValues(t, fmt.Sprintf("Priority value %v does not exist", prio), err.Message())
 }
 
+func Test_validateUpdateJobRequest_InvalidBackoffPolicy_Returns_BadRequestError(t *testing.T) {
+	policy := "bogus"
+	req := dto.CreateUpdateJobRequest{
+		BackoffPolicy: policy,
+	}
+
+	err := validateUpdateJobRequest(req, nil, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Backoff policy %v does not exist", policy), err.Message())
+}
+
 func Test_validateUpdateJobRequest_ValidRequest_Returns_NoError(t *testing.T) {
 	req := dto.CreateUpdateJobRequest{
 		Type: "encoding",
 	}
 
-	err := validateUpdateJobRequest(req)
+	err := validateUpdateJobRequest(req, nil, nil)
 
 	assert.Nil(t, err)
 }
@@ -71,19 +163,95 @@ func Test_validateUpdateJobRequest_ValidRequest_Returns_NoError(t *testing.T) {
 func Test_validateDequeueRequest_NoType_Returns_BadRequestError(t *testing.T) {
 	req := dto.DequeueRequest{}
 
-	err := validateDequeueRequest(req)
+	err := validateDequeueRequest(req, 30, 50)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
 	assert.EqualValues(t, "Dequeue request must have a type", err.Message())
 }
 
+func Test_validateDequeueRequest_WaitTooHigh_Returns_BadRequestError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Type: "Encoding",
+		Wait: 31,
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Wait time 31 is too high. Must be between 0 and 30 seconds", err.Message())
+}
+
+func Test_validateDequeueRequest_NegativeWait_Returns_BadRequestError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Type: "Encoding",
+		Wait: -1,
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Wait time -1 is too low. Must be between 0 and 30 seconds", err.Message())
+}
+
+func Test_validateDequeueRequest_NegativeCount_Returns_BadRequestError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Type:  "Encoding",
+		Count: -1,
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Count -1 is too low. Must be between 1 and 50", err.Message())
+}
+
+func Test_validateDequeueRequest_InvalidExcludeLabel_Returns_BadRequestError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Type:          "Encoding",
+		ExcludeLabels: map[string]string{"re.gion": ""},
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Label key re.gion is not valid. Must only contain letters, digits, underscores or hyphens", err.Message())
+}
+
+func Test_validateDequeueRequest_CountTooHigh_Returns_BadRequestError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Type:  "Encoding",
+		Count: 51,
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Count 51 is too high. Must be between 1 and 50", err.Message())
+}
+
+func Test_validateDequeueRequest_TypesOnly_Returns_NoError(t *testing.T) {
+	req := dto.DequeueRequest{
+		Types:    []string{"encoding", "proxy"},
+		SubTypes: []string{"h264"},
+	}
+
+	err := validateDequeueRequest(req, 30, 50)
+
+	assert.Nil(t, err)
+}
+
 func Test_validateDequeueRequest_ValidRequest_Returns_NoError(t *testing.T) {
 	req := dto.DequeueRequest{
 		Type: "encoding",
 	}
 
-	err := validateDequeueRequest(req)
+	err := validateDequeueRequest(req, 30, 50)
 
 	assert.Nil(t, err)
 }
@@ -121,6 +289,57 @@ func Test_validateUpdateJobStatusRequest_ValidRequest_Returns_NoError(t *testing
 	assert.Nil(t, err)
 }
 
+func Test_validateUpdateJobStatusRequest_ResultNotDone_Returns_BadRequestError(t *testing.T) {
+	req := dto.UpdateJobStatusRequest{
+		Status: "paused",
+		Result: json.RawMessage(`{"files":["a.mp4"]}`),
+	}
+
+	err := validateUpdateJobStatusRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Result can only be set when a job is finished or failed", err.Message())
+}
+
+func Test_validateUpdateJobStatusRequest_ErrorNotFailed_Returns_BadRequestError(t *testing.T) {
+	req := dto.UpdateJobStatusRequest{
+		Status: "finished",
+		Error:  &dto.JobErrorRequest{Code: "E_TIMEOUT"},
+	}
+
+	err := validateUpdateJobStatusRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Error can only be set when a job failed", err.Message())
+}
+
+func Test_validateUpdateJobStatusRequest_ErrorWithoutCode_Returns_BadRequestError(t *testing.T) {
+	req := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Error:  &dto.JobErrorRequest{Message: "Source file not found"},
+	}
+
+	err := validateUpdateJobStatusRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Error must have a code", err.Message())
+}
+
+func Test_validateUpdateJobStatusRequest_FailedWithResultAndError_Returns_NoError(t *testing.T) {
+	req := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Result: json.RawMessage(`{"partial":true}`),
+		Error:  &dto.JobErrorRequest{Code: "E_SOURCE_MISSING", Message: "Source file not found"},
+	}
+
+	err := validateUpdateJobStatusRequest(req)
+
+	assert.Nil(t, err)
+}
+
 func Test_validateUpdateJobHistoryRequest_NoMessage_Returns_BadRequestError(t *testing.T) {
 	req := dto.UpdateJobHistoryRequest{}
 
@@ -141,6 +360,83 @@ func Test_validateUpdateJobHistoryRequest_Returns_NoError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_validateUpdateJobProgressRequest_NoProgress_Returns_BadRequestError(t *testing.T) {
+	req := dto.UpdateJobProgressRequest{Message: "Pass 1"}
+
+	err := validateUpdateJobProgressRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Progress request must have a progress value", err.Message())
+}
+
+func Test_validateUpdateJobProgressRequest_InvalidProgress_Returns_BadRequestError(t *testing.T) {
+	var progress int32 = -1
+	req := dto.UpdateJobProgressRequest{Progress: &progress}
+
+	err := validateUpdateJobProgressRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Progress value -1 must be between 0 and 100", err.Message())
+}
+
+func Test_validateUpdateJobProgressRequest_InvalidEta_Returns_BadRequestError(t *testing.T) {
+	var progress int32 = 50
+	req := dto.UpdateJobProgressRequest{Progress: &progress, Eta: "tomorrow"}
+
+	err := validateUpdateJobProgressRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "ETA tomorrow is not a valid RFC3339 timestamp", err.Message())
+}
+
+func Test_validateUpdateJobProgressRequest_ValidRequest_Returns_NoError(t *testing.T) {
+	var progress int32 = 50
+	req := dto.UpdateJobProgressRequest{Progress: &progress, Message: "Pass 1", Eta: "2026-10-17T12:00:00Z"}
+
+	err := validateUpdateJobProgressRequest(req)
+
+	assert.Nil(t, err)
+}
+
+func Test_validateHeartbeatRequest_NoWorker_Returns_BadRequestError(t *testing.T) {
+	req := dto.HeartbeatRequest{}
+
+	err := validateHeartbeatRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Heartbeat request must have a worker", err.Message())
+}
+
+func Test_validateHeartbeatRequest_InvalidProgress_Returns_BadRequestError(t *testing.T) {
+	var progress int32 = 101
+	req := dto.HeartbeatRequest{
+		Worker:   "worker 1",
+		Progress: &progress,
+	}
+
+	err := validateHeartbeatRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Progress value 101 must be between 0 and 100", err.Message())
+}
+
+func Test_validateHeartbeatRequest_ValidRequest_Returns_NoError(t *testing.T) {
+	var progress int32 = 50
+	req := dto.HeartbeatRequest{
+		Worker:   "worker 1",
+		Progress: &progress,
+	}
+
+	err := validateHeartbeatRequest(req)
+
+	assert.Nil(t, err)
+}
+
 func Test_extractSorts_NoInput_Returns_DefaultSort(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -319,6 +615,63 @@ func Test_extractFilters_NoFilters_Returns_EmptyResult(t *testing.T) {
 	assert.EqualValues(t, 0, len(filters))
 }
 
+func Test_extractFilters_ErrorCode_Returns_Filter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?error_code=E_TIMEOUT")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(filters))
+	assert.EqualValues(t, "error_code", filters[0].Field)
+	assert.EqualValues(t, "eq", filters[0].Operator)
+	assert.EqualValues(t, "E_TIMEOUT", filters[0].Value)
+}
+
+func Test_extractFilters_JsonPath_Returns_Filter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?extra_data.asset.id=eq:123")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(filters))
+	assert.EqualValues(t, "extra_data.asset.id", filters[0].Field)
+	assert.EqualValues(t, "eq", filters[0].Operator)
+	assert.EqualValues(t, "123", filters[0].Value)
+}
+
+func Test_extractFilters_InvalidJsonPath_Returns_NoFilter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?extra_data.asset..id=123&name.first=job")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(filters))
+}
+
+func Test_extractFilters_Label_Returns_Filter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?label.customer=acme")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(filters))
+	assert.EqualValues(t, "label.customer", filters[0].Field)
+	assert.EqualValues(t, "eq", filters[0].Operator)
+	assert.EqualValues(t, "acme", filters[0].Value)
+}
+
 func Test_extractFilters_MalformedFilters_Returns_BadRequestError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -403,6 +756,49 @@ func Test_extractFilters_UnknownOperator_Returns_BadRequestError(t *testing.T) {
 	assert.EqualValues(t, "Unknown operator bogus for filter", err.Message())
 }
 
+func Test_extractFilters_RunAtWithOp_Returns_Result(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?run_at=gte:2022-01-05T06:00:00Z")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.NotNil(t, filters)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "run_at", filters[0].Field)
+	assert.EqualValues(t, "gte", filters[0].Operator)
+	assert.EqualValues(t, "2022-01-05T06:00:00Z", filters[0].Value)
+}
+
+func Test_extractFilters_RunAtNoOp_Returns_ResultWithEqual(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?run_at=2022-01-05T06:00:00Z")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.NotNil(t, filters)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "eq", filters[0].Operator)
+	assert.EqualValues(t, "2022-01-05T06:00:00Z", filters[0].Value)
+}
+
+func Test_extractFilters_InvalidRunAt_Returns_BadRequestError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	url, _ := url.Parse("http://server:8080/jobs?run_at=lt:tomorrow")
+	safParams := url.Query()
+
+	filters, err := jh.extractFilters(safParams)
+
+	assert.Nil(t, filters)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Run at time tomorrow is not a valid RFC3339 timestamp", err.Message())
+}
+
 func Test_extractFilters_OnlyUnknownField_Returns_EmptyResult(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -504,3 +900,66 @@ func Test_validateSortAndFilterRequest_ValidParams_Returns_ParsedParams(t *testi
 	assert.EqualValues(t, "neq", params.Filters[0].Operator)
 	assert.EqualValues(t, "running", params.Filters[0].Value)
 }
+
+func Test_validateCreateJobRequest_InvalidDependency_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Type:      "proxy",
+		DependsOn: []string{"not_a_ksuid"},
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Prerequisite job id not_a_ksuid should be a ksuid", err.Message())
+}
+
+func Test_validateCreateJobRequest_InvalidUniquePolicy_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		Type:         "encoding",
+		UniquePolicy: "ignore",
+	}
+
+	err := validateCreateJobRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Unique policy ignore does not exist", err.Message())
+}
+
+func Test_validateUpdateJobRequest_WithDependencies_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobRequest{
+		DependsOn: []string{ksuid.New().String()},
+	}
+
+	err := validateUpdateJobRequest(req, nil, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Prerequisites of an existing job cannot be changed", err.Message())
+}
+
+func Test_validateCreateChildJobsRequest_NoChildren_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateChildJobsRequest{}
+
+	err := validateCreateChildJobsRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Create child jobs request must have at least one child job", err.Message())
+}
+
+func Test_validateCreateChildJobsRequest_InvalidChild_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{
+			{Type: "encoding"},
+			{Type: "encoding", Priority: "bogus"},
+		},
+	}
+
+	err := validateCreateChildJobsRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Child job 2: Priority value bogus does not exist", err.Message())
+}
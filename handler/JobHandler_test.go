@@ -36,7 +36,7 @@ func setupTest(t *testing.T) func() {
 	cfg.RunTime.Sani = sani
 	ctrl := gomock.NewController(t)
 	mockService = service.NewMockJobService(ctrl)
-	jh = NewJobHandler(&cfg, mockService)
+	jh = NewJobHandler(&cfg, mockService, nil, nil, nil)
 	jh.Cfg = &cfg
 	router = gin.Default()
 	recorder = httptest.NewRecorder()
@@ -88,7 +88,7 @@ func Test_CreateJob_Returns_InvalidJsonError(t *testing.T) {
 func Test_CreateJob_Returns_InvalidInputError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	apiError := api_error.NewBadRequestError("Could not validate input data for create job request")
+	apiError := api_error.NewBadRequestError("Priority value bogus does not exist")
 	errorJson, _ := json.Marshal(apiError)
 	jobReq := dto.CreateUpdateJobRequest{
 		Name:     "Job 1",
@@ -105,6 +105,46 @@ func Test_CreateJob_Returns_InvalidInputError(t *testing.T) {
 	assert.EqualValues(t, errorJson, recorder.Body.String())
 }
 
+func Test_CreateJob_SchemaViolation_Returns_RegistryError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTypeService := service.NewMockJobTypeService(ctrl)
+	jh.TypeService = mockTypeService
+	apiError := api_error.NewBadRequestError("Extra data does not match schema of job type encoding: extra_data.asset is required")
+	errorJson, _ := json.Marshal(apiError)
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	mockTypeService.EXPECT().ValidateJobRequest(jobReq).Return(apiError)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJob_RegistryError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTypeService := service.NewMockJobTypeService(ctrl)
+	jh.TypeService = mockTypeService
+	apiError := api_error.NewInternalServerError("Database error finding job type by name", nil)
+	errorJson, _ := json.Marshal(apiError)
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	mockTypeService.EXPECT().ValidateJobRequest(jobReq).Return(apiError)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusInternalServerError, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
 func Test_CreateJob_Returns_ServiceError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -150,7 +190,6 @@ func Test_CreateJob_Returns_NoError(t *testing.T) {
 		ActionDetails: "",
 		Progress:      0,
 		History:       "",
-		ExtraData:     "",
 		Priority:      "medium",
 		Rank:          0,
 	}
@@ -228,6 +267,32 @@ func Test_GetAllJobs_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, fmt.Sprintf("%v", len(dummyJobList)), totalCount[0])
 }
 
+func Test_GetAllDeadLetters_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	dummyJobList := createDummyJobList()
+	dummyJobListJson, _ := json.Marshal(dummyJobList)
+	safReq := dto.SortAndFilterRequest{
+		Sorts: dto.SortBy{
+			Field: "id",
+			Dir:   "DESC",
+		},
+		Filters: []dto.FilterBy{},
+		Limit:   0,
+		Offset:  0,
+	}
+	mockService.EXPECT().GetAllDeadLetters(safReq).Return(&dummyJobList, len(dummyJobList), nil)
+
+	router.GET("/deadletters", jh.GetAllDeadLetters)
+	request, _ := http.NewRequest(http.MethodGet, "/deadletters", nil)
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, dummyJobListJson, recorder.Body.String())
+	totalCount := recorder.Result().Header["X-Total-Count"]
+	assert.EqualValues(t, fmt.Sprintf("%v", len(dummyJobList)), totalCount[0])
+}
+
 func createDummyJobList() []dto.JobResponse {
 	job1, _ := domain.NewJob("Job 1", "Encoding")
 	job2, _ := domain.NewJob("Job 2", "Encondig")
@@ -239,6 +304,39 @@ func createDummyJobList() []dto.JobResponse {
 	return dummyJobList
 }
 
+func Test_GetDeadLetterById_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No dead-lettered job found for id %v", id))
+	errorJson, _ := json.Marshal(apiError)
+	mockService.EXPECT().GetDeadLetterById(id.String()).Return(nil, apiError)
+	router.GET("/deadletters/:job_id", jh.GetDeadLetterById)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/deadletters/%v", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNotFound, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_RequeueDeadLetter_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	newJob, _ := domain.NewJob("Job 1", "Encoding")
+	jobResp := newJob.ToJobResponseDto()
+	bodyJson, _ := json.Marshal(jobResp)
+	id := newJob.Id.String()
+	mockService.EXPECT().RequeueDeadLetter(id).Return(&jobResp, nil)
+	router.PUT("/deadletters/:job_id/requeue", jh.RequeueDeadLetter)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/deadletters/%v/requeue", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, bodyJson, recorder.Body.String())
+}
+
 func Test_GetJobById_Returns_InvalidIdError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -286,6 +384,80 @@ func Test_GetJobById_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, bodyJson, recorder.Body.String())
 }
 
+func Test_GetJobById_WithChildren_Returns_Children(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	parent, _ := domain.NewJob("Package", "Packaging")
+	child, _ := domain.NewJob("Rendition", "Encoding")
+	child.ParentId = parent.Id.String()
+	parentResp := parent.ToJobResponseDto()
+	children := []dto.JobResponse{child.ToJobResponseDto()}
+	mockService.EXPECT().GetJobById(parent.Id.String()).Return(&parentResp, nil)
+	mockService.EXPECT().GetChildJobs(parent.Id.String()).Return(&children, nil)
+	parentResp.Children = children
+	bodyJson, _ := json.Marshal(parentResp)
+	router.GET("/jobs/:job_id", jh.GetJobById)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/jobs/%v?children=true", parent.Id.String()), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, bodyJson, recorder.Body.String())
+}
+
+func Test_CreateChildJobs_Returns_InvalidJsonError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewBadRequestError("Invalid JSON body in create child jobs request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/jobs/:job_id/children", jh.CreateChildJobs)
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/jobs/%v/children", id), strings.NewReader("{"))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateChildJobs_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewBadRequestError("Create child jobs request must have at least one child job")
+	errorJson, _ := json.Marshal(apiError)
+	childReqJson, _ := json.Marshal(dto.CreateChildJobsRequest{})
+	router.POST("/jobs/:job_id/children", jh.CreateChildJobs)
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/jobs/%v/children", id), strings.NewReader(string(childReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateChildJobs_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	childReq := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{{Name: "Rendition", Type: "Encoding"}},
+	}
+	childReqJson, _ := json.Marshal(childReq)
+	child, _ := domain.NewJob("Rendition", "Encoding")
+	child.ParentId = id.String()
+	children := []dto.JobResponse{child.ToJobResponseDto()}
+	bodyJson, _ := json.Marshal(children)
+	mockService.EXPECT().CreateChildJobs(id.String(), childReq).Return(&children, nil)
+	router.POST("/jobs/:job_id/children", jh.CreateChildJobs)
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/jobs/%v/children", id), strings.NewReader(string(childReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, bodyJson, recorder.Body.String())
+}
+
 func Test_DeleteJobById_Returns_InvalidIdError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -372,7 +544,7 @@ func Test_Dequeue_Returns_ServiceError(t *testing.T) {
 	bodyJson, _ := json.Marshal(req)
 	router.PUT("/jobs/dequeue", jh.Dequeue)
 	request, _ := http.NewRequest(http.MethodPut, "/jobs/dequeue", strings.NewReader(string(bodyJson)))
-	mockService.EXPECT().Dequeue(req).Return(nil, apiError)
+	mockService.EXPECT().Dequeue(gomock.Any(), req).Return(nil, apiError)
 
 	router.ServeHTTP(recorder, request)
 
@@ -389,10 +561,142 @@ func Test_Dequeue_Returns_NoError(t *testing.T) {
 	bodyJson, _ := json.Marshal(req)
 	newJob, _ := domain.NewJob("Job 1", "Encoding")
 	jobResp := newJob.ToJobResponseDto()
-	respJson, _ := json.Marshal(jobResp)
+	respJson, _ := json.Marshal([]dto.JobResponse{jobResp})
+	router.PUT("/jobs/dequeue", jh.Dequeue)
+	request, _ := http.NewRequest(http.MethodPut, "/jobs/dequeue", strings.NewReader(string(bodyJson)))
+	mockService.EXPECT().Dequeue(gomock.Any(), req).Return(&[]dto.JobResponse{jobResp}, nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_Dequeue_WithTypes_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	req := dto.DequeueRequest{
+		Types:    []string{"encoding", "proxy"},
+		SubTypes: []string{"h264"},
+	}
+	bodyJson, _ := json.Marshal(req)
+	newJob, _ := domain.NewJob("Job 1", "proxy")
+	jobResp := newJob.ToJobResponseDto()
+	respJson, _ := json.Marshal([]dto.JobResponse{jobResp})
+	router.PUT("/jobs/dequeue", jh.Dequeue)
+	request, _ := http.NewRequest(http.MethodPut, "/jobs/dequeue", strings.NewReader(string(bodyJson)))
+	mockService.EXPECT().Dequeue(gomock.Any(), req).Return(&[]dto.JobResponse{jobResp}, nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_Dequeue_WithCount_Returns_List(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	cfg.Dequeue.MaxCount = 50
+	req := dto.DequeueRequest{
+		Type:  "Encoding",
+		Count: 5,
+	}
+	bodyJson, _ := json.Marshal(req)
+	jobResps := []dto.JobResponse{}
+	respJson, _ := json.Marshal(jobResps)
 	router.PUT("/jobs/dequeue", jh.Dequeue)
 	request, _ := http.NewRequest(http.MethodPut, "/jobs/dequeue", strings.NewReader(string(bodyJson)))
-	mockService.EXPECT().Dequeue(req).Return(&jobResp, nil)
+	mockService.EXPECT().Dequeue(gomock.Any(), req).Return(&jobResps, nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_Heartbeat_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Could not validate input data for heartbeat request")
+	errorJson, _ := json.Marshal(apiError)
+	id := ksuid.New()
+	hbReqJson, _ := json.Marshal(dto.HeartbeatRequest{})
+	router.PUT("/jobs/:job_id/heartbeat", jh.Heartbeat)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/heartbeat", id), strings.NewReader(string(hbReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_Heartbeat_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewProcessingConflictError(fmt.Sprintf("Job with id %v is not leased by worker worker 1", id))
+	errorJson, _ := json.Marshal(apiError)
+	hbReq := dto.HeartbeatRequest{Worker: "worker 1"}
+	hbReqJson, _ := json.Marshal(hbReq)
+	mockService.EXPECT().Heartbeat(id.String(), hbReq).Return(nil, apiError)
+	router.PUT("/jobs/:job_id/heartbeat", jh.Heartbeat)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/heartbeat", id), strings.NewReader(string(hbReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusConflict, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_Heartbeat_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	newJob, _ := domain.NewJob("Job 1", "Encoding")
+	jobResp := newJob.ToJobResponseDto()
+	respJson, _ := json.Marshal(jobResp)
+	hbReq := dto.HeartbeatRequest{Worker: "worker 1"}
+	hbReqJson, _ := json.Marshal(hbReq)
+	mockService.EXPECT().Heartbeat(newJob.Id.String(), hbReq).Return(&jobResp, nil)
+	router.PUT("/jobs/:job_id/heartbeat", jh.Heartbeat)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/heartbeat", newJob.Id), strings.NewReader(string(hbReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_SetProgressById_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewBadRequestError("Could not validate input data for update job progress request")
+	errorJson, _ := json.Marshal(apiError)
+	var progress int32 = 150
+	progReqJson, _ := json.Marshal(dto.UpdateJobProgressRequest{Progress: &progress})
+	router.PUT("/jobs/:job_id/progress", jh.SetProgressById)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/progress", id), strings.NewReader(string(progReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_SetProgressById_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	newJob, _ := domain.NewJob("Job 1", "Encoding")
+	newJob.Progress = 40
+	newJob.ProgressMessage = "Pass 1"
+	jobResp := newJob.ToJobResponseDto()
+	respJson, _ := json.Marshal(jobResp)
+	var progress int32 = 40
+	progReq := dto.UpdateJobProgressRequest{Progress: &progress, Message: "Pass 1"}
+	progReqJson, _ := json.Marshal(progReq)
+	mockService.EXPECT().SetProgressById(newJob.Id.String(), progReq).Return(&jobResp, nil)
+	router.PUT("/jobs/:job_id/progress", jh.SetProgressById)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/progress", newJob.Id), strings.NewReader(string(progReqJson)))
 
 	router.ServeHTTP(recorder, request)
 
@@ -433,7 +737,7 @@ func Test_UpdateJob_Returns_InvalidInputError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 	id := ksuid.New()
-	apiError := api_error.NewBadRequestError("Could not validate input data for update job request")
+	apiError := api_error.NewBadRequestError("Priority value bogus does not exist")
 	errorJson, _ := json.Marshal(apiError)
 	jobReq := dto.CreateUpdateJobRequest{
 		Name:     "Job 1",
@@ -491,6 +795,35 @@ func Test_UpdateJob_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, newJobRespJson, recorder.Body.String())
 }
 
+func Test_UpdateJob_MergedJobInvalid_Returns_RegistryError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTypeService := service.NewMockJobTypeService(ctrl)
+	jh.TypeService = mockTypeService
+	oldJob, _ := domain.NewJob("Job 1", "encoding")
+	oldJob.SubType = "h264"
+	oldJobResp := oldJob.ToJobResponseDto()
+	apiError := api_error.NewBadRequestError("Action delete is not allowed for job type encoding")
+	errorJson, _ := json.Marshal(apiError)
+	mockService.EXPECT().GetJobById(oldJob.Id.String()).Return(&oldJobResp, nil)
+	mockTypeService.EXPECT().ValidateJobRequest(gomock.Any()).DoAndReturn(
+		func(jobReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+			assert.EqualValues(t, "encoding", jobReq.Type)
+			assert.EqualValues(t, "h264", jobReq.SubType)
+			assert.EqualValues(t, "delete", jobReq.Action)
+			return apiError
+		})
+	router.PUT("/jobs/:job_id", jh.UpdateJob)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v", oldJob.Id.String()), strings.NewReader(`{"action": "delete"}`))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
 func Test_SetStatusById_Returns_InvalidIdError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -576,6 +909,90 @@ func Test_SetStatusById_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
 }
 
+func Test_SetStatusById_WithResultAndError_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	retryable := false
+	jobReq := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Result: json.RawMessage(`{"files":["part1.mp4"]}`),
+		Error: &dto.JobErrorRequest{
+			Code:      "E_SOURCE_MISSING",
+			Message:   "Source file not found",
+			Retryable: &retryable,
+		},
+	}
+	jobReqJson, _ := json.Marshal(jobReq)
+	mockService.EXPECT().SetStatusById(id.String(), jobReq).Return(nil)
+	router.PUT("jobs/:job_id/status", jh.SetStatusById)
+	request, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/jobs/%v/status", id), strings.NewReader(string(jobReqJson)))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
+}
+
+func Test_CancelJob_Returns_InvalidIdError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("User Id should be a ksuid")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/jobs/:job_id/cancel", jh.CancelJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs/not-a-ksuid/cancel", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CancelJob_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewProcessingConflictError(fmt.Sprintf("Job with id %v is already finished and cannot be cancelled", id))
+	errorJson, _ := json.Marshal(apiError)
+	mockService.EXPECT().CancelJob(id.String()).Return(nil, apiError)
+	router.POST("/jobs/:job_id/cancel", jh.CancelJob)
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/jobs/%v/cancel", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusConflict, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CancelJob_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	job, _ := domain.NewJob("Job 1", "Encoding")
+	job.Status = domain.StatusCancelled
+	jobResp := job.ToJobResponseDto()
+	bodyJson, _ := json.Marshal(jobResp)
+	mockService.EXPECT().CancelJob(job.Id.String()).Return(&jobResp, nil)
+	router.POST("/jobs/:job_id/cancel", jh.CancelJob)
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/jobs/%v/cancel", job.Id.String()), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, bodyJson, recorder.Body.String())
+}
+
+func Test_GetStatusTransitions_Returns_TransitionTable(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	bodyJson, _ := json.Marshal(domain.StatusTransitionsToDto())
+	router.GET("/jobs/transitions", jh.GetStatusTransitions)
+	request, _ := http.NewRequest(http.MethodGet, "/jobs/transitions", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, bodyJson, recorder.Body.String())
+}
+
 func Test_SetHistoryById_Returns_InvalidIdError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -659,6 +1076,71 @@ func Test_SetHistoryById_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
 }
 
+func Test_GetJobHistory_Returns_InvalidIdError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("User Id should be a ksuid")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/jobs/:job_id/history", jh.GetJobHistory)
+	request, _ := http.NewRequest(http.MethodGet, "/jobs/not_a_ksuid/history", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetJobHistory_NegativeOffset_Returns_BadRequestError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewBadRequestError("Offset was set to -1 (too low). Must be 0 or higher")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/jobs/:job_id/history", jh.GetJobHistory)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/jobs/%v/history?offset=-1", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetJobHistory_Returns_ServiceError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No history found for job id %v", id.String()))
+	errorJson, _ := json.Marshal(apiError)
+	mockService.EXPECT().GetJobHistory(id.String(), 0, 5).Return(nil, 0, apiError)
+	router.GET("/jobs/:job_id/history", jh.GetJobHistory)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/jobs/%v/history?offset=5", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNotFound, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetJobHistory_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New()
+	events := []dto.JobEventResponse{
+		{Id: 1, JobId: id.String(), EventType: "created", Actor: "system", Message: "Job created", NewStatus: "created"},
+		{Id: 2, JobId: id.String(), EventType: "status_changed", Actor: "worker 1", Message: "Dequeuing job for processing", OldStatus: "created", NewStatus: "running"},
+	}
+	eventsJson, _ := json.Marshal(events)
+	mockService.EXPECT().GetJobHistory(id.String(), 0, 0).Return(&events, 7, nil)
+	router.GET("/jobs/:job_id/history", jh.GetJobHistory)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/jobs/%v/history", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, eventsJson, recorder.Body.String())
+	assert.EqualValues(t, "7", recorder.Result().Header["X-Total-Count"][0])
+}
+
 func Test_DeleteAllJobs_NoForce_Returns_BadRequestError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -699,3 +1181,122 @@ func Test_DeleteAllJobs_WithForce_Returns_NoError(t *testing.T) {
 
 	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
 }
+
+func setupIdempotencyTest(t *testing.T) (*service.MockIdempotencyService, func()) {
+	teardown := setupTest(t)
+	ctrl := gomock.NewController(t)
+	mockIdemService := service.NewMockIdempotencyService(ctrl)
+	jh.IdemService = mockIdemService
+	return mockIdemService, func() {
+		teardown()
+		ctrl.Finish()
+	}
+}
+
+func Test_CreateJob_IdempotencyKeyTooLong_Returns_BadRequestError(t *testing.T) {
+	_, teardown := setupIdempotencyTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Idempotency key must not be longer than 255 characters")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+	request.Header.Set("Idempotency-Key", strings.Repeat("a", 256))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJob_IdempotencyKeyNew_Stores_Result(t *testing.T) {
+	mockIdemService, teardown := setupIdempotencyTest(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	jobResp := dto.JobResponse{Id: ksuid.New().String(), Type: "encoding"}
+	mockIdemService.EXPECT().BeginJobRequest("abc", jobReq).Return(nil, nil)
+	mockService.EXPECT().CreateJob(jobReq).Return(&jobResp, nil)
+	mockIdemService.EXPECT().CompleteJobRequest("abc", http.StatusCreated, jobResp).Return(nil)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+	request.Header.Set("Idempotency-Key", "abc")
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+}
+
+func Test_CreateJob_IdempotencyKeyServiceError_Releases_Key(t *testing.T) {
+	mockIdemService, teardown := setupIdempotencyTest(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("database error", nil)
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	mockIdemService.EXPECT().BeginJobRequest("abc", jobReq).Return(nil, nil)
+	mockService.EXPECT().CreateJob(jobReq).Return(nil, apiError)
+	mockIdemService.EXPECT().AbortJobRequest("abc").Return(nil)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+	request.Header.Set("Idempotency-Key", "abc")
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func Test_CreateJob_IdempotencyKeyRepeat_Returns_StoredResponse(t *testing.T) {
+	mockIdemService, teardown := setupIdempotencyTest(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	stored := dto.IdempotentJobResponse{
+		StatusCode: http.StatusCreated,
+		Job:        dto.JobResponse{Id: ksuid.New().String(), Type: "encoding"},
+	}
+	respJson, _ := json.Marshal(stored.Job)
+	mockIdemService.EXPECT().BeginJobRequest("abc", jobReq).Return(&stored, nil)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+	request.Header.Set("Idempotency-Key", "abc")
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+	assert.EqualValues(t, "true", recorder.Header().Get("Idempotent-Replayed"))
+}
+
+func Test_CreateJob_IdempotencyKeyConflictingBody_Returns_ValidationError(t *testing.T) {
+	mockIdemService, teardown := setupIdempotencyTest(t)
+	defer teardown()
+	apiError := api_error.NewValidationError("Idempotency key abc was already used with a different request body")
+	errorJson, _ := json.Marshal(apiError)
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	mockIdemService.EXPECT().BeginJobRequest("abc", jobReq).Return(nil, apiError)
+	router.POST("/jobs", jh.CreateJob)
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"type": "encoding"}`))
+	request.Header.Set("Idempotency-Key", "abc")
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJob_Deduplicated_Returns_Ok(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:         "encoding",
+		UniqueKey:    "encode-a.mxf",
+		UniquePolicy: "return_existing",
+	}
+	jobResp := dto.JobResponse{Id: ksuid.New().String(), Type: "encoding", UniqueKey: "encode-a.mxf", Deduplicated: true}
+	respJson, _ := json.Marshal(jobResp)
+	mockService.EXPECT().CreateJob(jobReq).Return(&jobResp, nil)
+	router.POST("/jobs", jh.CreateJob)
+	body := `{"type": "encoding", "unique_key": "encode-a.mxf", "unique_policy": "return_existing"}`
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type JobTemplateHandler struct {
+	Service service.JobTemplateService
+	Cfg     *config.AppConfig
+}
+
+func NewJobTemplateHandler(cfg *config.AppConfig, svc service.JobTemplateService) JobTemplateHandler {
+	return JobTemplateHandler{
+		Cfg:     cfg,
+		Service: svc,
+	}
+}
+
+func (th *JobTemplateHandler) getJobTemplateName(templateParam string) (string, api_error.ApiErr) {
+	templateParam = th.Cfg.RunTime.BmPolicy.Sanitize(templateParam)
+	if !domain.IsValidJobTemplateName(templateParam) {
+		msg := "Job template name must only contain letters, digits, underscores or hyphens"
+		logger.Error(msg, nil)
+		return "", api_error.NewBadRequestError(msg)
+	}
+	return templateParam, nil
+}
+
+func (th *JobTemplateHandler) getJobTemplateVersion(versionParam string) (int32, api_error.ApiErr) {
+	if versionParam == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(th.Cfg.RunTime.BmPolicy.Sanitize(versionParam), 10, 32)
+	if err != nil || version < 1 {
+		msg := "Job template version must be a positive number"
+		logger.Error(msg, err)
+		return 0, api_error.NewBadRequestError(msg)
+	}
+	return int32(version), nil
+}
+
+func (th *JobTemplateHandler) CreateJobTemplate(c *gin.Context) {
+	var newTemplateReq dto.CreateUpdateJobTemplateRequest
+	if err := c.ShouldBindJSON(&newTemplateReq); err != nil {
+		msg := "Invalid JSON body in create job template request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	th.Cfg.RunTime.Sani.Sanitize(&newTemplateReq)
+	err := validateCreateJobTemplateRequest(newTemplateReq)
+	if err != nil {
+		msg := "Could not validate input data for create job template request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := th.Service.CreateJobTemplate(newTemplateReq)
+	if err != nil {
+		logger.Error("Service error while creating job template", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (th *JobTemplateHandler) GetAllJobTemplates(c *gin.Context) {
+	templates, err := th.Service.GetAllJobTemplates()
+	if err != nil {
+		logger.Error("Service error while getting all job templates", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (th *JobTemplateHandler) GetJobTemplateByName(c *gin.Context) {
+	templateName, err := th.getJobTemplateName(c.Param("template_name"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	version, err := th.getJobTemplateVersion(c.Query("version"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	template, err := th.Service.GetJobTemplateByName(templateName, version)
+	if err != nil {
+		logger.Error("Service error while getting job template by name", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func (th *JobTemplateHandler) GetJobTemplateVersions(c *gin.Context) {
+	templateName, err := th.getJobTemplateName(c.Param("template_name"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	templates, err := th.Service.GetJobTemplateVersions(templateName)
+	if err != nil {
+		logger.Error("Service error while getting job template versions", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (th *JobTemplateHandler) UpdateJobTemplate(c *gin.Context) {
+	templateName, err := th.getJobTemplateName(c.Param("template_name"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var updTemplateReq dto.CreateUpdateJobTemplateRequest
+	if err := c.ShouldBindJSON(&updTemplateReq); err != nil {
+		msg := "Invalid JSON body in update job template request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	th.Cfg.RunTime.Sani.Sanitize(&updTemplateReq)
+	err = validateUpdateJobTemplateRequest(updTemplateReq)
+	if err != nil {
+		msg := "Could not validate input data for update job template request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := th.Service.UpdateJobTemplate(templateName, updTemplateReq)
+	if err != nil {
+		logger.Error("Service error while updating job template", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (th *JobTemplateHandler) DeleteJobTemplateByName(c *gin.Context) {
+	templateName, err := th.getJobTemplateName(c.Param("template_name"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	err = th.Service.DeleteJobTemplateByName(templateName)
+	if err != nil {
+		logger.Error("Service error while deleting job template by name", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
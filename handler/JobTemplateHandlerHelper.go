@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+func validateCreateJobTemplateRequest(newReq dto.CreateUpdateJobTemplateRequest) api_error.ApiErr {
+	if newReq.Name == "" {
+		return api_error.NewBadRequestError("Job template create request must have a name")
+	}
+	if !domain.IsValidJobTemplateName(newReq.Name) {
+		return api_error.NewBadRequestError(fmt.Sprintf("Job template name %v is not valid. Must only contain letters, digits, underscores or hyphens", newReq.Name))
+	}
+	if newReq.Job == nil {
+		return api_error.NewBadRequestError("Job template create request must have a job")
+	}
+	return validateUpdateJobTemplateRequest(newReq)
+}
+
+func validateUpdateJobTemplateRequest(newReq dto.CreateUpdateJobTemplateRequest) api_error.ApiErr {
+	if newReq.Job == nil {
+		return nil
+	}
+	if newReq.Job.Template != "" || newReq.Job.TemplateVersion != 0 {
+		return api_error.NewBadRequestError("Job template cannot reference another template")
+	}
+	return validateUpdateJobRequest(*newReq.Job, nil, nil)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateCreateJobTemplateRequest_NoJob_Returns_BadRequestError(t *testing.T) {
+	err := validateCreateJobTemplateRequest(dto.CreateUpdateJobTemplateRequest{Name: "encode_hd"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Job template create request must have a job", err.Message())
+}
+
+func Test_validateUpdateJobTemplateRequest_InvalidPriority_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobTemplateRequest{
+		Job: &dto.CreateUpdateJobRequest{Priority: "urgent"},
+	}
+
+	err := validateUpdateJobTemplateRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Priority value urgent does not exist", err.Message())
+}
+
+func Test_validateUpdateJobTemplateRequest_NoJob_Returns_NoError(t *testing.T) {
+	err := validateUpdateJobTemplateRequest(dto.CreateUpdateJobTemplateRequest{Description: "Encode to HD"})
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	tmh                    JobTemplateHandler
+	mockJobTemplateService *service.MockJobTemplateService
+)
+
+func setupJobTemplateTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	ctrl := gomock.NewController(t)
+	mockJobTemplateService = service.NewMockJobTemplateService(ctrl)
+	tmh = NewJobTemplateHandler(&cfg, mockJobTemplateService)
+	jh.TemplateService = mockJobTemplateService
+	return func() {
+		teardown()
+		ctrl.Finish()
+	}
+}
+
+func Test_getJobTemplateVersion_Invalid_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	version, err := tmh.getJobTemplateVersion("0")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, version)
+	assert.EqualValues(t, "Job template version must be a positive number", err.Message())
+}
+
+func Test_CreateJobTemplate_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Could not validate input data for create job template request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/templates", tmh.CreateJobTemplate)
+	body := `{"name": "encode_hd", "job": {"template": "encode_sd"}}`
+	request, _ := http.NewRequest(http.MethodPost, "/templates", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJobTemplate_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	tmplReq := dto.CreateUpdateJobTemplateRequest{
+		Name: "encode_hd",
+		Job:  &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	tmplResp := dto.JobTemplateResponse{
+		Name:    "encode_hd",
+		Version: 1,
+		Job:     dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	respJson, _ := json.Marshal(tmplResp)
+	mockJobTemplateService.EXPECT().CreateJobTemplate(tmplReq).Return(&tmplResp, nil)
+	router.POST("/templates", tmh.CreateJobTemplate)
+	body := `{"name": "encode_hd", "job": {"type": "encoding"}}`
+	request, _ := http.NewRequest(http.MethodPost, "/templates", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_GetJobTemplateByName_Version_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	tmplResp := dto.JobTemplateResponse{
+		Name:    "encode_hd",
+		Version: 2,
+	}
+	respJson, _ := json.Marshal(tmplResp)
+	mockJobTemplateService.EXPECT().GetJobTemplateByName("encode_hd", int32(2)).Return(&tmplResp, nil)
+	router.GET("/templates/:template_name", tmh.GetJobTemplateByName)
+	request, _ := http.NewRequest(http.MethodGet, "/templates/encode_hd?version=2", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_UpdateJobTemplate_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	tmplReq := dto.CreateUpdateJobTemplateRequest{
+		Job: &dto.CreateUpdateJobRequest{Priority: "low"},
+	}
+	tmplResp := dto.JobTemplateResponse{
+		Name:    "encode_hd",
+		Version: 2,
+		Job:     dto.CreateUpdateJobRequest{Type: "encoding", Priority: "low"},
+	}
+	respJson, _ := json.Marshal(tmplResp)
+	mockJobTemplateService.EXPECT().UpdateJobTemplate("encode_hd", tmplReq).Return(&tmplResp, nil)
+	router.PUT("/templates/:template_name", tmh.UpdateJobTemplate)
+	body := `{"job": {"priority": "low"}}`
+	request, _ := http.NewRequest(http.MethodPut, "/templates/encode_hd", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_DeleteJobTemplateByName_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	mockJobTemplateService.EXPECT().DeleteJobTemplateByName("encode_hd").Return(nil)
+	router.DELETE("/templates/:template_name", tmh.DeleteJobTemplateByName)
+	request, _ := http.NewRequest(http.MethodDelete, "/templates/encode_hd", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
+}
+
+func Test_CreateJob_Template_Returns_TemplateError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("No job template found for name encode_hd")
+	errorJson, _ := json.Marshal(apiError)
+	jobReq := dto.CreateUpdateJobRequest{Template: "encode_hd"}
+	mockJobTemplateService.EXPECT().ResolveJobRequest(jobReq).Return(nil, apiError)
+	router.POST("/jobs", jh.CreateJob)
+	body := `{"template": "encode_hd"}`
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJob_Template_Creates_ResolvedJob(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Template: "encode_hd", Name: "Trailer"}
+	resolvedReq := dto.CreateUpdateJobRequest{Template: "encode_hd", TemplateVersion: 2, Name: "Trailer", Type: "encoding"}
+	jobResp := dto.JobResponse{Name: "Trailer", Type: "encoding"}
+	mockJobTemplateService.EXPECT().ResolveJobRequest(jobReq).Return(&resolvedReq, nil)
+	mockService.EXPECT().CreateJob(resolvedReq).Return(&jobResp, nil)
+	router.POST("/jobs", jh.CreateJob)
+	body := `{"template": "encode_hd", "name": "Trailer"}`
+	request, _ := http.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type JobTypeHandler struct {
+	Service service.JobTypeService
+	Cfg     *config.AppConfig
+}
+
+func NewJobTypeHandler(cfg *config.AppConfig, svc service.JobTypeService) JobTypeHandler {
+	return JobTypeHandler{
+		Cfg:     cfg,
+		Service: svc,
+	}
+}
+
+func (th *JobTypeHandler) getJobTypeName(jobTypeParam string) (string, api_error.ApiErr) {
+	jobTypeParam = th.Cfg.RunTime.BmPolicy.Sanitize(jobTypeParam)
+	if !domain.IsValidJobTypeName(jobTypeParam) {
+		msg := "Job type name must only contain letters, digits, underscores or hyphens"
+		logger.Error(msg, nil)
+		return "", api_error.NewBadRequestError(msg)
+	}
+	return jobTypeParam, nil
+}
+
+func (th *JobTypeHandler) CreateJobType(c *gin.Context) {
+	var newTypeReq dto.CreateUpdateJobTypeRequest
+	if err := c.ShouldBindJSON(&newTypeReq); err != nil {
+		msg := "Invalid JSON body in create job type request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	th.Cfg.RunTime.Sani.Sanitize(&newTypeReq)
+	err := validateCreateJobTypeRequest(newTypeReq)
+	if err != nil {
+		msg := "Could not validate input data for create job type request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := th.Service.CreateJobType(newTypeReq)
+	if err != nil {
+		logger.Error("Service error while creating job type", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (th *JobTypeHandler) GetAllJobTypes(c *gin.Context) {
+	jobTypes, err := th.Service.GetAllJobTypes()
+	if err != nil {
+		logger.Error("Service error while getting all job types", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, jobTypes)
+}
+
+func (th *JobTypeHandler) GetJobTypeByName(c *gin.Context) {
+	jobTypeName, err := th.getJobTypeName(c.Param("job_type"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	jobType, err := th.Service.GetJobTypeByName(jobTypeName)
+	if err != nil {
+		logger.Error("Service error while getting job type by name", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, jobType)
+}
+
+func (th *JobTypeHandler) UpdateJobType(c *gin.Context) {
+	jobTypeName, err := th.getJobTypeName(c.Param("job_type"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var updTypeReq dto.CreateUpdateJobTypeRequest
+	if err := c.ShouldBindJSON(&updTypeReq); err != nil {
+		msg := "Invalid JSON body in update job type request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	th.Cfg.RunTime.Sani.Sanitize(&updTypeReq)
+	err = validateUpdateJobTypeRequest(updTypeReq)
+	if err != nil {
+		msg := "Could not validate input data for update job type request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := th.Service.UpdateJobType(jobTypeName, updTypeReq)
+	if err != nil {
+		logger.Error("Service error while updating job type", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (th *JobTypeHandler) DeleteJobTypeByName(c *gin.Context) {
+	jobTypeName, err := th.getJobTypeName(c.Param("job_type"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	err = th.Service.DeleteJobTypeByName(jobTypeName)
+	if err != nil {
+		logger.Error("Service error while deleting job type by name", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
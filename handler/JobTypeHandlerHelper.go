@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/misc"
+)
+
+func validateCreateJobTypeRequest(newReq dto.CreateUpdateJobTypeRequest) api_error.ApiErr {
+	if newReq.Name == "" {
+		return api_error.NewBadRequestError("Job type create request must have a name")
+	}
+	if !domain.IsValidJobTypeName(newReq.Name) {
+		return api_error.NewBadRequestError(fmt.Sprintf("Job type name %v is not valid. Must only contain letters, digits, underscores or hyphens", newReq.Name))
+	}
+	return validateUpdateJobTypeRequest(newReq)
+}
+
+func validateUpdateJobTypeRequest(newReq dto.CreateUpdateJobTypeRequest) api_error.ApiErr {
+	for _, field := range newReq.RequiredFields {
+		if !misc.SliceContainsString(domain.GetJobRequestFieldsAsStrings(), field) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Required field %v does not exist", field))
+		}
+	}
+	for _, field := range newReq.UniqueFields {
+		if !misc.SliceContainsString(domain.GetJobRequestFieldsAsStrings(), field) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Unique field %v does not exist", field))
+		}
+	}
+	if len(newReq.ExtraDataSchema) > 0 && string(newReq.ExtraDataSchema) != "null" {
+		if _, err := domain.ParseJsonSchema(newReq.ExtraDataSchema); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Extra data schema is not valid: %v", err.Error()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateCreateJobTypeRequest_NoName_Returns_BadRequestError(t *testing.T) {
+	err := validateCreateJobTypeRequest(dto.CreateUpdateJobTypeRequest{})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Job type create request must have a name", err.Message())
+}
+
+func Test_validateCreateJobTypeRequest_UnknownRequiredField_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobTypeRequest{
+		Name:           "encoding",
+		RequiredFields: []string{"sourc"},
+	}
+
+	err := validateCreateJobTypeRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Required field sourc does not exist", err.Message())
+}
+
+func Test_validateCreateJobTypeRequest_UnknownUniqueField_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateJobTypeRequest{
+		Name:         "encoding",
+		UniqueFields: []string{"sourc"},
+	}
+
+	err := validateCreateJobTypeRequest(req)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Unique field sourc does not exist", err.Message())
+}
+
+func Test_validateCreateJobRequest_RegistryError_Returns_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jobTypes := service.NewMockJobTypeService(ctrl)
+	req := dto.CreateUpdateJobRequest{
+		Type: "encodng",
+	}
+	apiError := api_error.NewBadRequestError("Job type encodng is not registered")
+	jobTypes.EXPECT().ValidateJobRequest(req).Return(apiError)
+
+	err := validateCreateJobRequest(req, jobTypes)
+
+	assert.EqualValues(t, apiError, err)
+}
+
+func Test_validateCreateChildJobsRequest_RegistryError_Returns_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jobTypes := service.NewMockJobTypeService(ctrl)
+	child := dto.CreateUpdateJobRequest{Type: "encoding"}
+	apiError := api_error.NewInternalServerError("Database error finding job type by name", nil)
+	jobTypes.EXPECT().ValidateJobRequest(child).Return(apiError)
+	req := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{child},
+	}
+
+	err := validateCreateChildJobsRequest(req, jobTypes)
+
+	assert.EqualValues(t, apiError, err)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	th                 JobTypeHandler
+	mockJobTypeService *service.MockJobTypeService
+)
+
+func setupJobTypeTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	ctrl := gomock.NewController(t)
+	mockJobTypeService = service.NewMockJobTypeService(ctrl)
+	th = NewJobTypeHandler(&cfg, mockJobTypeService)
+	return func() {
+		teardown()
+		ctrl.Finish()
+	}
+}
+
+func Test_getJobTypeName_InvalidName_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	name, err := th.getJobTypeName("media encoding")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "", name)
+	assert.EqualValues(t, "Job type name must only contain letters, digits, underscores or hyphens", err.Message())
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_CreateJobType_Returns_InvalidJsonError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Invalid JSON body in create job type request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/jobtypes", th.CreateJobType)
+	request, _ := http.NewRequest(http.MethodPost, "/jobtypes", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJobType_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Could not validate input data for create job type request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/jobtypes", th.CreateJobType)
+	body := `{"name": "encoding", "extra_data_schema": {"type": "text"}}`
+	request, _ := http.NewRequest(http.MethodPost, "/jobtypes", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateJobType_Returns_NoError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	typeReq := dto.CreateUpdateJobTypeRequest{
+		Name:     "encoding",
+		SubTypes: []string{"h264"},
+	}
+	typeResp := dto.JobTypeResponse{
+		Name:     "encoding",
+		SubTypes: []string{"h264"},
+	}
+	respJson, _ := json.Marshal(typeResp)
+	mockJobTypeService.EXPECT().CreateJobType(typeReq).Return(&typeResp, nil)
+	router.POST("/jobtypes", th.CreateJobType)
+	body := `{"name": "encoding", "sub_types": ["h264"]}`
+	request, _ := http.NewRequest(http.MethodPost, "/jobtypes", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_GetJobTypeByName_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No job type found for name encodng")
+	errorJson, _ := json.Marshal(apiError)
+	mockJobTypeService.EXPECT().GetJobTypeByName("encodng").Return(nil, apiError)
+	router.GET("/jobtypes/:job_type", th.GetJobTypeByName)
+	request, _ := http.NewRequest(http.MethodGet, "/jobtypes/encodng", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNotFound, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_UpdateJobType_Returns_NoError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	typeReq := dto.CreateUpdateJobTypeRequest{
+		Actions: []string{"transcode"},
+	}
+	typeResp := dto.JobTypeResponse{
+		Name:    "encoding",
+		Actions: []string{"transcode"},
+	}
+	respJson, _ := json.Marshal(typeResp)
+	mockJobTypeService.EXPECT().UpdateJobType("encoding", typeReq).Return(&typeResp, nil)
+	router.PUT("/jobtypes/:job_type", th.UpdateJobType)
+	body := `{"actions": ["transcode"]}`
+	request, _ := http.NewRequest(http.MethodPut, "/jobtypes/encoding", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_DeleteJobTypeByName_Returns_NoError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+	mockJobTypeService.EXPECT().DeleteJobTypeByName("encoding").Return(nil)
+	router.DELETE("/jobtypes/:job_type", th.DeleteJobTypeByName)
+	request, _ := http.NewRequest(http.MethodDelete, "/jobtypes/encoding", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/segmentio/ksuid"
+)
+
+type ScheduleHandler struct {
+	Service     service.ScheduleService
+	TypeService service.JobTypeService
+	Cfg         *config.AppConfig
+}
+
+func NewScheduleHandler(cfg *config.AppConfig, svc service.ScheduleService, typeSvc service.JobTypeService) ScheduleHandler {
+	return ScheduleHandler{
+		Cfg:         cfg,
+		Service:     svc,
+		TypeService: typeSvc,
+	}
+}
+
+func (sh *ScheduleHandler) getScheduleId(scheduleIdParam string) (string, api_error.ApiErr) {
+	scheduleIdParam = sh.Cfg.RunTime.BmPolicy.Sanitize(scheduleIdParam)
+	scheduleId, err := ksuid.Parse(scheduleIdParam)
+	if err != nil {
+		msg := "Schedule Id should be a ksuid"
+		logger.Error(msg, err)
+		return "", api_error.NewBadRequestError(msg)
+	}
+	return scheduleId.String(), nil
+}
+
+func (sh *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var newSchedReq dto.CreateUpdateScheduleRequest
+	if err := c.ShouldBindJSON(&newSchedReq); err != nil {
+		msg := "Invalid JSON body in create schedule request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	sh.Cfg.RunTime.Sani.Sanitize(&newSchedReq)
+	err := validateCreateScheduleRequest(newSchedReq, sh.TypeService)
+	if err != nil {
+		msg := "Could not validate input data for create schedule request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := sh.Service.CreateSchedule(newSchedReq)
+	if err != nil {
+		logger.Error("Service error while creating schedule", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (sh *ScheduleHandler) GetAllSchedules(c *gin.Context) {
+	schedules, err := sh.Service.GetAllSchedules()
+	if err != nil {
+		logger.Error("Service error while getting all schedules", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (sh *ScheduleHandler) GetScheduleById(c *gin.Context) {
+	scheduleId, err := sh.getScheduleId(c.Param("schedule_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	schedule, err := sh.Service.GetScheduleById(scheduleId)
+	if err != nil {
+		logger.Error("Service error while getting schedule by id", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (sh *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	scheduleId, err := sh.getScheduleId(c.Param("schedule_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	var updSchedReq dto.CreateUpdateScheduleRequest
+	if err := c.ShouldBindJSON(&updSchedReq); err != nil {
+		msg := "Invalid JSON body in update schedule request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	sh.Cfg.RunTime.Sani.Sanitize(&updSchedReq)
+	err = validateUpdateScheduleRequest(updSchedReq, sh.TypeService)
+	if err != nil {
+		msg := "Could not validate input data for update schedule request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := sh.Service.UpdateSchedule(scheduleId, updSchedReq)
+	if err != nil {
+		logger.Error("Service error while updating schedule", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (sh *ScheduleHandler) DeleteScheduleById(c *gin.Context) {
+	scheduleId, err := sh.getScheduleId(c.Param("schedule_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	err = sh.Service.DeleteScheduleById(scheduleId)
+	if err != nil {
+		logger.Error("Service error while deleting schedule by id", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/robfig/cron/v3"
+)
+
+func validateCreateScheduleRequest(newReq dto.CreateUpdateScheduleRequest, jobTypes service.JobTypeService) api_error.ApiErr {
+	if newReq.CronExpr == "" {
+		return api_error.NewBadRequestError("Schedule create request must have a cron expression")
+	}
+	if newReq.Job == nil {
+		return api_error.NewBadRequestError("Schedule create request must have a job")
+	}
+	return validateUpdateScheduleRequest(newReq, jobTypes)
+}
+
+func validateUpdateScheduleRequest(newReq dto.CreateUpdateScheduleRequest, jobTypes service.JobTypeService) api_error.ApiErr {
+	if newReq.CronExpr != "" {
+		if _, err := cron.ParseStandard(newReq.CronExpr); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Cron expression %v is not valid", newReq.CronExpr))
+		}
+	}
+	if newReq.TimeZone != "" {
+		if _, err := time.LoadLocation(newReq.TimeZone); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Time zone %v does not exist", newReq.TimeZone))
+		}
+	}
+	if newReq.MissedRunPolicy != "" {
+		if !domain.IsValidMissedRunPolicy(newReq.MissedRunPolicy) {
+			return api_error.NewBadRequestError(fmt.Sprintf("Missed run policy %v does not exist", newReq.MissedRunPolicy))
+		}
+	}
+	if newReq.Job != nil {
+		if err := validateCreateJobRequest(*newReq.Job, jobTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
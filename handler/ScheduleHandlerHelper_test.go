@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateCreateScheduleRequest_NoCron_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateScheduleRequest{
+		Job: &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	err := validateCreateScheduleRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Schedule create request must have a cron expression", err.Message())
+}
+
+func Test_validateCreateScheduleRequest_NoJob_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+	}
+
+	err := validateCreateScheduleRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Schedule create request must have a job", err.Message())
+}
+
+func Test_validateUpdateScheduleRequest_InvalidTimeZone_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateScheduleRequest{
+		TimeZone: "Mars/Olympus",
+	}
+
+	err := validateUpdateScheduleRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Time zone Mars/Olympus does not exist", err.Message())
+}
+
+func Test_validateUpdateScheduleRequest_InvalidMissedRunPolicy_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateUpdateScheduleRequest{
+		MissedRunPolicy: "catch_up",
+	}
+
+	err := validateUpdateScheduleRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Missed run policy catch_up does not exist", err.Message())
+}
+
+func Test_validateCreateScheduleRequest_ValidRequest_Returns_NoError(t *testing.T) {
+	req := dto.CreateUpdateScheduleRequest{
+		CronExpr:        "*/15 * * * *",
+		TimeZone:        "Europe/Berlin",
+		MissedRunPolicy: "run_once",
+		Job:             &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	err := validateCreateScheduleRequest(req, nil)
+
+	assert.Nil(t, err)
+}
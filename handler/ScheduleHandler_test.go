@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sh                  ScheduleHandler
+	mockScheduleService *service.MockScheduleService
+)
+
+func setupScheduleTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	ctrl := gomock.NewController(t)
+	mockScheduleService = service.NewMockScheduleService(ctrl)
+	sh = NewScheduleHandler(&cfg, mockScheduleService, nil)
+	return func() {
+		teardown()
+		ctrl.Finish()
+	}
+}
+
+func Test_getScheduleId_NonKsuid_Returns_BadRequestError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	schedId, err := sh.getScheduleId("wrong_id")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "", schedId)
+	assert.EqualValues(t, "Schedule Id should be a ksuid", err.Message())
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_CreateSchedule_Returns_InvalidJsonError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Invalid JSON body in create schedule request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/schedules", sh.CreateSchedule)
+	request, _ := http.NewRequest(http.MethodPost, "/schedules", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateSchedule_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Could not validate input data for create schedule request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/schedules", sh.CreateSchedule)
+	body := `{"cron": "every morning", "job": {"type": "encoding"}}`
+	request, _ := http.NewRequest(http.MethodPost, "/schedules", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateSchedule_Returns_NoError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	schedResp := dto.ScheduleResponse{
+		Id:       ksuid.New().String(),
+		CronExpr: "0 6 * * *",
+		Job:      *schedReq.Job,
+	}
+	respJson, _ := json.Marshal(schedResp)
+	mockScheduleService.EXPECT().CreateSchedule(schedReq).Return(&schedResp, nil)
+	router.POST("/schedules", sh.CreateSchedule)
+	body := `{"cron": "0 6 * * *", "job": {"type": "encoding"}}`
+	request, _ := http.NewRequest(http.MethodPost, "/schedules", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_GetScheduleById_Returns_NotFoundError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+	id := ksuid.New()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No schedule found for id %v", id))
+	errorJson, _ := json.Marshal(apiError)
+	mockScheduleService.EXPECT().GetScheduleById(id.String()).Return(nil, apiError)
+	router.GET("/schedules/:schedule_id", sh.GetScheduleById)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/schedules/%v", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNotFound, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_DeleteScheduleById_Returns_NoError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+	id := ksuid.New()
+	mockScheduleService.EXPECT().DeleteScheduleById(id.String()).Return(nil)
+	router.DELETE("/schedules/:schedule_id", sh.DeleteScheduleById)
+	request, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/schedules/%v", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusNoContent, recorder.Code)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/segmentio/ksuid"
+)
+
+type WorkflowHandler struct {
+	Service     service.WorkflowService
+	TypeService service.JobTypeService
+	Cfg         *config.AppConfig
+}
+
+func NewWorkflowHandler(cfg *config.AppConfig, svc service.WorkflowService, typeSvc service.JobTypeService) WorkflowHandler {
+	return WorkflowHandler{
+		Cfg:         cfg,
+		Service:     svc,
+		TypeService: typeSvc,
+	}
+}
+
+func (wh *WorkflowHandler) getWorkflowId(workflowIdParam string) (string, api_error.ApiErr) {
+	workflowIdParam = wh.Cfg.RunTime.BmPolicy.Sanitize(workflowIdParam)
+	workflowId, err := ksuid.Parse(workflowIdParam)
+	if err != nil {
+		msg := "Workflow Id should be a ksuid"
+		logger.Error(msg, err)
+		return "", api_error.NewBadRequestError(msg)
+	}
+	return workflowId.String(), nil
+}
+
+func (wh *WorkflowHandler) CreateWorkflow(c *gin.Context) {
+	var newWfReq dto.CreateWorkflowRequest
+	if err := c.ShouldBindJSON(&newWfReq); err != nil {
+		msg := "Invalid JSON body in create workflow request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	wh.Cfg.RunTime.Sani.Sanitize(&newWfReq)
+	err := validateCreateWorkflowRequest(newWfReq, wh.TypeService)
+	if err != nil {
+		msg := "Could not validate input data for create workflow request"
+		logger.Error(msg, err)
+		apiErr := api_error.NewBadRequestError(msg)
+		c.JSON(apiErr.StatusCode(), apiErr)
+		return
+	}
+	result, err := wh.Service.CreateWorkflow(newWfReq)
+	if err != nil {
+		logger.Error("Service error while creating workflow", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (wh *WorkflowHandler) GetAllWorkflows(c *gin.Context) {
+	workflows, err := wh.Service.GetAllWorkflows()
+	if err != nil {
+		logger.Error("Service error while getting all workflows", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, workflows)
+}
+
+func (wh *WorkflowHandler) GetWorkflowById(c *gin.Context) {
+	workflowId, err := wh.getWorkflowId(c.Param("workflow_id"))
+	if err != nil {
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	workflow, err := wh.Service.GetWorkflowById(workflowId)
+	if err != nil {
+		logger.Error("Service error while getting workflow by id", err)
+		c.JSON(err.StatusCode(), err)
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+func validateCreateWorkflowRequest(newReq dto.CreateWorkflowRequest, jobTypes service.JobTypeService) api_error.ApiErr {
+	if len(newReq.Stages) == 0 {
+		return api_error.NewBadRequestError("Workflow create request must have at least one stage")
+	}
+	for idx, stage := range newReq.Stages {
+		if err := validateCreateJobRequest(stage, jobTypes); err != nil {
+			return api_error.NewBadRequestError(fmt.Sprintf("Stage %d: %v", idx+1, err.Message()))
+		}
+		if len(stage.DependsOn) > 0 {
+			return api_error.NewBadRequestError(fmt.Sprintf("Stage %d: stages of a workflow cannot have prerequisites", idx+1))
+		}
+	}
+	return nil
+}
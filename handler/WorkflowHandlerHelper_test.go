@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateCreateWorkflowRequest_NoStages_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateWorkflowRequest{Name: "pipeline"}
+
+	err := validateCreateWorkflowRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Workflow create request must have at least one stage", err.Message())
+}
+
+func Test_validateCreateWorkflowRequest_StageWithoutType_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateWorkflowRequest{
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding"}, {}},
+	}
+
+	err := validateCreateWorkflowRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Stage 2: Job create / update request must have a type", err.Message())
+}
+
+func Test_validateCreateWorkflowRequest_StageWithPrerequisites_Returns_BadRequestError(t *testing.T) {
+	req := dto.CreateWorkflowRequest{
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding", DependsOn: []string{ksuid.New().String()}}},
+	}
+
+	err := validateCreateWorkflowRequest(req, nil)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Stage 1: stages of a workflow cannot have prerequisites", err.Message())
+}
+
+func Test_validateCreateWorkflowRequest_ValidRequest_Returns_NoError(t *testing.T) {
+	req := dto.CreateWorkflowRequest{
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding"}, {Type: "proxy", Priority: "high"}},
+	}
+
+	err := validateCreateWorkflowRequest(req, nil)
+
+	assert.Nil(t, err)
+}
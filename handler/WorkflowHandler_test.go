@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/service"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	wh                  WorkflowHandler
+	mockWorkflowService *service.MockWorkflowService
+)
+
+func setupWorkflowTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	ctrl := gomock.NewController(t)
+	mockWorkflowService = service.NewMockWorkflowService(ctrl)
+	wh = NewWorkflowHandler(&cfg, mockWorkflowService, nil)
+	return func() {
+		teardown()
+		ctrl.Finish()
+	}
+}
+
+func Test_CreateWorkflow_Returns_InvalidJsonError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Invalid JSON body in create workflow request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/workflows", wh.CreateWorkflow)
+	request, _ := http.NewRequest(http.MethodPost, "/workflows", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateWorkflow_Returns_InvalidInputError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Could not validate input data for create workflow request")
+	errorJson, _ := json.Marshal(apiError)
+	router.POST("/workflows", wh.CreateWorkflow)
+	body := `{"name": "pipeline", "stages": []}`
+	request, _ := http.NewRequest(http.MethodPost, "/workflows", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_CreateWorkflow_Returns_NoError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+	wfReq := dto.CreateWorkflowRequest{
+		Name:   "pipeline",
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding"}, {Type: "proxy"}},
+	}
+	wfResp := dto.WorkflowResponse{
+		Id:         ksuid.New().String(),
+		Name:       "pipeline",
+		Status:     "running",
+		StageCount: 2,
+	}
+	respJson, _ := json.Marshal(wfResp)
+	mockWorkflowService.EXPECT().CreateWorkflow(wfReq).Return(&wfResp, nil)
+	router.POST("/workflows", wh.CreateWorkflow)
+	body := `{"name": "pipeline", "stages": [{"type": "encoding"}, {"type": "proxy"}]}`
+	request, _ := http.NewRequest(http.MethodPost, "/workflows", strings.NewReader(body))
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusCreated, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
+
+func Test_GetWorkflowById_Returns_InvalidIdError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+	apiError := api_error.NewBadRequestError("Workflow Id should be a ksuid")
+	errorJson, _ := json.Marshal(apiError)
+	router.GET("/workflows/:workflow_id", wh.GetWorkflowById)
+	request, _ := http.NewRequest(http.MethodGet, "/workflows/not_a_ksuid", nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusBadRequest, recorder.Code)
+	assert.EqualValues(t, errorJson, recorder.Body.String())
+}
+
+func Test_GetWorkflowById_Returns_NoError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+	id := ksuid.New()
+	wfResp := dto.WorkflowResponse{Id: id.String(), Status: "finished", Progress: 100}
+	respJson, _ := json.Marshal(wfResp)
+	mockWorkflowService.EXPECT().GetWorkflowById(id.String()).Return(&wfResp, nil)
+	router.GET("/workflows/:workflow_id", wh.GetWorkflowById)
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/workflows/%v", id), nil)
+
+	router.ServeHTTP(recorder, request)
+
+	assert.EqualValues(t, http.StatusOK, recorder.Code)
+	assert.EqualValues(t, respJson, recorder.Body.String())
+}
@@ -36,6 +36,36 @@ func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddChildren mocks base method.
+func (m *MockJobRepository) AddChildren(arg0 string, arg1 []domain.Job) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddChildren", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// AddChildren indicates an expected call of AddChildren.
+func (mr *MockJobRepositoryMockRecorder) AddChildren(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddChildren", reflect.TypeOf((*MockJobRepository)(nil).AddChildren), arg0, arg1)
+}
+
+// Cancel mocks base method.
+func (m *MockJobRepository) Cancel(arg0 string) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockJobRepositoryMockRecorder) Cancel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockJobRepository)(nil).Cancel), arg0)
+}
+
 // CleanupJobs mocks base method.
 func (m *MockJobRepository) CleanupJobs() api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -79,10 +109,10 @@ func (mr *MockJobRepositoryMockRecorder) DeleteById(arg0 interface{}) *gomock.Ca
 }
 
 // Dequeue mocks base method.
-func (m *MockJobRepository) Dequeue(arg0 string) (*domain.Job, api_error.ApiErr) {
+func (m *MockJobRepository) Dequeue(arg0 dto.DequeueRequest) (*[]domain.Job, api_error.ApiErr) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Dequeue", arg0)
-	ret0, _ := ret[0].(*domain.Job)
+	ret0, _ := ret[0].(*[]domain.Job)
 	ret1, _ := ret[1].(api_error.ApiErr)
 	return ret0, ret1
 }
@@ -93,6 +123,21 @@ func (mr *MockJobRepositoryMockRecorder) Dequeue(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dequeue", reflect.TypeOf((*MockJobRepository)(nil).Dequeue), arg0)
 }
 
+// FindActiveByUniqueKey mocks base method.
+func (m *MockJobRepository) FindActiveByUniqueKey(arg0 string) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveByUniqueKey", arg0)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindActiveByUniqueKey indicates an expected call of FindActiveByUniqueKey.
+func (mr *MockJobRepositoryMockRecorder) FindActiveByUniqueKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveByUniqueKey", reflect.TypeOf((*MockJobRepository)(nil).FindActiveByUniqueKey), arg0)
+}
+
 // FindAll mocks base method.
 func (m *MockJobRepository) FindAll(arg0 dto.SortAndFilterRequest) (*[]domain.Job, int, api_error.ApiErr) {
 	m.ctrl.T.Helper()
@@ -109,6 +154,22 @@ func (mr *MockJobRepositoryMockRecorder) FindAll(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockJobRepository)(nil).FindAll), arg0)
 }
 
+// FindAllDeadLetters mocks base method.
+func (m *MockJobRepository) FindAllDeadLetters(arg0 dto.SortAndFilterRequest) (*[]domain.Job, int, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllDeadLetters", arg0)
+	ret0, _ := ret[0].(*[]domain.Job)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(api_error.ApiErr)
+	return ret0, ret1, ret2
+}
+
+// FindAllDeadLetters indicates an expected call of FindAllDeadLetters.
+func (mr *MockJobRepositoryMockRecorder) FindAllDeadLetters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllDeadLetters", reflect.TypeOf((*MockJobRepository)(nil).FindAllDeadLetters), arg0)
+}
+
 // FindById mocks base method.
 func (m *MockJobRepository) FindById(arg0 string) (*domain.Job, api_error.ApiErr) {
 	m.ctrl.T.Helper()
@@ -124,6 +185,126 @@ func (mr *MockJobRepositoryMockRecorder) FindById(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockJobRepository)(nil).FindById), arg0)
 }
 
+// FindChildren mocks base method.
+func (m *MockJobRepository) FindChildren(arg0 string) (*[]domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildren", arg0)
+	ret0, _ := ret[0].(*[]domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindChildren indicates an expected call of FindChildren.
+func (mr *MockJobRepositoryMockRecorder) FindChildren(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildren", reflect.TypeOf((*MockJobRepository)(nil).FindChildren), arg0)
+}
+
+// FindDeadLetterById mocks base method.
+func (m *MockJobRepository) FindDeadLetterById(arg0 string) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeadLetterById", arg0)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindDeadLetterById indicates an expected call of FindDeadLetterById.
+func (mr *MockJobRepositoryMockRecorder) FindDeadLetterById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeadLetterById", reflect.TypeOf((*MockJobRepository)(nil).FindDeadLetterById), arg0)
+}
+
+// FindDependencies mocks base method.
+func (m *MockJobRepository) FindDependencies(arg0 []string) (*[]domain.JobDependency, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDependencies", arg0)
+	ret0, _ := ret[0].(*[]domain.JobDependency)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindDependencies indicates an expected call of FindDependencies.
+func (mr *MockJobRepositoryMockRecorder) FindDependencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDependencies", reflect.TypeOf((*MockJobRepository)(nil).FindDependencies), arg0)
+}
+
+// FindEvents mocks base method.
+func (m *MockJobRepository) FindEvents(arg0 string, arg1, arg2 int) (*[]domain.JobEvent, int, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*[]domain.JobEvent)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(api_error.ApiErr)
+	return ret0, ret1, ret2
+}
+
+// FindEvents indicates an expected call of FindEvents.
+func (mr *MockJobRepositoryMockRecorder) FindEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEvents", reflect.TypeOf((*MockJobRepository)(nil).FindEvents), arg0, arg1, arg2)
+}
+
+// Heartbeat mocks base method.
+func (m *MockJobRepository) Heartbeat(arg0 string, arg1 dto.HeartbeatRequest) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockJobRepositoryMockRecorder) Heartbeat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockJobRepository)(nil).Heartbeat), arg0, arg1)
+}
+
+// NotifyJobCreated mocks base method.
+func (m *MockJobRepository) NotifyJobCreated(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyJobCreated", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// NotifyJobCreated indicates an expected call of NotifyJobCreated.
+func (mr *MockJobRepositoryMockRecorder) NotifyJobCreated(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyJobCreated", reflect.TypeOf((*MockJobRepository)(nil).NotifyJobCreated), arg0)
+}
+
+// ReleaseExpiredLeases mocks base method.
+func (m *MockJobRepository) ReleaseExpiredLeases() (int, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredLeases")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// ReleaseExpiredLeases indicates an expected call of ReleaseExpiredLeases.
+func (mr *MockJobRepositoryMockRecorder) ReleaseExpiredLeases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredLeases", reflect.TypeOf((*MockJobRepository)(nil).ReleaseExpiredLeases))
+}
+
+// RequeueDeadLetter mocks base method.
+func (m *MockJobRepository) RequeueDeadLetter(arg0 string) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadLetter", arg0)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// RequeueDeadLetter indicates an expected call of RequeueDeadLetter.
+func (mr *MockJobRepositoryMockRecorder) RequeueDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadLetter", reflect.TypeOf((*MockJobRepository)(nil).RequeueDeadLetter), arg0)
+}
+
 // SetHistoryById mocks base method.
 func (m *MockJobRepository) SetHistoryById(arg0, arg1 string) api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -138,8 +319,23 @@ func (mr *MockJobRepositoryMockRecorder) SetHistoryById(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistoryById", reflect.TypeOf((*MockJobRepository)(nil).SetHistoryById), arg0, arg1)
 }
 
+// SetProgressById mocks base method.
+func (m *MockJobRepository) SetProgressById(arg0 string, arg1 dto.UpdateJobProgressRequest) (*domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProgressById", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// SetProgressById indicates an expected call of SetProgressById.
+func (mr *MockJobRepositoryMockRecorder) SetProgressById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProgressById", reflect.TypeOf((*MockJobRepository)(nil).SetProgressById), arg0, arg1)
+}
+
 // SetStatusById mocks base method.
-func (m *MockJobRepository) SetStatusById(arg0, arg1, arg2 string) api_error.ApiErr {
+func (m *MockJobRepository) SetStatusById(arg0 string, arg1 dto.UpdateJobStatusRequest, arg2 string) api_error.ApiErr {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetStatusById", arg0, arg1, arg2)
 	ret0, _ := ret[0].(api_error.ApiErr)
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/domain (interfaces: JobTemplateRepository)
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/johannes-kuhfuss/jobsvc/domain"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockJobTemplateRepository is a mock of JobTemplateRepository interface.
+type MockJobTemplateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobTemplateRepositoryMockRecorder
+}
+
+// MockJobTemplateRepositoryMockRecorder is the mock recorder for MockJobTemplateRepository.
+type MockJobTemplateRepositoryMockRecorder struct {
+	mock *MockJobTemplateRepository
+}
+
+// NewMockJobTemplateRepository creates a new mock instance.
+func NewMockJobTemplateRepository(ctrl *gomock.Controller) *MockJobTemplateRepository {
+	mock := &MockJobTemplateRepository{ctrl: ctrl}
+	mock.recorder = &MockJobTemplateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobTemplateRepository) EXPECT() *MockJobTemplateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByName mocks base method.
+func (m *MockJobTemplateRepository) DeleteByName(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByName", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeleteByName indicates an expected call of DeleteByName.
+func (mr *MockJobTemplateRepositoryMockRecorder) DeleteByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByName", reflect.TypeOf((*MockJobTemplateRepository)(nil).DeleteByName), arg0)
+}
+
+// FindAll mocks base method.
+func (m *MockJobTemplateRepository) FindAll() (*[]domain.JobTemplate, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].(*[]domain.JobTemplate)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockJobTemplateRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockJobTemplateRepository)(nil).FindAll))
+}
+
+// FindByName mocks base method.
+func (m *MockJobTemplateRepository) FindByName(arg0 string, arg1 int32) (*domain.JobTemplate, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", arg0, arg1)
+	ret0, _ := ret[0].(*domain.JobTemplate)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockJobTemplateRepositoryMockRecorder) FindByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockJobTemplateRepository)(nil).FindByName), arg0, arg1)
+}
+
+// FindVersions mocks base method.
+func (m *MockJobTemplateRepository) FindVersions(arg0 string) (*[]domain.JobTemplate, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVersions", arg0)
+	ret0, _ := ret[0].(*[]domain.JobTemplate)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindVersions indicates an expected call of FindVersions.
+func (mr *MockJobTemplateRepositoryMockRecorder) FindVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVersions", reflect.TypeOf((*MockJobTemplateRepository)(nil).FindVersions), arg0)
+}
+
+// Store mocks base method.
+func (m *MockJobTemplateRepository) Store(arg0 domain.JobTemplate) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockJobTemplateRepositoryMockRecorder) Store(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockJobTemplateRepository)(nil).Store), arg0)
+}
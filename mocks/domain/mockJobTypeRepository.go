@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/domain (interfaces: JobTypeRepository)
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/johannes-kuhfuss/jobsvc/domain"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockJobTypeRepository is a mock of JobTypeRepository interface.
+type MockJobTypeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobTypeRepositoryMockRecorder
+}
+
+// MockJobTypeRepositoryMockRecorder is the mock recorder for MockJobTypeRepository.
+type MockJobTypeRepositoryMockRecorder struct {
+	mock *MockJobTypeRepository
+}
+
+// NewMockJobTypeRepository creates a new mock instance.
+func NewMockJobTypeRepository(ctrl *gomock.Controller) *MockJobTypeRepository {
+	mock := &MockJobTypeRepository{ctrl: ctrl}
+	mock.recorder = &MockJobTypeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobTypeRepository) EXPECT() *MockJobTypeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteByName mocks base method.
+func (m *MockJobTypeRepository) DeleteByName(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByName", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeleteByName indicates an expected call of DeleteByName.
+func (mr *MockJobTypeRepositoryMockRecorder) DeleteByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByName", reflect.TypeOf((*MockJobTypeRepository)(nil).DeleteByName), arg0)
+}
+
+// FindAll mocks base method.
+func (m *MockJobTypeRepository) FindAll() (*[]domain.JobType, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].(*[]domain.JobType)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockJobTypeRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockJobTypeRepository)(nil).FindAll))
+}
+
+// FindByName mocks base method.
+func (m *MockJobTypeRepository) FindByName(arg0 string) (*domain.JobType, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByName", arg0)
+	ret0, _ := ret[0].(*domain.JobType)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindByName indicates an expected call of FindByName.
+func (mr *MockJobTypeRepositoryMockRecorder) FindByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockJobTypeRepository)(nil).FindByName), arg0)
+}
+
+// Store mocks base method.
+func (m *MockJobTypeRepository) Store(arg0 domain.JobType) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockJobTypeRepositoryMockRecorder) Store(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockJobTypeRepository)(nil).Store), arg0)
+}
+
+// Update mocks base method.
+func (m *MockJobTypeRepository) Update(arg0 domain.JobType) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockJobTypeRepositoryMockRecorder) Update(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockJobTypeRepository)(nil).Update), arg0)
+}
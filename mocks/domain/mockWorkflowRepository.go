@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/domain (interfaces: WorkflowRepository)
+
+// Package domain is a generated GoMock package.
+package domain
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/johannes-kuhfuss/jobsvc/domain"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockWorkflowRepository is a mock of WorkflowRepository interface.
+type MockWorkflowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowRepositoryMockRecorder
+}
+
+// MockWorkflowRepositoryMockRecorder is the mock recorder for MockWorkflowRepository.
+type MockWorkflowRepositoryMockRecorder struct {
+	mock *MockWorkflowRepository
+}
+
+// NewMockWorkflowRepository creates a new mock instance.
+func NewMockWorkflowRepository(ctrl *gomock.Controller) *MockWorkflowRepository {
+	mock := &MockWorkflowRepository{ctrl: ctrl}
+	mock.recorder = &MockWorkflowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowRepository) EXPECT() *MockWorkflowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdvanceStage mocks base method.
+func (m *MockWorkflowRepository) AdvanceStage(arg0 domain.Workflow, arg1 int32, arg2 *domain.Job) (bool, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceStage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// AdvanceStage indicates an expected call of AdvanceStage.
+func (mr *MockWorkflowRepositoryMockRecorder) AdvanceStage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceStage", reflect.TypeOf((*MockWorkflowRepository)(nil).AdvanceStage), arg0, arg1, arg2)
+}
+
+// FindAll mocks base method.
+func (m *MockWorkflowRepository) FindAll() (*[]domain.Workflow, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].(*[]domain.Workflow)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockWorkflowRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockWorkflowRepository)(nil).FindAll))
+}
+
+// FindById mocks base method.
+func (m *MockWorkflowRepository) FindById(arg0 string) (*domain.Workflow, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0)
+	ret0, _ := ret[0].(*domain.Workflow)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockWorkflowRepositoryMockRecorder) FindById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockWorkflowRepository)(nil).FindById), arg0)
+}
+
+// FindRunning mocks base method.
+func (m *MockWorkflowRepository) FindRunning() (*[]domain.Workflow, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRunning")
+	ret0, _ := ret[0].(*[]domain.Workflow)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindRunning indicates an expected call of FindRunning.
+func (mr *MockWorkflowRepositoryMockRecorder) FindRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRunning", reflect.TypeOf((*MockWorkflowRepository)(nil).FindRunning))
+}
+
+// FindStageJobs mocks base method.
+func (m *MockWorkflowRepository) FindStageJobs(arg0 []string) (*[]domain.Job, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStageJobs", arg0)
+	ret0, _ := ret[0].(*[]domain.Job)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// FindStageJobs indicates an expected call of FindStageJobs.
+func (mr *MockWorkflowRepositoryMockRecorder) FindStageJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStageJobs", reflect.TypeOf((*MockWorkflowRepository)(nil).FindStageJobs), arg0)
+}
+
+// Store mocks base method.
+func (m *MockWorkflowRepository) Store(arg0 domain.Workflow, arg1 domain.Job) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", arg0, arg1)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockWorkflowRepositoryMockRecorder) Store(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockWorkflowRepository)(nil).Store), arg0, arg1)
+}
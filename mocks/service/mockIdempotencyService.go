@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/service (interfaces: IdempotencyService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/jobsvc/dto"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// AbortJobRequest mocks base method.
+func (m *MockIdempotencyService) AbortJobRequest(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortJobRequest", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// AbortJobRequest indicates an expected call of AbortJobRequest.
+func (mr *MockIdempotencyServiceMockRecorder) AbortJobRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortJobRequest", reflect.TypeOf((*MockIdempotencyService)(nil).AbortJobRequest), arg0)
+}
+
+// BeginJobRequest mocks base method.
+func (m *MockIdempotencyService) BeginJobRequest(arg0 string, arg1 dto.CreateUpdateJobRequest) (*dto.IdempotentJobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginJobRequest", arg0, arg1)
+	ret0, _ := ret[0].(*dto.IdempotentJobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// BeginJobRequest indicates an expected call of BeginJobRequest.
+func (mr *MockIdempotencyServiceMockRecorder) BeginJobRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginJobRequest", reflect.TypeOf((*MockIdempotencyService)(nil).BeginJobRequest), arg0, arg1)
+}
+
+// CleanKeys mocks base method.
+func (m *MockIdempotencyService) CleanKeys() api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanKeys")
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// CleanKeys indicates an expected call of CleanKeys.
+func (mr *MockIdempotencyServiceMockRecorder) CleanKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanKeys", reflect.TypeOf((*MockIdempotencyService)(nil).CleanKeys))
+}
+
+// CompleteJobRequest mocks base method.
+func (m *MockIdempotencyService) CompleteJobRequest(arg0 string, arg1 int, arg2 dto.JobResponse) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJobRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// CompleteJobRequest indicates an expected call of CompleteJobRequest.
+func (mr *MockIdempotencyServiceMockRecorder) CompleteJobRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJobRequest", reflect.TypeOf((*MockIdempotencyService)(nil).CompleteJobRequest), arg0, arg1, arg2)
+}
@@ -5,6 +5,7 @@
 package service
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,21 @@ func (m *MockJobService) EXPECT() *MockJobServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelJob mocks base method.
+func (m *MockJobService) CancelJob(arg0 string) (*dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", arg0)
+	ret0, _ := ret[0].(*dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockJobServiceMockRecorder) CancelJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockJobService)(nil).CancelJob), arg0)
+}
+
 // CleanJobs mocks base method.
 func (m *MockJobService) CleanJobs() api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -49,6 +65,21 @@ func (mr *MockJobServiceMockRecorder) CleanJobs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanJobs", reflect.TypeOf((*MockJobService)(nil).CleanJobs))
 }
 
+// CreateChildJobs mocks base method.
+func (m *MockJobService) CreateChildJobs(arg0 string, arg1 dto.CreateChildJobsRequest) (*[]dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChildJobs", arg0, arg1)
+	ret0, _ := ret[0].(*[]dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateChildJobs indicates an expected call of CreateChildJobs.
+func (mr *MockJobServiceMockRecorder) CreateChildJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChildJobs", reflect.TypeOf((*MockJobService)(nil).CreateChildJobs), arg0, arg1)
+}
+
 // CreateJob mocks base method.
 func (m *MockJobService) CreateJob(arg0 dto.CreateUpdateJobRequest) (*dto.JobResponse, api_error.ApiErr) {
 	m.ctrl.T.Helper()
@@ -93,18 +124,34 @@ func (mr *MockJobServiceMockRecorder) DeleteJobById(arg0 interface{}) *gomock.Ca
 }
 
 // Dequeue mocks base method.
-func (m *MockJobService) Dequeue(arg0 dto.DequeueRequest) (*dto.JobResponse, api_error.ApiErr) {
+func (m *MockJobService) Dequeue(arg0 context.Context, arg1 dto.DequeueRequest) (*[]dto.JobResponse, api_error.ApiErr) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Dequeue", arg0)
-	ret0, _ := ret[0].(*dto.JobResponse)
+	ret := m.ctrl.Call(m, "Dequeue", arg0, arg1)
+	ret0, _ := ret[0].(*[]dto.JobResponse)
 	ret1, _ := ret[1].(api_error.ApiErr)
 	return ret0, ret1
 }
 
 // Dequeue indicates an expected call of Dequeue.
-func (mr *MockJobServiceMockRecorder) Dequeue(arg0 interface{}) *gomock.Call {
+func (mr *MockJobServiceMockRecorder) Dequeue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dequeue", reflect.TypeOf((*MockJobService)(nil).Dequeue), arg0, arg1)
+}
+
+// GetAllDeadLetters mocks base method.
+func (m *MockJobService) GetAllDeadLetters(arg0 dto.SortAndFilterRequest) (*[]dto.JobResponse, int, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllDeadLetters", arg0)
+	ret0, _ := ret[0].(*[]dto.JobResponse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(api_error.ApiErr)
+	return ret0, ret1, ret2
+}
+
+// GetAllDeadLetters indicates an expected call of GetAllDeadLetters.
+func (mr *MockJobServiceMockRecorder) GetAllDeadLetters(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dequeue", reflect.TypeOf((*MockJobService)(nil).Dequeue), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDeadLetters", reflect.TypeOf((*MockJobService)(nil).GetAllDeadLetters), arg0)
 }
 
 // GetAllJobs mocks base method.
@@ -123,6 +170,36 @@ func (mr *MockJobServiceMockRecorder) GetAllJobs(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllJobs", reflect.TypeOf((*MockJobService)(nil).GetAllJobs), arg0)
 }
 
+// GetChildJobs mocks base method.
+func (m *MockJobService) GetChildJobs(arg0 string) (*[]dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildJobs", arg0)
+	ret0, _ := ret[0].(*[]dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetChildJobs indicates an expected call of GetChildJobs.
+func (mr *MockJobServiceMockRecorder) GetChildJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildJobs", reflect.TypeOf((*MockJobService)(nil).GetChildJobs), arg0)
+}
+
+// GetDeadLetterById mocks base method.
+func (m *MockJobService) GetDeadLetterById(arg0 string) (*dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetterById", arg0)
+	ret0, _ := ret[0].(*dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetDeadLetterById indicates an expected call of GetDeadLetterById.
+func (mr *MockJobServiceMockRecorder) GetDeadLetterById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetterById", reflect.TypeOf((*MockJobService)(nil).GetDeadLetterById), arg0)
+}
+
 // GetJobById mocks base method.
 func (m *MockJobService) GetJobById(arg0 string) (*dto.JobResponse, api_error.ApiErr) {
 	m.ctrl.T.Helper()
@@ -138,6 +215,66 @@ func (mr *MockJobServiceMockRecorder) GetJobById(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobById", reflect.TypeOf((*MockJobService)(nil).GetJobById), arg0)
 }
 
+// GetJobHistory mocks base method.
+func (m *MockJobService) GetJobHistory(arg0 string, arg1, arg2 int) (*[]dto.JobEventResponse, int, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*[]dto.JobEventResponse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(api_error.ApiErr)
+	return ret0, ret1, ret2
+}
+
+// GetJobHistory indicates an expected call of GetJobHistory.
+func (mr *MockJobServiceMockRecorder) GetJobHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobHistory", reflect.TypeOf((*MockJobService)(nil).GetJobHistory), arg0, arg1, arg2)
+}
+
+// Heartbeat mocks base method.
+func (m *MockJobService) Heartbeat(arg0 string, arg1 dto.HeartbeatRequest) (*dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockJobServiceMockRecorder) Heartbeat(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockJobService)(nil).Heartbeat), arg0, arg1)
+}
+
+// ReleaseExpiredLeases mocks base method.
+func (m *MockJobService) ReleaseExpiredLeases() api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredLeases")
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// ReleaseExpiredLeases indicates an expected call of ReleaseExpiredLeases.
+func (mr *MockJobServiceMockRecorder) ReleaseExpiredLeases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredLeases", reflect.TypeOf((*MockJobService)(nil).ReleaseExpiredLeases))
+}
+
+// RequeueDeadLetter mocks base method.
+func (m *MockJobService) RequeueDeadLetter(arg0 string) (*dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadLetter", arg0)
+	ret0, _ := ret[0].(*dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// RequeueDeadLetter indicates an expected call of RequeueDeadLetter.
+func (mr *MockJobServiceMockRecorder) RequeueDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadLetter", reflect.TypeOf((*MockJobService)(nil).RequeueDeadLetter), arg0)
+}
+
 // SetHistoryById mocks base method.
 func (m *MockJobService) SetHistoryById(arg0 string, arg1 dto.UpdateJobHistoryRequest) api_error.ApiErr {
 	m.ctrl.T.Helper()
@@ -152,6 +289,21 @@ func (mr *MockJobServiceMockRecorder) SetHistoryById(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHistoryById", reflect.TypeOf((*MockJobService)(nil).SetHistoryById), arg0, arg1)
 }
 
+// SetProgressById mocks base method.
+func (m *MockJobService) SetProgressById(arg0 string, arg1 dto.UpdateJobProgressRequest) (*dto.JobResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProgressById", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JobResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// SetProgressById indicates an expected call of SetProgressById.
+func (mr *MockJobServiceMockRecorder) SetProgressById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProgressById", reflect.TypeOf((*MockJobService)(nil).SetProgressById), arg0, arg1)
+}
+
 // SetStatusById mocks base method.
 func (m *MockJobService) SetStatusById(arg0 string, arg1 dto.UpdateJobStatusRequest) api_error.ApiErr {
 	m.ctrl.T.Helper()
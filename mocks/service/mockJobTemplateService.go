@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/service (interfaces: JobTemplateService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/jobsvc/dto"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockJobTemplateService is a mock of JobTemplateService interface.
+type MockJobTemplateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobTemplateServiceMockRecorder
+}
+
+// MockJobTemplateServiceMockRecorder is the mock recorder for MockJobTemplateService.
+type MockJobTemplateServiceMockRecorder struct {
+	mock *MockJobTemplateService
+}
+
+// NewMockJobTemplateService creates a new mock instance.
+func NewMockJobTemplateService(ctrl *gomock.Controller) *MockJobTemplateService {
+	mock := &MockJobTemplateService{ctrl: ctrl}
+	mock.recorder = &MockJobTemplateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobTemplateService) EXPECT() *MockJobTemplateServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateJobTemplate mocks base method.
+func (m *MockJobTemplateService) CreateJobTemplate(arg0 dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobTemplate", arg0)
+	ret0, _ := ret[0].(*dto.JobTemplateResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateJobTemplate indicates an expected call of CreateJobTemplate.
+func (mr *MockJobTemplateServiceMockRecorder) CreateJobTemplate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobTemplate", reflect.TypeOf((*MockJobTemplateService)(nil).CreateJobTemplate), arg0)
+}
+
+// DeleteJobTemplateByName mocks base method.
+func (m *MockJobTemplateService) DeleteJobTemplateByName(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJobTemplateByName", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeleteJobTemplateByName indicates an expected call of DeleteJobTemplateByName.
+func (mr *MockJobTemplateServiceMockRecorder) DeleteJobTemplateByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJobTemplateByName", reflect.TypeOf((*MockJobTemplateService)(nil).DeleteJobTemplateByName), arg0)
+}
+
+// GetAllJobTemplates mocks base method.
+func (m *MockJobTemplateService) GetAllJobTemplates() (*[]dto.JobTemplateResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllJobTemplates")
+	ret0, _ := ret[0].(*[]dto.JobTemplateResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetAllJobTemplates indicates an expected call of GetAllJobTemplates.
+func (mr *MockJobTemplateServiceMockRecorder) GetAllJobTemplates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllJobTemplates", reflect.TypeOf((*MockJobTemplateService)(nil).GetAllJobTemplates))
+}
+
+// GetJobTemplateByName mocks base method.
+func (m *MockJobTemplateService) GetJobTemplateByName(arg0 string, arg1 int32) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobTemplateByName", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JobTemplateResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJobTemplateByName indicates an expected call of GetJobTemplateByName.
+func (mr *MockJobTemplateServiceMockRecorder) GetJobTemplateByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobTemplateByName", reflect.TypeOf((*MockJobTemplateService)(nil).GetJobTemplateByName), arg0, arg1)
+}
+
+// GetJobTemplateVersions mocks base method.
+func (m *MockJobTemplateService) GetJobTemplateVersions(arg0 string) (*[]dto.JobTemplateResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobTemplateVersions", arg0)
+	ret0, _ := ret[0].(*[]dto.JobTemplateResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJobTemplateVersions indicates an expected call of GetJobTemplateVersions.
+func (mr *MockJobTemplateServiceMockRecorder) GetJobTemplateVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobTemplateVersions", reflect.TypeOf((*MockJobTemplateService)(nil).GetJobTemplateVersions), arg0)
+}
+
+// ResolveJobRequest mocks base method.
+func (m *MockJobTemplateService) ResolveJobRequest(arg0 dto.CreateUpdateJobRequest) (*dto.CreateUpdateJobRequest, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveJobRequest", arg0)
+	ret0, _ := ret[0].(*dto.CreateUpdateJobRequest)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// ResolveJobRequest indicates an expected call of ResolveJobRequest.
+func (mr *MockJobTemplateServiceMockRecorder) ResolveJobRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveJobRequest", reflect.TypeOf((*MockJobTemplateService)(nil).ResolveJobRequest), arg0)
+}
+
+// UpdateJobTemplate mocks base method.
+func (m *MockJobTemplateService) UpdateJobTemplate(arg0 string, arg1 dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobTemplate", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JobTemplateResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdateJobTemplate indicates an expected call of UpdateJobTemplate.
+func (mr *MockJobTemplateServiceMockRecorder) UpdateJobTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobTemplate", reflect.TypeOf((*MockJobTemplateService)(nil).UpdateJobTemplate), arg0, arg1)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/service (interfaces: JobTypeService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/jobsvc/dto"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockJobTypeService is a mock of JobTypeService interface.
+type MockJobTypeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobTypeServiceMockRecorder
+}
+
+// MockJobTypeServiceMockRecorder is the mock recorder for MockJobTypeService.
+type MockJobTypeServiceMockRecorder struct {
+	mock *MockJobTypeService
+}
+
+// NewMockJobTypeService creates a new mock instance.
+func NewMockJobTypeService(ctrl *gomock.Controller) *MockJobTypeService {
+	mock := &MockJobTypeService{ctrl: ctrl}
+	mock.recorder = &MockJobTypeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobTypeService) EXPECT() *MockJobTypeServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateJobType mocks base method.
+func (m *MockJobTypeService) CreateJobType(arg0 dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobType", arg0)
+	ret0, _ := ret[0].(*dto.JobTypeResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateJobType indicates an expected call of CreateJobType.
+func (mr *MockJobTypeServiceMockRecorder) CreateJobType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobType", reflect.TypeOf((*MockJobTypeService)(nil).CreateJobType), arg0)
+}
+
+// DeleteJobTypeByName mocks base method.
+func (m *MockJobTypeService) DeleteJobTypeByName(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJobTypeByName", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeleteJobTypeByName indicates an expected call of DeleteJobTypeByName.
+func (mr *MockJobTypeServiceMockRecorder) DeleteJobTypeByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJobTypeByName", reflect.TypeOf((*MockJobTypeService)(nil).DeleteJobTypeByName), arg0)
+}
+
+// GetAllJobTypes mocks base method.
+func (m *MockJobTypeService) GetAllJobTypes() (*[]dto.JobTypeResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllJobTypes")
+	ret0, _ := ret[0].(*[]dto.JobTypeResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetAllJobTypes indicates an expected call of GetAllJobTypes.
+func (mr *MockJobTypeServiceMockRecorder) GetAllJobTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllJobTypes", reflect.TypeOf((*MockJobTypeService)(nil).GetAllJobTypes))
+}
+
+// GetJobTypeByName mocks base method.
+func (m *MockJobTypeService) GetJobTypeByName(arg0 string) (*dto.JobTypeResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobTypeByName", arg0)
+	ret0, _ := ret[0].(*dto.JobTypeResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetJobTypeByName indicates an expected call of GetJobTypeByName.
+func (mr *MockJobTypeServiceMockRecorder) GetJobTypeByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobTypeByName", reflect.TypeOf((*MockJobTypeService)(nil).GetJobTypeByName), arg0)
+}
+
+// UpdateJobType mocks base method.
+func (m *MockJobTypeService) UpdateJobType(arg0 string, arg1 dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobType", arg0, arg1)
+	ret0, _ := ret[0].(*dto.JobTypeResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdateJobType indicates an expected call of UpdateJobType.
+func (mr *MockJobTypeServiceMockRecorder) UpdateJobType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobType", reflect.TypeOf((*MockJobTypeService)(nil).UpdateJobType), arg0, arg1)
+}
+
+// ValidateJobRequest mocks base method.
+func (m *MockJobTypeService) ValidateJobRequest(arg0 dto.CreateUpdateJobRequest) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateJobRequest", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// ValidateJobRequest indicates an expected call of ValidateJobRequest.
+func (mr *MockJobTypeServiceMockRecorder) ValidateJobRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateJobRequest", reflect.TypeOf((*MockJobTypeService)(nil).ValidateJobRequest), arg0)
+}
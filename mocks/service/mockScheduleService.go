@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/service (interfaces: ScheduleService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/jobsvc/dto"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockScheduleService is a mock of ScheduleService interface.
+type MockScheduleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleServiceMockRecorder
+}
+
+// MockScheduleServiceMockRecorder is the mock recorder for MockScheduleService.
+type MockScheduleServiceMockRecorder struct {
+	mock *MockScheduleService
+}
+
+// NewMockScheduleService creates a new mock instance.
+func NewMockScheduleService(ctrl *gomock.Controller) *MockScheduleService {
+	mock := &MockScheduleService{ctrl: ctrl}
+	mock.recorder = &MockScheduleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleService) EXPECT() *MockScheduleServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSchedule mocks base method.
+func (m *MockScheduleService) CreateSchedule(arg0 dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", arg0)
+	ret0, _ := ret[0].(*dto.ScheduleResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockScheduleServiceMockRecorder) CreateSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockScheduleService)(nil).CreateSchedule), arg0)
+}
+
+// DeleteScheduleById mocks base method.
+func (m *MockScheduleService) DeleteScheduleById(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduleById", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// DeleteScheduleById indicates an expected call of DeleteScheduleById.
+func (mr *MockScheduleServiceMockRecorder) DeleteScheduleById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduleById", reflect.TypeOf((*MockScheduleService)(nil).DeleteScheduleById), arg0)
+}
+
+// GetAllSchedules mocks base method.
+func (m *MockScheduleService) GetAllSchedules() (*[]dto.ScheduleResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSchedules")
+	ret0, _ := ret[0].(*[]dto.ScheduleResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetAllSchedules indicates an expected call of GetAllSchedules.
+func (mr *MockScheduleServiceMockRecorder) GetAllSchedules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSchedules", reflect.TypeOf((*MockScheduleService)(nil).GetAllSchedules))
+}
+
+// GetScheduleById mocks base method.
+func (m *MockScheduleService) GetScheduleById(arg0 string) (*dto.ScheduleResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleById", arg0)
+	ret0, _ := ret[0].(*dto.ScheduleResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetScheduleById indicates an expected call of GetScheduleById.
+func (mr *MockScheduleServiceMockRecorder) GetScheduleById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleById", reflect.TypeOf((*MockScheduleService)(nil).GetScheduleById), arg0)
+}
+
+// RunSchedule mocks base method.
+func (m *MockScheduleService) RunSchedule(arg0 string) api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunSchedule", arg0)
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// RunSchedule indicates an expected call of RunSchedule.
+func (mr *MockScheduleServiceMockRecorder) RunSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSchedule", reflect.TypeOf((*MockScheduleService)(nil).RunSchedule), arg0)
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockScheduleService) UpdateSchedule(arg0 string, arg1 dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", arg0, arg1)
+	ret0, _ := ret[0].(*dto.ScheduleResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockScheduleServiceMockRecorder) UpdateSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockScheduleService)(nil).UpdateSchedule), arg0, arg1)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/johannes-kuhfuss/jobsvc/service (interfaces: WorkflowService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	dto "github.com/johannes-kuhfuss/jobsvc/dto"
+	api_error "github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+// MockWorkflowService is a mock of WorkflowService interface.
+type MockWorkflowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkflowServiceMockRecorder
+}
+
+// MockWorkflowServiceMockRecorder is the mock recorder for MockWorkflowService.
+type MockWorkflowServiceMockRecorder struct {
+	mock *MockWorkflowService
+}
+
+// NewMockWorkflowService creates a new mock instance.
+func NewMockWorkflowService(ctrl *gomock.Controller) *MockWorkflowService {
+	mock := &MockWorkflowService{ctrl: ctrl}
+	mock.recorder = &MockWorkflowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkflowService) EXPECT() *MockWorkflowServiceMockRecorder {
+	return m.recorder
+}
+
+// AdvanceWorkflows mocks base method.
+func (m *MockWorkflowService) AdvanceWorkflows() api_error.ApiErr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceWorkflows")
+	ret0, _ := ret[0].(api_error.ApiErr)
+	return ret0
+}
+
+// AdvanceWorkflows indicates an expected call of AdvanceWorkflows.
+func (mr *MockWorkflowServiceMockRecorder) AdvanceWorkflows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceWorkflows", reflect.TypeOf((*MockWorkflowService)(nil).AdvanceWorkflows))
+}
+
+// CreateWorkflow mocks base method.
+func (m *MockWorkflowService) CreateWorkflow(arg0 dto.CreateWorkflowRequest) (*dto.WorkflowResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkflow", arg0)
+	ret0, _ := ret[0].(*dto.WorkflowResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// CreateWorkflow indicates an expected call of CreateWorkflow.
+func (mr *MockWorkflowServiceMockRecorder) CreateWorkflow(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkflow", reflect.TypeOf((*MockWorkflowService)(nil).CreateWorkflow), arg0)
+}
+
+// GetAllWorkflows mocks base method.
+func (m *MockWorkflowService) GetAllWorkflows() (*[]dto.WorkflowResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWorkflows")
+	ret0, _ := ret[0].(*[]dto.WorkflowResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetAllWorkflows indicates an expected call of GetAllWorkflows.
+func (mr *MockWorkflowServiceMockRecorder) GetAllWorkflows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWorkflows", reflect.TypeOf((*MockWorkflowService)(nil).GetAllWorkflows))
+}
+
+// GetWorkflowById mocks base method.
+func (m *MockWorkflowService) GetWorkflowById(arg0 string) (*dto.WorkflowResponse, api_error.ApiErr) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowById", arg0)
+	ret0, _ := ret[0].(*dto.WorkflowResponse)
+	ret1, _ := ret[1].(api_error.ApiErr)
+	return ret0, ret1
+}
+
+// GetWorkflowById indicates an expected call of GetWorkflowById.
+func (mr *MockWorkflowServiceMockRecorder) GetWorkflowById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowById", reflect.TypeOf((*MockWorkflowService)(nil).GetWorkflowById), arg0)
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
+)
+
+type IdempotencyKeyRepositoryDb struct {
+	cfg *config.AppConfig
+}
+
+var (
+	idempotencyTable string
+)
+
+func NewIdempotencyKeyRepositoryDb(c *config.AppConfig) IdempotencyKeyRepositoryDb {
+	idempotencyTable = c.Db.IdempotencyTable
+	return IdempotencyKeyRepositoryDb{c}
+}
+
+func (ird IdempotencyKeyRepositoryDb) Store(idemKey domain.IdempotencyKey) api_error.ApiErr {
+	conn := ird.cfg.RunTime.DbConn
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (key, request_hash, status_code, response, created_at) VALUES ($1, $2, $3, $4, $5)`, idempotencyTable)
+	_, err := conn.Exec(sqlInsert, idemKey.Key, idemKey.RequestHash, idemKey.StatusCode, idemKey.Response, idemKey.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			msg := fmt.Sprintf("Idempotency key %v already exists", idemKey.Key)
+			logger.Info(msg)
+			return api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error storing idempotency key"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (ird IdempotencyKeyRepositoryDb) FindByKey(key string) (*domain.IdempotencyKey, api_error.ApiErr) {
+	conn := ird.cfg.RunTime.DbConn
+	var idemKey domain.IdempotencyKey
+	findByKeySql := fmt.Sprintf(`SELECT * FROM %v WHERE key = $1`, idempotencyTable)
+	err := conn.Get(&idemKey, findByKeySql, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No idempotency key found for key %v", key)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting idempotency key"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	return &idemKey, nil
+}
+
+func (ird IdempotencyKeyRepositoryDb) Complete(idemKey domain.IdempotencyKey) api_error.ApiErr {
+	conn := ird.cfg.RunTime.DbConn
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (status_code, response) = ($1, $2) WHERE key = $3`, idempotencyTable)
+	_, err := conn.Exec(sqlUpdate, idemKey.StatusCode, idemKey.Response, idemKey.Key)
+	if err != nil {
+		msg := "Database error completing idempotency key"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (ird IdempotencyKeyRepositoryDb) DeleteByKey(key string) api_error.ApiErr {
+	conn := ird.cfg.RunTime.DbConn
+	sqlDelete := fmt.Sprintf(`DELETE FROM %v WHERE key = $1`, idempotencyTable)
+	_, err := conn.Exec(sqlDelete, key)
+	if err != nil {
+		msg := "Database error deleting idempotency key"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (ird IdempotencyKeyRepositoryDb) DeleteExpired(before time.Time) api_error.ApiErr {
+	conn := ird.cfg.RunTime.DbConn
+	sqlDelete := fmt.Sprintf(`DELETE FROM %v WHERE created_at < $1`, idempotencyTable)
+	_, err := conn.Exec(sqlDelete, before)
+	if err != nil {
+		msg := "Database error deleting expired idempotency keys"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var (
+	ird IdempotencyKeyRepositoryDb
+)
+
+func setupIdempotencyKeyTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	cfg.Db.IdempotencyTable = "idempotency_keys"
+	ird = NewIdempotencyKeyRepositoryDb(&cfg)
+	return teardown
+}
+
+func Test_StoreIdempotencyKey_Duplicate_Returns_ConflictError(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	idemKey := domain.IdempotencyKey{Key: "abc"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, idempotencyTable))).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := ird.Store(idemKey)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Idempotency key abc already exists", err.Message())
+}
+
+func Test_StoreIdempotencyKey_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	idemKey := domain.IdempotencyKey{Key: "abc", RequestHash: "123"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, idempotencyTable))).
+		WithArgs("abc", "123", 0, nil, AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := ird.Store(idemKey)
+
+	assert.Nil(t, err)
+}
+
+func Test_FindIdempotencyKey_NoRows_Returns_NotFoundError(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE key = $1`, idempotencyTable))).
+		WithArgs("abc").WillReturnError(sql.ErrNoRows)
+
+	idemKey, err := ird.FindByKey("abc")
+
+	assert.Nil(t, idemKey)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, "No idempotency key found for key abc", err.Message())
+}
+
+func Test_FindIdempotencyKey_NoError_Returns_Key(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"key", "request_hash", "status_code", "response"}).
+		AddRow("abc", "123", 201, `{"id":"2G7VHsD6jkOXq2RmBAbVE1bBqIW"}`)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE key = $1`, idempotencyTable))).
+		WithArgs("abc").WillReturnRows(rows)
+
+	idemKey, err := ird.FindByKey("abc")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "123", idemKey.RequestHash)
+	assert.EqualValues(t, 201, idemKey.StatusCode)
+	assert.True(t, idemKey.IsCompleted())
+}
+
+func Test_CompleteIdempotencyKey_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (status_code, response) = ($1, $2) WHERE key = $3`, idempotencyTable))).
+		WillReturnError(sql.ErrConnDone)
+
+	err := ird.Complete(domain.IdempotencyKey{Key: "abc"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error completing idempotency key", err.Message())
+}
+
+func Test_DeleteExpiredIdempotencyKeys_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupIdempotencyKeyTest(t)
+	defer teardown()
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE created_at < $1`, idempotencyTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := ird.DeleteExpired(date.GetNowUtc())
+
+	assert.Nil(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/johannes-kuhfuss/services_utils/api_error"
 	"github.com/johannes-kuhfuss/services_utils/date"
 	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
 )
 
 type JobRepositoryDb struct {
@@ -20,15 +21,27 @@ type JobRepositoryDb struct {
 }
 
 var (
-	table string
+	table           string
+	deadLetterTable string
+	eventTable      string
 )
 
 func NewJobRepositoryDb(c *config.AppConfig) JobRepositoryDb {
 	table = c.Db.JobTable
+	deadLetterTable = c.Db.DeadLetterTable
+	eventTable = c.Db.EventTable
 	return JobRepositoryDb{c}
 }
 
 func (jrd JobRepositoryDb) FindAll(safReq dto.SortAndFilterRequest) (*[]domain.Job, int, api_error.ApiErr) {
+	return jrd.findAllIn(table, safReq)
+}
+
+func (jrd JobRepositoryDb) FindAllDeadLetters(safReq dto.SortAndFilterRequest) (*[]domain.Job, int, api_error.ApiErr) {
+	return jrd.findAllIn(deadLetterTable, safReq)
+}
+
+func (jrd JobRepositoryDb) findAllIn(tbl string, safReq dto.SortAndFilterRequest) (*[]domain.Job, int, api_error.ApiErr) {
 	conn := jrd.cfg.RunTime.DbConn
 	jobs := make([]domain.Job, 0)
 	var (
@@ -40,14 +53,14 @@ func (jrd JobRepositoryDb) FindAll(safReq dto.SortAndFilterRequest) (*[]domain.J
 	where := constructWhereClause(safReq)
 	orderBy := fmt.Sprintf("%v %v", safReq.Sorts.Field, safReq.Sorts.Dir)
 	if where == "" {
-		findAllSql = fmt.Sprintf(`SELECT * FROM %v ORDER BY %v LIMIT $1 OFFSET $2`, table, orderBy)
+		findAllSql = fmt.Sprintf(`SELECT * FROM %v ORDER BY %v LIMIT $1 OFFSET $2`, tbl, orderBy)
 		err = conn.Select(&jobs, findAllSql, safReq.Limit, safReq.Offset)
-		countSql = fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v')`, table)
+		countSql = fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v')`, tbl)
 	} else {
-		findAllSql = fmt.Sprintf(`SELECT * FROM %v WHERE %v ORDER BY %v LIMIT $1 OFFSET $2`, table, where, orderBy)
+		findAllSql = fmt.Sprintf(`SELECT * FROM %v WHERE %v ORDER BY %v LIMIT $1 OFFSET $2`, tbl, where, orderBy)
 		err = conn.Select(&jobs, findAllSql, safReq.Limit, safReq.Offset)
 		countWhere := strings.ReplaceAll(where, "'", "$$")
-		countSql = fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v WHERE %v')`, table, countWhere)
+		countSql = fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v WHERE %v')`, tbl, countWhere)
 	}
 	if err != nil {
 		msg := "Database error getting all jobs"
@@ -59,6 +72,12 @@ func (jrd JobRepositoryDb) FindAll(safReq dto.SortAndFilterRequest) (*[]domain.J
 		logger.Info(msg)
 		return nil, 0, api_error.NewNotFoundError(msg)
 	}
+	err = loadHistory(conn, jobRefs(jobs)...)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, 0, api_error.NewInternalServerError(msg, nil)
+	}
 	row := conn.QueryRow(countSql)
 	err = row.Scan(&totalCount)
 	if err != nil {
@@ -85,53 +104,190 @@ func (jrd JobRepositoryDb) FindById(id string) (*domain.Job, api_error.ApiErr) {
 			return nil, api_error.NewInternalServerError(msg, nil)
 		}
 	}
+	err = loadHistory(conn, &job)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &job, nil
+}
+
+func (jrd JobRepositoryDb) FindActiveByUniqueKey(uniqueKey string) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	findByKeySql := fmt.Sprintf(`SELECT * FROM %v WHERE unique_key = $1 AND status NOT IN ($2, $3, $4) LIMIT 1`, table)
+	err := conn.Get(&job, findByKeySql, uniqueKey, domain.StatusFinished, domain.StatusFailed, domain.StatusCancelled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No active job found for unique key %v", uniqueKey)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting active job by unique key"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	err = loadHistory(conn, &job)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &job, nil
+}
+
+func (jrd JobRepositoryDb) FindDeadLetterById(id string) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	findByIdSql := fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, deadLetterTable)
+	err := conn.Get(&job, findByIdSql, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No dead-lettered job found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting dead-lettered job by id"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	err = loadHistory(conn, &job)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
 	return &job, nil
 }
 
+func (jrd JobRepositoryDb) FindDependencies(ids []string) (*[]domain.JobDependency, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	deps := make([]domain.JobDependency, 0)
+	findDepsSql := fmt.Sprintf(`SELECT id, status FROM %v WHERE id = ANY($1) UNION ALL SELECT id, status FROM %v WHERE id = ANY($1)`, table, deadLetterTable)
+	err := conn.Select(&deps, findDepsSql, pq.Array(ids))
+	if err != nil {
+		msg := "Database error getting job dependencies"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &deps, nil
+}
+
+func (jrd JobRepositoryDb) FindChildren(parentId string) (*[]domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	children := make([]domain.Job, 0)
+	findChildrenSql := fmt.Sprintf(`SELECT * FROM %v WHERE parent_id = $1 UNION ALL SELECT * FROM %v WHERE parent_id = $1 ORDER BY id`, table, deadLetterTable)
+	err := conn.Select(&children, findChildrenSql, parentId)
+	if err != nil {
+		msg := "Database error getting child jobs"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	err = loadHistory(conn, jobRefs(children)...)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &children, nil
+}
+
+func (jrd JobRepositoryDb) AddChildren(parentId string, children []domain.Job) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var parent domain.Job
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error adding child jobs"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Get(&parent, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table), parentId)
+	if sqlErr != nil {
+		tx.Rollback()
+		if sqlErr == sql.ErrNoRows {
+			msg := fmt.Sprintf("No job found for id %v", parentId)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		}
+		msg := "Database error adding child jobs (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	if parent.IsDone() {
+		tx.Rollback()
+		msg := fmt.Sprintf("Job with id %v is already %v and cannot get child jobs", parentId, parent.Status)
+		logger.Info(msg)
+		return nil, api_error.NewProcessingConflictError(msg)
+	}
+	sqlErr = loadHistory(tx, &parent)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error adding child jobs (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	for _, child := range children {
+		sqlErr = insertJob(tx, child)
+		if sqlErr != nil {
+			tx.Rollback()
+			if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && child.UniqueKey != "" {
+				msg := fmt.Sprintf("Active job with unique key %v already exists", child.UniqueKey)
+				logger.Info(msg)
+				return nil, api_error.NewProcessingConflictError(msg)
+			}
+			msg := "Database error adding child jobs (insert)"
+			logger.Error(msg, sqlErr)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	now := date.GetNowUtc()
+	parent.ModifiedAt = now
+	parent.ChangeStatus(domain.StatusRunning, parent.Actor(), fmt.Sprintf("Added %d child jobs. Waiting for child jobs to finish", len(children)))
+	parent.LeaseOwner = ""
+	parent.LeaseExpiresAt = nil
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) = 
+		($1, $2, $3, $4) WHERE id = $5`, table)
+	_, sqlErr = tx.Exec(sqlUpdate, now, string(parent.Status), "", nil, parentId)
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &parent)
+	}
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error adding child jobs (update)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = aggregateParents(tx, parentId, now)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error updating parent job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error adding child jobs"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &parent, nil
+}
+
 func (jrd JobRepositoryDb) Store(job domain.Job) api_error.ApiErr {
 	conn := jrd.cfg.RunTime.DbConn
-	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
-		id, 
-		correlation_id, 
-		name, 
-		created_at, 
-		created_by, 
-		modified_at, 
-		modified_by, 
-		status, 
-		source, 
-		destination, 
-		type, 
-		sub_type, 
-		action, 
-		action_details, 
-		progress, 
-		history, 
-		extra_data, 
-		priority, 
-		rank) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`, table)
-	_, err := conn.Exec(sqlInsert,
-		job.Id.String(),
-		job.CorrelationId,
-		job.Name,
-		job.CreatedAt,
-		job.CreatedBy,
-		job.ModifiedAt,
-		job.ModifiedBy,
-		job.Status,
-		job.Source,
-		job.Destination,
-		job.Type,
-		job.SubType,
-		job.Action,
-		job.ActionDetails,
-		job.Progress,
-		job.History,
-		job.ExtraData,
-		job.Priority,
-		job.Rank)
+	err := insertJob(conn, job)
 	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && job.UniqueKey != "" {
+			msg := fmt.Sprintf("Active job with unique key %v already exists", job.UniqueKey)
+			logger.Info(msg)
+			return api_error.NewProcessingConflictError(msg)
+		}
 		msg := "Database error storing new job"
 		logger.Error(msg, err)
 		return api_error.NewInternalServerError(msg, nil)
@@ -139,6 +295,17 @@ func (jrd JobRepositoryDb) Store(job domain.Job) api_error.ApiErr {
 	return nil
 }
 
+func (jrd JobRepositoryDb) NotifyJobCreated(jobType string) api_error.ApiErr {
+	conn := jrd.cfg.RunTime.DbConn
+	_, err := conn.Exec(`SELECT pg_notify($1, $2)`, jrd.cfg.Db.NotifyChannel, jobType)
+	if err != nil {
+		msg := "Database error notifying about new job"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
 func (jrd JobRepositoryDb) DeleteById(id string) api_error.ApiErr {
 	conn := jrd.cfg.RunTime.DbConn
 	deleteByIdSql := fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table)
@@ -151,9 +318,9 @@ func (jrd JobRepositoryDb) DeleteById(id string) api_error.ApiErr {
 	return nil
 }
 
-func (jrd JobRepositoryDb) Dequeue(jobType string) (*domain.Job, api_error.ApiErr) {
+func (jrd JobRepositoryDb) Dequeue(dqReq dto.DequeueRequest) (*[]domain.Job, api_error.ApiErr) {
 	conn := jrd.cfg.RunTime.DbConn
-	var nextJob domain.Job
+	nextJobs := make([]domain.Job, 0)
 	var sqlErr error
 	var tx *sqlx.Tx
 
@@ -163,46 +330,151 @@ func (jrd JobRepositoryDb) Dequeue(jobType string) (*domain.Job, api_error.ApiEr
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
 	}
-	sqlErr = tx.Get(&nextJob,
-		fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table),
-		string(domain.StatusCreated), jobType)
+	where, args := dequeueWhereClause(dqReq)
+	args = append(args, dequeueCount(dqReq))
+	sqlErr = tx.Select(&nextJobs,
+		fmt.Sprintf(`SELECT * FROM %v WHERE %v ORDER BY %v LIMIT $%d FOR UPDATE SKIP LOCKED`, table, where, jrd.dequeueOrderBy(), len(args)),
+		args...)
 	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error dequeuing next job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	if len(nextJobs) == 0 {
+		tx.Rollback()
+		msg := fmt.Sprintf("No job found to dequeue for type %v", strings.Join(dqReq.AllTypes(), ", "))
+		logger.Info(msg)
+		return nil, api_error.NewNotFoundError(msg)
+	}
+	sqlErr = loadHistory(tx, jobRefs(nextJobs)...)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error dequeuing next job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	now := date.GetNowUtc()
+	leaseOwner, leaseExpiry := jrd.newLease(dqReq.Worker, dqReq.LeaseSeconds, now)
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at, attempts) = 
+		($1, $2, $3, $4, $5, $6) WHERE id = $7`, table)
+	for idx := range nextJobs {
+		nextJob := &nextJobs[idx]
+		nextJob.Attempts++
+		nextJob.ChangeStatus(domain.StatusRunning, leaseOwner, fmt.Sprintf("Dequeuing job for processing (attempt %d of %d). Leased to %v until %v", nextJob.Attempts, nextJob.MaxAttempts, leaseOwner, leaseExpiry.Format(time.RFC3339)))
+		_, sqlErr = tx.Exec(sqlUpdate, now, string(nextJob.Status), 1, leaseOwner, leaseExpiry, nextJob.Attempts, nextJob.Id.String())
+		if sqlErr == nil {
+			sqlErr = insertEvents(tx, nextJob)
+		}
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error dequeuing next job (update)"
+			logger.Error(msg, sqlErr)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+		nextJob.ModifiedAt = now
+		nextJob.Progress = 1
+		nextJob.LeaseOwner = leaseOwner
+		nextJob.LeaseExpiresAt = &leaseExpiry
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error dequeuing job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &nextJobs, nil
+}
+
+func (jrd JobRepositoryDb) Heartbeat(id string, hbReq dto.HeartbeatRequest) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error processing heartbeat"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Get(&job, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table), id)
+	if sqlErr != nil {
+		tx.Rollback()
 		if sqlErr == sql.ErrNoRows {
-			msg := fmt.Sprintf("No job found to dequeue for type %v", jobType)
+			msg := fmt.Sprintf("No job found for id %v", id)
 			logger.Info(msg)
 			return nil, api_error.NewNotFoundError(msg)
 		} else {
-			msg := "Database error dequeuing next job (select)"
+			msg := "Database error processing heartbeat (select)"
 			logger.Error(msg, sqlErr)
-			return nil, api_error.NewInternalServerError("Database error dequeuing next job (select)", nil)
+			return nil, api_error.NewInternalServerError(msg, nil)
 		}
 	}
-	nextJob.AddHistory("Dequeuing job for processing")
+	if job.Status != domain.StatusRunning || job.LeaseOwner != hbReq.Worker {
+		tx.Rollback()
+		msg := fmt.Sprintf("Job with id %v is not leased by worker %v", id, hbReq.Worker)
+		logger.Info(msg)
+		return nil, api_error.NewProcessingConflictError(msg)
+	}
+	sqlErr = loadHistory(tx, &job)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error processing heartbeat (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
 	now := date.GetNowUtc()
-	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, history, progress) = 
+	_, leaseExpiry := jrd.newLease(hbReq.Worker, hbReq.LeaseSeconds, now)
+	if hbReq.Progress != nil {
+		historyInterval := time.Duration(jrd.cfg.Progress.HistoryIntervalSeconds) * time.Second
+		if err := job.ReportProgress(dto.UpdateJobProgressRequest{Progress: hbReq.Progress}, historyInterval); err != nil {
+			tx.Rollback()
+			logger.Info(err.Message())
+			return nil, err
+		}
+	}
+	if hbReq.Message != "" {
+		job.AddEvent(domain.EventNote, hbReq.Worker, hbReq.Message)
+	}
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, progress, progress_logged_at, lease_expires_at) = 
 		($1, $2, $3, $4) WHERE id = $5`, table)
-	_, sqlErr = tx.Exec(sqlUpdate, now, "running", nextJob.History, 1, nextJob.Id.String())
+	_, sqlErr = tx.Exec(sqlUpdate, now, job.Progress, job.ProgressLoggedAt, leaseExpiry, id)
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &job)
+	}
 	if sqlErr != nil {
-		msg := "Database error dequeuing next job (update)"
+		tx.Rollback()
+		msg := "Database error processing heartbeat (update)"
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
 	}
+	if job.ParentId != "" {
+		sqlErr = aggregateParents(tx, job.ParentId, now)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error updating parent job"
+			logger.Error(msg, sqlErr)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
 	sqlErr = tx.Commit()
 	if sqlErr != nil {
-		msg := "Database transaction end error dequeuing job"
+		msg := "Database transaction end error processing heartbeat"
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
 	}
-	nextJob.ModifiedAt = now
-	nextJob.Status = "running"
-	return &nextJob, nil
+	job.ModifiedAt = now
+	job.LeaseExpiresAt = &leaseExpiry
+	return &job, nil
 }
 
-func (jrd JobRepositoryDb) SetStatusById(id string, newStatus string, message string) api_error.ApiErr {
+func (jrd JobRepositoryDb) SetStatusById(id string, statusReq dto.UpdateJobStatusRequest, message string) api_error.ApiErr {
 	conn := jrd.cfg.RunTime.DbConn
 	var oldJob domain.Job
 	var sqlErr error
 	var tx *sqlx.Tx
+	newStatus := statusReq.Status
 
 	tx, sqlErr = conn.Beginx()
 	if sqlErr != nil {
@@ -210,22 +482,101 @@ func (jrd JobRepositoryDb) SetStatusById(id string, newStatus string, message st
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	sqlErr = tx.Get(&oldJob, fmt.Sprintf(`SELECT status, history FROM %v WHERE id = $1`, table), id)
+	sqlErr = tx.Get(&oldJob, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table), id)
 	if sqlErr != nil {
+		tx.Rollback()
 		msg := "Database error setting job status with id (select)"
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	oldJob.AddHistory(message)
-	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, history) =
-	 	($1, $2, $3) WHERE id = $4`, table)
+	if oldJob.CancelRequested && newStatus != string(domain.StatusCancelled) && newStatus != string(domain.StatusFinished) && newStatus != string(domain.StatusFailed) {
+		tx.Rollback()
+		msg := fmt.Sprintf("Cancellation of job with id %v was requested. Confirm by setting status to cancelled", id)
+		logger.Info(msg)
+		return api_error.NewProcessingConflictError(msg)
+	}
+	if !domain.IsValidTransition(oldJob.Status, domain.JobStatus(newStatus)) {
+		tx.Rollback()
+		msg := fmt.Sprintf("Job with id %v cannot change status from %v to %v", id, oldJob.Status, newStatus)
+		logger.Info(msg)
+		return api_error.NewProcessingConflictError(msg)
+	}
 	now := date.GetNowUtc()
-	_, sqlErr = tx.Exec(sqlUpdate, now, newStatus, oldJob.History, id)
+	deadLetter := false
+	oldJob.SetOutcome(statusReq)
+	if newStatus == string(domain.StatusFailed) && oldJob.CanRetry() && !oldJob.CancelRequested && statusReq.IsRetryable() {
+		notBefore := now.Add(oldJob.RetryDelay())
+		oldJob.ChangeStatus(domain.StatusCreated, oldJob.Actor(), fmt.Sprintf("Attempt %d of %d failed. %v. Retrying after %v", oldJob.Attempts, oldJob.MaxAttempts, message, notBefore.Format(time.RFC3339)))
+		sqlRetry := fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at, not_before, error_code, error_message, error_retryable) =
+			($1, $2, $3, $4, $5, $6, $7, $8) WHERE id = $9`, table)
+		_, sqlErr = tx.Exec(sqlRetry, now, string(oldJob.Status), "", nil, notBefore, oldJob.ErrorCode, oldJob.ErrorMessage, oldJob.ErrorRetryable, id)
+	} else {
+		if newStatus == string(domain.StatusFailed) && !statusReq.IsRetryable() {
+			message = fmt.Sprintf("%v. Error %v is not retryable", message, oldJob.ErrorCode)
+		} else if newStatus == string(domain.StatusFailed) && oldJob.Attempts > 0 {
+			message = fmt.Sprintf("Attempt %d of %d failed. %v. No attempts left", oldJob.Attempts, oldJob.MaxAttempts, message)
+		} else if newStatus == string(domain.StatusRunning) && oldJob.Status != domain.StatusRunning {
+			oldJob.Attempts++
+			message = fmt.Sprintf("%v. Attempt %d of %d", message, oldJob.Attempts, oldJob.MaxAttempts)
+		}
+		oldJob.ChangeStatus(domain.JobStatus(newStatus), oldJob.Actor(), message)
+		if newStatus == string(domain.StatusFailed) {
+			deadLetter = true
+			oldJob.AddHistory("Moving job to dead-letter queue")
+		}
+		var leaseOwner string
+		var leaseExpiry interface{}
+		if newStatus == string(domain.StatusRunning) {
+			owner, expiry := jrd.newLease(oldJob.LeaseOwner, 0, now)
+			leaseOwner, leaseExpiry = owner, expiry
+		}
+		sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) =
+	 	($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table)
+		_, sqlErr = tx.Exec(sqlUpdate, now, newStatus, oldJob.Attempts, leaseOwner, leaseExpiry, oldJob.Result, oldJob.ErrorCode, oldJob.ErrorMessage, oldJob.ErrorRetryable, id)
+	}
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &oldJob)
+	}
 	if sqlErr != nil {
+		tx.Rollback()
 		msg := "Database error setting job status with id (update)"
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
+	if deadLetter {
+		sqlErr = moveJob(tx, id, table, deadLetterTable)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error moving job to dead-letter queue"
+			logger.Error(msg, sqlErr)
+			return api_error.NewInternalServerError(msg, nil)
+		}
+		sqlErr = failDependents(tx, id, now)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error failing dependent jobs"
+			logger.Error(msg, sqlErr)
+			return api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	if newStatus == string(domain.StatusCancelled) {
+		sqlErr = failDependents(tx, id, now)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error failing dependent jobs"
+			logger.Error(msg, sqlErr)
+			return api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	if oldJob.ParentId != "" {
+		sqlErr = aggregateParents(tx, oldJob.ParentId, now)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error updating parent job"
+			logger.Error(msg, sqlErr)
+			return api_error.NewInternalServerError(msg, nil)
+		}
+	}
 	sqlErr = tx.Commit()
 	if sqlErr != nil {
 		msg := "Database transaction end error setting job status by id"
@@ -235,6 +586,140 @@ func (jrd JobRepositoryDb) SetStatusById(id string, newStatus string, message st
 	return nil
 }
 
+func (jrd JobRepositoryDb) Cancel(id string) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error cancelling job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Get(&job, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table), id)
+	if sqlErr != nil {
+		tx.Rollback()
+		if sqlErr == sql.ErrNoRows {
+			msg := fmt.Sprintf("No job found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		}
+		msg := "Database error cancelling job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	if job.IsDone() {
+		tx.Rollback()
+		msg := fmt.Sprintf("Job with id %v is already %v and cannot be cancelled", id, job.Status)
+		logger.Info(msg)
+		return nil, api_error.NewProcessingConflictError(msg)
+	}
+	sqlErr = loadHistory(tx, &job)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error cancelling job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	now := date.GetNowUtc()
+	job.ModifiedAt = now
+	if job.IsLeased() {
+		if !job.CancelRequested {
+			job.CancelRequested = true
+			job.AddEvent(domain.EventNote, domain.ActorApi, fmt.Sprintf("Cancellation requested. Waiting for worker %v to confirm", job.LeaseOwner))
+			sqlRequest := fmt.Sprintf(`UPDATE %v SET (modified_at, cancel_requested) = ($1, $2) WHERE id = $3`, table)
+			_, sqlErr = tx.Exec(sqlRequest, now, true, id)
+			if sqlErr == nil {
+				sqlErr = insertEvents(tx, &job)
+			}
+		}
+	} else {
+		sqlErr = cancelJob(tx, &job, domain.ActorApi, now)
+	}
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error cancelling job (update)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error cancelling job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &job, nil
+}
+
+func (jrd JobRepositoryDb) SetProgressById(id string, progReq dto.UpdateJobProgressRequest) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error setting job progress by id"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Get(&job, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table), id)
+	if sqlErr != nil {
+		tx.Rollback()
+		if sqlErr == sql.ErrNoRows {
+			msg := fmt.Sprintf("No job found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		}
+		msg := "Database error setting job progress by id (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = loadHistory(tx, &job)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error setting job progress by id (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	historyInterval := time.Duration(jrd.cfg.Progress.HistoryIntervalSeconds) * time.Second
+	if err := job.ReportProgress(progReq, historyInterval); err != nil {
+		tx.Rollback()
+		logger.Info(err.Message())
+		return nil, err
+	}
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, progress, eta, progress_message, progress_logged_at) = 
+		($1, $2, $3, $4, $5) WHERE id = $6`, table)
+	_, sqlErr = tx.Exec(sqlUpdate, job.ModifiedAt, job.Progress, job.Eta, job.ProgressMessage, job.ProgressLoggedAt, id)
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &job)
+	}
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error setting job progress by id (update)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	if job.ParentId != "" {
+		sqlErr = aggregateParents(tx, job.ParentId, job.ModifiedAt)
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error updating parent job"
+			logger.Error(msg, sqlErr)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error setting job progress by id"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &job, nil
+}
+
 func (jrd JobRepositoryDb) Update(id string, jobReq dto.CreateUpdateJobRequest) (*domain.Job, api_error.ApiErr) {
 	conn := jrd.cfg.RunTime.DbConn
 	var oldJob domain.Job
@@ -247,8 +732,15 @@ func (jrd JobRepositoryDb) Update(id string, jobReq dto.CreateUpdateJobRequest)
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
 	}
-	sqlErr = tx.Get(&oldJob, fmt.Sprintf("SELECT * FROM %v WHERE id = $1", table), id)
+	sqlErr = tx.Get(&oldJob, fmt.Sprintf("SELECT * FROM %v WHERE id = $1 FOR UPDATE", table), id)
+	if sqlErr != nil {
+		msg := "Database error updating job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = loadHistory(tx, &oldJob)
 	if sqlErr != nil {
+		tx.Rollback()
 		msg := "Database error updating job (select)"
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
@@ -265,11 +757,18 @@ func (jrd JobRepositoryDb) Update(id string, jobReq dto.CreateUpdateJobRequest)
 			sub_type, 
 			action, 
 			action_details, 
-			history, 
 			extra_data, 
 			priority, 
-			rank) = 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) WHERE id = $15`, table)
+			rank, 
+			max_attempts, 
+			backoff_policy, 
+			backoff_seconds, 
+			status, 
+			run_at, 
+			dependency_policy, 
+			labels, 
+			unique_key) = 
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) WHERE id = $22`, table)
 	_, sqlErr = tx.Exec(sqlUpdate,
 		updJob.CorrelationId,
 		updJob.Name,
@@ -281,12 +780,28 @@ func (jrd JobRepositoryDb) Update(id string, jobReq dto.CreateUpdateJobRequest)
 		updJob.SubType,
 		updJob.Action,
 		updJob.ActionDetails,
-		updJob.History,
 		updJob.ExtraData,
 		updJob.Priority,
 		updJob.Rank,
+		updJob.MaxAttempts,
+		updJob.BackoffPolicy,
+		updJob.BackoffSeconds,
+		updJob.Status,
+		updJob.RunAt,
+		updJob.DependencyPolicy,
+		updJob.Labels,
+		updJob.UniqueKey,
 		updJob.Id.String())
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, updJob)
+	}
 	if sqlErr != nil {
+		tx.Rollback()
+		if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && updJob.UniqueKey != "" {
+			msg := fmt.Sprintf("Active job with unique key %v already exists", updJob.UniqueKey)
+			logger.Info(msg)
+			return nil, api_error.NewProcessingConflictError(msg)
+		}
 		msg := "Database error updating job (update)"
 		logger.Error(msg, sqlErr)
 		return nil, api_error.NewInternalServerError(msg, nil)
@@ -312,16 +827,19 @@ func (jrd JobRepositoryDb) SetHistoryById(id string, message string) api_error.A
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	sqlErr = tx.Get(&oldJob, fmt.Sprintf(`SELECT history FROM %v WHERE id = $1`, table), id)
+	sqlErr = tx.Get(&oldJob, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table), id)
 	if sqlErr != nil {
 		msg := "Database error setting job history by id (select)"
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	oldJob.AddHistory(message)
-	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, history) = ($1, $2) WHERE id = $3`, table)
+	oldJob.AddEvent(domain.EventNote, domain.ActorApi, message)
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET modified_at = $1 WHERE id = $2`, table)
 	now := date.GetNowUtc()
-	_, sqlErr = tx.Exec(sqlUpdate, now, oldJob.History, id)
+	_, sqlErr = tx.Exec(sqlUpdate, now, id)
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &oldJob)
+	}
 	if sqlErr != nil {
 		msg := "Database error setting job history by id (update)"
 		logger.Error(msg, sqlErr)
@@ -337,6 +855,32 @@ func (jrd JobRepositoryDb) SetHistoryById(id string, message string) api_error.A
 	return nil
 }
 
+func (jrd JobRepositoryDb) FindEvents(id string, limit int, offset int) (*[]domain.JobEvent, int, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	events := make([]domain.JobEvent, 0)
+	var totalCount int
+	countSql := fmt.Sprintf(`SELECT count(*) FROM %v WHERE job_id = $1`, eventTable)
+	err := conn.Get(&totalCount, countSql, id)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, 0, api_error.NewInternalServerError(msg, nil)
+	}
+	if totalCount == 0 {
+		msg := fmt.Sprintf("No history found for job id %v", id)
+		logger.Info(msg)
+		return nil, 0, api_error.NewNotFoundError(msg)
+	}
+	findEventsSql := fmt.Sprintf(`SELECT * FROM %v WHERE job_id = $1 ORDER BY id LIMIT $2 OFFSET $3`, eventTable)
+	err = conn.Select(&events, findEventsSql, id, limit, offset)
+	if err != nil {
+		msg := "Database error getting job history"
+		logger.Error(msg, err)
+		return nil, 0, api_error.NewInternalServerError(msg, nil)
+	}
+	return &events, totalCount, nil
+}
+
 func (jrd JobRepositoryDb) DeleteAllJobs() api_error.ApiErr {
 	conn := jrd.cfg.RunTime.DbConn
 	sqlDeleteAll := fmt.Sprintf(`DELETE FROM %v`, table)
@@ -353,27 +897,50 @@ func (jrd JobRepositoryDb) CleanupJobs() api_error.ApiErr {
 	var inProgressRows int
 	conn := jrd.cfg.RunTime.DbConn
 
-	sqlDeleteFailed := fmt.Sprintf(`DELETE FROM %v WHERE status = 'failed' AND modified_at < $1`, table)
-	searchTime := time.Now().UTC().Add(-time.Hour * 24 * time.Duration(jrd.cfg.Cleanup.FailedRetentionDays))
-	sqlRes, sqlErr := conn.Exec(sqlDeleteFailed, searchTime)
+	sqlDeleteSucceeded := fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 
+		AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, jrd.cfg.Db.WorkflowTable)
+	searchTime := time.Now().UTC().Add(-time.Hour * 24 * time.Duration(jrd.cfg.Cleanup.SuccessRetentionDays))
+	sqlRes, sqlErr := conn.Exec(sqlDeleteSucceeded, searchTime)
 	if sqlErr != nil {
-		msg := "Database error deleting expired failed jobs"
+		msg := "Database error deleting expired succeeded jobs"
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	failedRows, _ := sqlRes.RowsAffected()
-	logger.Info(fmt.Sprintf("Deleted %d expired failed jobs", failedRows))
+	successRows, _ := sqlRes.RowsAffected()
+	logger.Info(fmt.Sprintf("Deleted %d expired succeeded jobs", successRows))
 
-	sqlDeleteSucceeded := fmt.Sprintf(`DELETE FROM %v WHERE status = 'finished' AND modified_at < $1`, table)
-	searchTime = time.Now().UTC().Add(-time.Hour * 24 * time.Duration(jrd.cfg.Cleanup.SuccessRetentionDays))
-	sqlRes, sqlErr = conn.Exec(sqlDeleteSucceeded, searchTime)
+	sqlDeleteDeadLetters := fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND %v`, deadLetterTable, noPendingDependents())
+	searchTime = time.Now().UTC().Add(-time.Hour * 24 * time.Duration(jrd.cfg.Cleanup.DeadLetterRetentionDays))
+	sqlRes, sqlErr = conn.Exec(sqlDeleteDeadLetters, searchTime)
 	if sqlErr != nil {
-		msg := "Database error deleting expired succeeded jobs"
+		msg := "Database error deleting expired dead-lettered jobs"
 		logger.Error(msg, sqlErr)
 		return api_error.NewInternalServerError(msg, nil)
 	}
-	successRows, _ := sqlRes.RowsAffected()
-	logger.Info(fmt.Sprintf("Deleted %d expired succeeded jobs", successRows))
+	deadLetterRows, _ := sqlRes.RowsAffected()
+	logger.Info(fmt.Sprintf("Deleted %d expired dead-lettered jobs", deadLetterRows))
+
+	sqlDeleteCancelled := fmt.Sprintf(`DELETE FROM %v prereq WHERE status = 'cancelled' AND modified_at < $1 AND %v`, table, noPendingDependents())
+	searchTime = time.Now().UTC().Add(-time.Hour * 24 * time.Duration(jrd.cfg.Cleanup.CancelledRetentionDays))
+	sqlRes, sqlErr = conn.Exec(sqlDeleteCancelled, searchTime)
+	if sqlErr != nil {
+		msg := "Database error deleting expired cancelled jobs"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	cancelledRows, _ := sqlRes.RowsAffected()
+	logger.Info(fmt.Sprintf("Deleted %d expired cancelled jobs", cancelledRows))
+
+	sqlDeleteEvents := fmt.Sprintf(`DELETE FROM %v ev WHERE NOT EXISTS (SELECT 1 FROM %v job WHERE job.id = ev.job_id) 
+		AND NOT EXISTS (SELECT 1 FROM %v dl WHERE dl.id = ev.job_id)`, eventTable, table, deadLetterTable)
+	sqlRes, sqlErr = conn.Exec(sqlDeleteEvents)
+	if sqlErr != nil {
+		msg := "Database error deleting orphaned job events"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	eventRows, _ := sqlRes.RowsAffected()
+	logger.Info(fmt.Sprintf("Deleted %d orphaned job events", eventRows))
 
 	sqlCountRunning := fmt.Sprintf(`SELECT count(*) FROM %v WHERE status = 'running' AND modified_at < $1`, table)
 	searchTime = time.Now().UTC().Add(-time.Hour * time.Duration(jrd.cfg.Cleanup.InProgressWarningHours))
@@ -393,3 +960,136 @@ func (jrd JobRepositoryDb) CleanupJobs() api_error.ApiErr {
 
 	return nil
 }
+
+func (jrd JobRepositoryDb) RequeueDeadLetter(id string) (*domain.Job, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	var job domain.Job
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error requeuing dead-lettered job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Get(&job, fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, deadLetterTable), id)
+	if sqlErr != nil {
+		tx.Rollback()
+		if sqlErr == sql.ErrNoRows {
+			msg := fmt.Sprintf("No dead-lettered job found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		}
+		msg := "Database error requeuing dead-lettered job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = loadHistory(tx, &job)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error requeuing dead-lettered job (select)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	now := date.GetNowUtc()
+	job.ModifiedAt = now
+	job.ChangeStatus(domain.StatusCreated, domain.ActorApi, "Requeued job from dead-letter queue")
+	job.Progress = 0
+	job.Attempts = 0
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+	job.NotBefore = nil
+	job.CancelRequested = false
+	job.ClearOutcome()
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, attempts, lease_owner, lease_expires_at, not_before, cancel_requested, 
+		result, error_code, error_message, error_retryable) = 
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) WHERE id = $13`, deadLetterTable)
+	_, sqlErr = tx.Exec(sqlUpdate, now, job.Status, 0, 0, "", nil, nil, false, nil, "", "", false, id)
+	if sqlErr == nil {
+		sqlErr = insertEvents(tx, &job)
+	}
+	if sqlErr != nil {
+		tx.Rollback()
+		if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && job.UniqueKey != "" {
+			msg := fmt.Sprintf("Cannot requeue job %v. Active job with unique key %v already exists", id, job.UniqueKey)
+			logger.Info(msg)
+			return nil, api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error requeuing dead-lettered job (update)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = moveJob(tx, id, deadLetterTable, table)
+	if sqlErr != nil {
+		tx.Rollback()
+		if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && job.UniqueKey != "" {
+			msg := fmt.Sprintf("Cannot requeue job %v. Active job with unique key %v already exists", id, job.UniqueKey)
+			logger.Info(msg)
+			return nil, api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error requeuing dead-lettered job (move)"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error requeuing dead-lettered job"
+		logger.Error(msg, sqlErr)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &job, nil
+}
+
+func (jrd JobRepositoryDb) ReleaseExpiredLeases() (int, api_error.ApiErr) {
+	conn := jrd.cfg.RunTime.DbConn
+	expiredJobs := make([]domain.Job, 0)
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error releasing expired leases"
+		logger.Error(msg, sqlErr)
+		return 0, api_error.NewInternalServerError(msg, nil)
+	}
+	now := date.GetNowUtc()
+	sqlErr = tx.Select(&expiredJobs,
+		fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND lease_expires_at < $2 FOR UPDATE SKIP LOCKED`, table),
+		string(domain.StatusRunning), now)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error releasing expired leases (select)"
+		logger.Error(msg, sqlErr)
+		return 0, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at) = 
+		($1, $2, $3, $4, $5) WHERE id = $6`, table)
+	for _, expiredJob := range expiredJobs {
+		if expiredJob.CancelRequested {
+			expiredJob.AddHistory(fmt.Sprintf("Lease of worker %v expired while cancellation was pending", expiredJob.LeaseOwner))
+			sqlErr = cancelJob(tx, &expiredJob, domain.ActorSystem, now)
+		} else if expiredJob.CanRetry() {
+			expiredJob.ChangeStatus(domain.StatusCreated, domain.ActorSystem, fmt.Sprintf("Lease of worker %v expired. Returning job to queue", expiredJob.LeaseOwner))
+			_, sqlErr = tx.Exec(sqlUpdate, now, string(expiredJob.Status), 0, "", nil, expiredJob.Id.String())
+			if sqlErr == nil {
+				sqlErr = insertEvents(tx, &expiredJob)
+			}
+		} else {
+			sqlErr = failExpiredJob(tx, &expiredJob, now)
+		}
+		if sqlErr != nil {
+			tx.Rollback()
+			msg := "Database error releasing expired leases (update)"
+			logger.Error(msg, sqlErr)
+			return 0, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error releasing expired leases"
+		logger.Error(msg, sqlErr)
+		return 0, api_error.NewInternalServerError(msg, nil)
+	}
+	return len(expiredJobs), nil
+}
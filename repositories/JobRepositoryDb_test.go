@@ -3,6 +3,7 @@ package repositories
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -15,6 +16,7 @@ import (
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/services_utils/date"
 	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
 	"github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
@@ -29,6 +31,7 @@ var (
 type (
 	AnyTime   struct{}
 	AnyString struct{}
+	AnyArray  struct{}
 )
 
 func (at AnyTime) Match(v driver.Value) bool {
@@ -41,9 +44,17 @@ func (as AnyString) Match(v driver.Value) bool {
 	return ok
 }
 
+func (aa AnyArray) Match(v driver.Value) bool {
+	_, ok := v.(string)
+	return ok
+}
+
 func setupTest(t *testing.T) func() {
 	var err error
 	var db *sqlx.DB
+	cfg.Db.DeadLetterTable = "joblist_deadletter"
+	cfg.Db.EventTable = "job_events"
+	cfg.Db.WorkflowTable = "workflows"
 	jrd = NewJobRepositoryDb(&cfg)
 	db, mock, err = sqlmock.Newx()
 	if err != nil {
@@ -57,6 +68,16 @@ func setupTest(t *testing.T) func() {
 	}
 }
 
+func expectLoadHistory() {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM job_events WHERE job_id = ANY($1) ORDER BY id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_id", "created_at", "event_type", "actor", "message", "old_status", "new_status"}))
+}
+
+func expectInsertEvents() {
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO job_events (job_id, created_at, event_type, actor, message, old_status, new_status)`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
 func Test_FindAll_NoWhere_Returns_DbError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -129,7 +150,6 @@ func Test_FindAll_NoWhere_Returns_CountError(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -149,12 +169,12 @@ func Test_FindAll_NoWhere_Returns_CountError(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY %v %v`, table, safReq.Sorts.Field, safReq.Sorts.Dir))).
 		WillReturnRows(rows)
+	expectLoadHistory()
 	sqlErr := sql.ErrConnDone
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v')`, table))).WillReturnError(sqlErr)
 
@@ -193,7 +213,6 @@ func Test_FindAll_NoWhere_Returns_Results(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -213,12 +232,12 @@ func Test_FindAll_NoWhere_Returns_Results(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY %v %v`, table, safReq.Sorts.Field, safReq.Sorts.Dir))).
 		WillReturnRows(rows)
+	expectLoadHistory()
 	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v')`, table))).WillReturnRows(countRows)
 
@@ -259,7 +278,6 @@ func Test_FindAll_WithWhere_Returns_Results(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -279,12 +297,12 @@ func Test_FindAll_WithWhere_Returns_Results(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = 'running' ORDER BY %v %v`, table, safReq.Sorts.Field, safReq.Sorts.Dir))).
 		WillReturnRows(rows)
+	expectLoadHistory()
 	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
 	countWhere := "status = $$running$$"
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v WHERE %v')`, table, countWhere))).WillReturnRows(countRows)
@@ -351,7 +369,6 @@ func Test_FindById_NoError_Returns_Result(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -371,12 +388,12 @@ func Test_FindById_NoError_Returns_Result(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
 		WithArgs(id).WillReturnRows(row)
+	expectLoadHistory()
 
 	job, err := jrd.FindById(id)
 
@@ -406,11 +423,20 @@ func Test_Store_DbError_Returns_InternalServerError(t *testing.T) {
 		action, 
 		action_details, 
 		progress, 
-		history, 
 		extra_data, 
 		priority, 
-		rank) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`, table))).
+		rank, 
+		attempts, 
+		max_attempts, 
+		backoff_policy, 
+		backoff_seconds, 
+		run_at, 
+		depends_on, 
+		dependency_policy, 
+		parent_id, 
+		labels, 
+		unique_key) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)`, table))).
 		WithArgs(
 			job.Id.String(),
 			job.CorrelationId,
@@ -427,10 +453,19 @@ func Test_Store_DbError_Returns_InternalServerError(t *testing.T) {
 			job.Action,
 			job.ActionDetails,
 			job.Progress,
-			job.History,
 			job.ExtraData,
 			job.Priority,
-			job.Rank).
+			job.Rank,
+			job.Attempts,
+			job.MaxAttempts,
+			job.BackoffPolicy,
+			job.BackoffSeconds,
+			job.RunAt,
+			job.DependsOn,
+			job.DependencyPolicy,
+			job.ParentId,
+			job.Labels,
+			job.UniqueKey).
 		WillReturnError(sqlErr)
 
 	err := jrd.Store(*job)
@@ -461,11 +496,20 @@ func Test_Store_NoError_Returns_NoError(t *testing.T) {
 		action, 
 		action_details, 
 		progress, 
-		history, 
 		extra_data, 
 		priority, 
-		rank) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`, table))).
+		rank, 
+		attempts, 
+		max_attempts, 
+		backoff_policy, 
+		backoff_seconds, 
+		run_at, 
+		depends_on, 
+		dependency_policy, 
+		parent_id, 
+		labels, 
+		unique_key) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)`, table))).
 		WithArgs(
 			job.Id.String(),
 			job.CorrelationId,
@@ -482,10 +526,23 @@ func Test_Store_NoError_Returns_NoError(t *testing.T) {
 			job.Action,
 			job.ActionDetails,
 			job.Progress,
-			job.History,
 			job.ExtraData,
 			job.Priority,
-			job.Rank).
+			job.Rank,
+			job.Attempts,
+			job.MaxAttempts,
+			job.BackoffPolicy,
+			job.BackoffSeconds,
+			job.RunAt,
+			job.DependsOn,
+			job.DependencyPolicy,
+			job.ParentId,
+			job.Labels,
+			job.UniqueKey).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO job_events (job_id, created_at, event_type, actor, message, old_status, new_status)`)).
+		WithArgs(pq.Array([]string{job.Id.String()}), AnyArray{}, pq.Array([]string{string(domain.EventCreated)}), pq.Array([]string{domain.ActorSystem}),
+			pq.Array([]string{"Job created"}), pq.Array([]string{""}), pq.Array([]string{string(domain.StatusCreated)})).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := jrd.Store(*job)
@@ -493,6 +550,31 @@ func Test_Store_NoError_Returns_NoError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_NotifyJobCreated_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlErr := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs(cfg.Db.NotifyChannel, "encoding").WillReturnError(sqlErr)
+
+	err := jrd.NotifyJobCreated("encoding")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error notifying about new job", err.Message())
+}
+
+func Test_NotifyJobCreated_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs(cfg.Db.NotifyChannel, "encoding").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := jrd.NotifyJobCreated("encoding")
+
+	assert.Nil(t, err)
+}
+
 func Test_DeleteById_DbError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -529,7 +611,7 @@ func Test_Dequeue_TransactionBeginError_Returns_InternalServerError(t *testing.T
 	sqlErr := sql.ErrConnDone
 	mock.ExpectBegin().WillReturnError(sqlErr)
 
-	job, err := jrd.Dequeue("encoding")
+	job, err := jrd.Dequeue(dto.DequeueRequest{Type: "encoding"})
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
@@ -541,31 +623,37 @@ func Test_Dequeue_NoJobForType_Returns_NotFoundError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	jobType := "encoding"
-	sqlErr := sql.ErrNoRows
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+	}
+	rows := sqlmock.NewRows([]string{})
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table))).
-		WithArgs(string(domain.StatusCreated), jobType).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), 1).WillReturnRows(rows)
 
-	job, err := jrd.Dequeue(jobType)
+	job, err := jrd.Dequeue(dqReq)
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
-	assert.EqualValues(t, fmt.Sprintf("No job found to dequeue for type %v", jobType), err.Message())
+	assert.EqualValues(t, fmt.Sprintf("No job found to dequeue for type %v", dqReq.Type), err.Message())
 }
 
 func Test_Dequeue_DbSelectError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	jobType := "encoding"
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+	}
 	sqlErr := sql.ErrConnDone
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table))).
-		WithArgs(string(domain.StatusCreated), jobType).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), 1).WillReturnError(sqlErr)
 
-	job, err := jrd.Dequeue(jobType)
+	job, err := jrd.Dequeue(dqReq)
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
@@ -577,7 +665,10 @@ func Test_Dequeue_DbUpdateError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	jobType := "encoding"
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+	}
 	sqlErr := sql.ErrConnDone
 	now := date.GetNowUtc()
 	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
@@ -596,7 +687,6 @@ func Test_Dequeue_DbUpdateError_Returns_InternalServerError(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -616,17 +706,17 @@ func Test_Dequeue_DbUpdateError_Returns_InternalServerError(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table))).
-		WithArgs(string(domain.StatusCreated), jobType).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history) = ($1, $2, $3) WHERE id = $4`, table))).
-		WithArgs(AnyTime{}, "running", AnyString{}, id).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), 1).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at, attempts) = ($1, $2, $3, $4, $5, $6) WHERE id = $7`, table))).
+		WithArgs(AnyTime{}, "running", 1, dqReq.Worker, AnyTime{}, 1, id).WillReturnError(sqlErr)
 
-	job, err := jrd.Dequeue(jobType)
+	job, err := jrd.Dequeue(dqReq)
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
@@ -638,7 +728,10 @@ func Test_Dequeue_TransactionCommitError_Returns_InternalServerError(t *testing.
 	teardown := setupTest(t)
 	defer teardown()
 
-	jobType := "encoding"
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+	}
 	sqlErr := sql.ErrTxDone
 	now := date.GetNowUtc()
 	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
@@ -657,7 +750,6 @@ func Test_Dequeue_TransactionCommitError_Returns_InternalServerError(t *testing.
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -677,18 +769,19 @@ func Test_Dequeue_TransactionCommitError_Returns_InternalServerError(t *testing.
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table))).
-		WithArgs(string(domain.StatusCreated), jobType).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history, progress) = ($1, $2, $3, $4) WHERE id = $5`, table))).
-		WithArgs(AnyTime{}, "running", AnyString{}, 1, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), 1).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at, attempts) = ($1, $2, $3, $4, $5, $6) WHERE id = $7`, table))).
+		WithArgs(AnyTime{}, "running", 1, dqReq.Worker, AnyTime{}, 1, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
 	mock.ExpectCommit().WillReturnError(sqlErr)
 
-	job, err := jrd.Dequeue(jobType)
+	job, err := jrd.Dequeue(dqReq)
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
@@ -700,7 +793,10 @@ func Test_Dequeue_NoError_Returns_Job(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	jobType := "encoding"
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+	}
 	now := date.GetNowUtc()
 	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
 	rows := sqlmock.NewRows([]string{
@@ -718,7 +814,6 @@ func Test_Dequeue_NoError_Returns_Job(t *testing.T) {
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -738,22 +833,65 @@ func Test_Dequeue_NoError_Returns_Job(t *testing.T) {
 			"action 1",
 			"action details 1",
 			0,
-			"2022-01-05T06:07:55Z: Job created\n",
 			"no extra data 1",
 			20,
 			0)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND type = $2 ORDER BY priority ASC, rank DESC limit 1`, table))).
-		WithArgs(string(domain.StatusCreated), jobType).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history, progress) = ($1, $2, $3, $4) WHERE id = $5`, table))).
-		WithArgs(AnyTime{}, "running", AnyString{}, 1, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), 1).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at, attempts) = ($1, $2, $3, $4, $5, $6) WHERE id = $7`, table))).
+		WithArgs(AnyTime{}, "running", 1, dqReq.Worker, AnyTime{}, 1, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
 	mock.ExpectCommit()
 
-	job, err := jrd.Dequeue(jobType)
+	jobs, err := jrd.Dequeue(dqReq)
 
-	assert.NotNil(t, job)
+	assert.NotNil(t, jobs)
 	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*jobs))
+	job := (*jobs)[0]
 	assert.EqualValues(t, id, job.Id.String())
+	assert.EqualValues(t, domain.StatusRunning, job.Status)
+	assert.EqualValues(t, dqReq.Worker, job.LeaseOwner)
+	assert.NotNil(t, job.LeaseExpiresAt)
+}
+
+func Test_Dequeue_WithCount_Returns_Jobs(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	dqReq := dto.DequeueRequest{
+		Type:   "encoding",
+		Worker: "worker 1",
+		Count:  2,
+	}
+	id1 := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	id2 := "23GaSImHjnOuKwdxYGP9fY8KmPD"
+	rows := sqlmock.NewRows([]string{"id", "status", "type"}).
+		AddRow(id1, "created", "encoding").
+		AddRow(id2, "created", "encoding")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE (status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now()) AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on)) ORDER BY priority DESC, rank DESC LIMIT $3 FOR UPDATE SKIP LOCKED`, table, deadLetterTable))).
+		WithArgs(string(domain.StatusCreated), pq.Array([]string{dqReq.Type}), dqReq.Count).WillReturnRows(rows)
+	expectLoadHistory()
+	for _, id := range []string{id1, id2} {
+		mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at, attempts) = ($1, $2, $3, $4, $5, $6) WHERE id = $7`, table))).
+			WithArgs(AnyTime{}, "running", 1, dqReq.Worker, AnyTime{}, 1, id).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectInsertEvents()
+	}
+	mock.ExpectCommit()
+
+	jobs, err := jrd.Dequeue(dqReq)
+
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(*jobs))
+	for _, job := range *jobs {
+		assert.EqualValues(t, domain.StatusRunning, job.Status)
+		assert.Contains(t, job.History, "Dequeuing job for processing")
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
 func Test_SetStatusById_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
@@ -766,7 +904,7 @@ func Test_SetStatusById_TransactionBeginError_Returns_InternalServerError(t *tes
 	message := "Job History Updated"
 	mock.ExpectBegin().WillReturnError(sqlErr)
 
-	err := jrd.SetStatusById(id, newStatus, message)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
@@ -782,10 +920,10 @@ func Test_SetStatusById_DbSelectError_Returns_InternalServerError(t *testing.T)
 	newStatus := "failed"
 	message := "Job History Updated"
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT status, history FROM %v WHERE id = $1`, table))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
 		WithArgs(id).WillReturnError(sqlErr)
 
-	err := jrd.SetStatusById(id, newStatus, message)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
@@ -800,22 +938,72 @@ func Test_SetStatusById_DbUpdateError_Returns_InternalServerError(t *testing.T)
 	id := ksuid.New().String()
 	newStatus := "failed"
 	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"status", "history"}).
-		AddRow("running", "2022-01-05T06:07:55Z: Job created\n")
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT status, history FROM %v WHERE id = $1`, table))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
 		WithArgs(id).WillReturnRows(rows)
 
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history) = ($1, $2, $3) WHERE id = $4`, table))).
-		WithArgs(AnyTime{}, newStatus, AnyString{}, id).WillReturnError(sqlErr)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnError(sqlErr)
 
-	err := jrd.SetStatusById(id, newStatus, message)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
 	assert.EqualValues(t, "Database error setting job status with id (update)", err.Message())
 }
 
+func Test_SetStatusById_DeadLetterMoveError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	newStatus := "failed"
+	message := "Job History Updated"
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnError(sqlErr)
+	mock.ExpectRollback()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error moving job to dead-letter queue", err.Message())
+}
+
+func Test_SetStatusById_Finished_DoesNotMoveToDeadLetter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	newStatus := "finished"
+	message := "Job History Updated"
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
 func Test_SetStatusById_TransactionCommitError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -824,17 +1012,24 @@ func Test_SetStatusById_TransactionCommitError_Returns_InternalServerError(t *te
 	id := ksuid.New().String()
 	newStatus := "failed"
 	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"status", "history"}).
-		AddRow("running", "2022-01-05T06:07:55Z: Job created\n")
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT status, history FROM %v WHERE id = $1`, table))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
 		WithArgs(id).WillReturnRows(rows)
 
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history) = ($1, $2, $3) WHERE id = $4`, table))).
-		WithArgs(AnyTime{}, newStatus, AnyString{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
 	mock.ExpectCommit().WillReturnError(sqlErr)
 
-	err := jrd.SetStatusById(id, newStatus, message)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
@@ -848,215 +1043,892 @@ func Test_SetStatusById_NoError_Returns_NoError(t *testing.T) {
 	id := ksuid.New().String()
 	newStatus := "failed"
 	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"status", "history"}).
-		AddRow("running", "2022-01-05T06:07:55Z: Job created\n")
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT status, history FROM %v WHERE id = $1`, table))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
 		WithArgs(id).WillReturnRows(rows)
 
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, history) = ($1, $2, $3) WHERE id = $4`, table))).
-		WithArgs(AnyTime{}, newStatus, AnyString{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
 	mock.ExpectCommit()
 
-	err := jrd.SetStatusById(id, newStatus, message)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
 	assert.Nil(t, err)
 }
 
-func Test_Update_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
+func Test_SetStatusById_FailedWithDependents_Fails_Dependents(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	sqlErr := sql.ErrConnDone
 	id := ksuid.New().String()
-	jobUpdReq := dto.CreateUpdateJobRequest{
-		SubType: "sub type",
-	}
-	mock.ExpectBegin().WillReturnError(sqlErr)
+	dependentId := ksuid.New().String()
+	newStatus := "failed"
+	message := "Encoding failed"
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
+	depRows := sqlmock.NewRows([]string{"id", "status"}).
+		AddRow(dependentId, "created")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(depRows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status) = ($1, $2) WHERE id = $3`, table))).
+		WithArgs(AnyTime{}, newStatus, dependentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(dependentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(dependentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(dependentId, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
 
-	job, err := jrd.Update(id, jobUpdReq)
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
 
-	assert.Nil(t, job)
-	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database transaction start error updating job", err.Message())
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
-func Test_Update_DbSelectError_Returns_InternalServerError(t *testing.T) {
+func Test_SetStatusById_FinishedWithResult_Stores_Result(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	sqlErr := sql.ErrConnDone
 	id := ksuid.New().String()
-	jobUpdReq := dto.CreateUpdateJobRequest{
-		SubType: "sub type",
+	statusReq := dto.UpdateJobStatusRequest{
+		Status: "finished",
+		Result: json.RawMessage(`{"files":["out.mp4"]}`),
 	}
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("running")
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
-		WithArgs(id).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, "finished", 0, "", nil, `{"files":["out.mp4"]}`, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
 
-	job, err := jrd.Update(id, jobUpdReq)
+	err := jrd.SetStatusById(id, statusReq, "Job status changed. New status: finished")
 
-	assert.Nil(t, job)
-	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error updating job (select)", err.Message())
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
-func Test_Update_DbUpdateError_Returns_InternalServerError(t *testing.T) {
+func Test_SetStatusById_NotRetryableError_Moves_ToDeadLetter(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	sqlErr := sql.ErrConnDone
-	oldJob := domain.Job{
-		Id:            ksuid.New(),
-		CorrelationId: "Corr Id 1",
-		Name:          "Job 1",
-		CreatedAt:     time.Now().UTC(),
-		CreatedBy:     "me",
-		ModifiedAt:    time.Now().UTC(),
-		ModifiedBy:    "you",
-		Status:        "running",
-		Source:        "source 1",
-		Destination:   "destination 1",
-		Type:          "encoding",
-		SubType:       "subtype 1",
-		Action:        "action 1",
-		ActionDetails: "action details 1",
-		Progress:      0,
-		History:       "2022-01-05T06:07:55Z: Job created\n",
-		ExtraData:     "no extra data 1",
-		Priority:      2,
-		Rank:          0,
-	}
-
-	jobUpdReq := dto.CreateUpdateJobRequest{
-		SubType: "subtype 2",
+	id := ksuid.New().String()
+	retryable := false
+	statusReq := dto.UpdateJobStatusRequest{
+		Status: "failed",
+		Error:  &dto.JobErrorRequest{Code: "E_SOURCE_MISSING", Message: "Source file not found", Retryable: &retryable},
 	}
-	rows := sqlmock.NewRows([]string{
-		"id",
-		"correlation_id",
-		"name",
-		"created_at",
-		"created_by",
-		"modified_at",
-		"modified_by",
-		"status",
-		"source",
-		"destination",
-		"type",
-		"sub_type",
-		"action",
-		"action_details",
-		"progress",
-		"history",
-		"extra_data",
-		"priority",
-		"rank"}).
-		AddRow(
-			oldJob.Id.String(),
-			oldJob.CorrelationId,
-			oldJob.Name,
-			oldJob.CreatedAt,
-			oldJob.CreatedBy,
-			oldJob.ModifiedAt,
-			oldJob.ModifiedBy,
-			oldJob.Status,
-			oldJob.Source,
-			oldJob.Destination,
-			oldJob.Type,
-			oldJob.SubType,
-			oldJob.Action,
-			oldJob.ActionDetails,
-			oldJob.Progress,
-			oldJob.History,
-			oldJob.ExtraData,
-			oldJob.Priority,
-			oldJob.Rank)
-	mergedJob := mergeJobs(&oldJob, jobUpdReq)
+	rows := sqlmock.NewRows([]string{"status", "attempts", "max_attempts"}).
+		AddRow("running", 1, 3)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
-		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
-			correlation_id, 
-			name, 
-			modified_at, 
-			modified_by, 
-			source, 
-			destination, 
-			type, 
-			sub_type, 
-			action, 
-			action_details, 
-			history, 
-			extra_data, 
-			priority, 
-			rank) = 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) WHERE id = $15`, table))).
-		WithArgs(
-			mergedJob.CorrelationId,
-			mergedJob.Name,
-			AnyTime{},
-			mergedJob.ModifiedBy,
-			mergedJob.Source,
-			mergedJob.Destination,
-			mergedJob.Type,
-			mergedJob.SubType,
-			mergedJob.Action,
-			mergedJob.ActionDetails,
-			mergedJob.History,
-			mergedJob.ExtraData,
-			mergedJob.Priority,
-			mergedJob.Rank,
-			oldJob.Id.String()).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, "failed", 1, "", nil, nil, "E_SOURCE_MISSING", "Source file not found", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, statusReq, "Job status changed. New status: failed")
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_FindDependencies_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT id, status FROM %v WHERE id = ANY($1) UNION ALL SELECT id, status FROM %v WHERE id = ANY($1)`, table, deadLetterTable))).
 		WillReturnError(sqlErr)
 
-	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+	deps, err := jrd.FindDependencies([]string{ksuid.New().String()})
 
-	assert.Nil(t, job)
+	assert.Nil(t, deps)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error updating job (update)", err.Message())
+	assert.EqualValues(t, "Database error getting job dependencies", err.Message())
 }
 
-func Test_Update_TransactionCommitError_Returns_InternalServerError(t *testing.T) {
+func Test_FindDependencies_NoError_Returns_Dependencies(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
 
-	sqlErr := sql.ErrTxDone
-	oldJob := domain.Job{
-		Id:            ksuid.New(),
-		CorrelationId: "Corr Id 1",
-		Name:          "Job 1",
-		CreatedAt:     time.Now().UTC(),
-		CreatedBy:     "me",
-		ModifiedAt:    time.Now().UTC(),
-		ModifiedBy:    "you",
-		Status:        "running",
-		Source:        "source 1",
-		Destination:   "destination 1",
-		Type:          "encoding",
-		SubType:       "subtype 1",
-		Action:        "action 1",
-		ActionDetails: "action details 1",
-		Progress:      0,
-		History:       "2022-01-05T06:07:55Z: Job created\n",
-		ExtraData:     "no extra data 1",
-		Priority:      2,
-		Rank:          0,
-	}
+	id1 := ksuid.New().String()
+	id2 := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status"}).
+		AddRow(id1, "finished").
+		AddRow(id2, "failed")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT id, status FROM %v WHERE id = ANY($1) UNION ALL SELECT id, status FROM %v WHERE id = ANY($1)`, table, deadLetterTable))).
+		WillReturnRows(rows)
 
-	jobUpdReq := dto.CreateUpdateJobRequest{
-		SubType: "subtype 2",
-	}
-	rows := sqlmock.NewRows([]string{
-		"id",
-		"correlation_id",
-		"name",
-		"created_at",
-		"created_by",
-		"modified_at",
-		"modified_by",
+	deps, err := jrd.FindDependencies([]string{id1, id2})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(*deps))
+	assert.EqualValues(t, domain.StatusFinished, (*deps)[0].Status)
+	assert.EqualValues(t, domain.StatusFailed, (*deps)[1].Status)
+}
+
+func Test_SetStatusById_LastChildFinished_Finishes_Parent(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	parentId := ksuid.New().String()
+	newStatus := "finished"
+	message := "Encoding done"
+	rows := sqlmock.NewRows([]string{"status", "parent_id"}).
+		AddRow("running", parentId)
+	parentRows := sqlmock.NewRows([]string{"id", "status"}).
+		AddRow(parentId, "running")
+	childRows := sqlmock.NewRows([]string{"id", "status", "parent_id"}).
+		AddRow(id, "finished", parentId).
+		AddRow(ksuid.New().String(), "finished", parentId)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, newStatus, 0, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(parentId).WillReturnRows(parentRows)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE parent_id = $1 UNION ALL SELECT * FROM %v WHERE parent_id = $1`, table, deadLetterTable))).
+		WithArgs(parentId).WillReturnRows(childRows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress) = ($1, $2, $3) WHERE id = $4`, table))).
+		WithArgs(AnyTime{}, "finished", 100, parentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetStatusById_Running_Counts_Attempt(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"status", "attempts", "max_attempts"}).
+		AddRow("queued", 0, 3)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, "running", 1, sqlmock.AnyArg(), AnyTime{}, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: "running"}, "Job status changed. New status: running")
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetStatusById_Resumed_Refreshes_Lease(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"status", "attempts", "max_attempts", "lease_owner"}).
+		AddRow("paused", 1, 3, "worker 1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, "running", 2, "worker 1", AnyTime{}, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: "running"}, "Job status changed. New status: running")
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetStatusById_FailedOnLastAttempt_Moves_ToDeadLetter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"status", "attempts", "max_attempts"}).
+		AddRow("running", 3, 3)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, attempts, lease_owner, lease_expires_at, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9) WHERE id = $10`, table))).
+		WithArgs(AnyTime{}, "failed", 3, "", nil, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: "failed"}, "Job status changed. New status: failed")
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetStatusById_InvalidTransition_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow("finished")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: "created"}, "Restarting")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Job with id %v cannot change status from finished to created", id), err.Message())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetStatusById_CancelRequested_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"status", "cancel_requested"}).
+		AddRow("running", true)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: "paused"}, "Pausing")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Cancellation of job with id %v was requested. Confirm by setting status to cancelled", id), err.Message())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Cancel_NoJob_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	job, err := jrd.Cancel(id)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No job found for id %v", id), err.Message())
+}
+
+func Test_Cancel_JobDone_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(id, "finished")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	job, err := jrd.Cancel(id)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Job with id %v is already finished and cannot be cancelled", id), err.Message())
+}
+
+func Test_Cancel_QueuedJob_Returns_CancelledJob(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status"}).
+		AddRow(id, "created")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table))).
+		WithArgs(AnyTime{}, "cancelled", "", nil, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	job, err := jrd.Cancel(id)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.StatusCancelled, job.Status)
+	assert.EqualValues(t, false, job.CancelRequested)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Cancel_RunningJob_Returns_CancelRequested(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status", "lease_owner"}).
+		AddRow(id, "running", "worker-1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, cancel_requested) = ($1, $2) WHERE id = $3`, table))).
+		WithArgs(AnyTime{}, true, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	job, err := jrd.Cancel(id)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.StatusRunning, job.Status)
+	assert.EqualValues(t, true, job.CancelRequested)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetProgressById_LowerProgress_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	var progress int32 = 20
+	rows := sqlmock.NewRows([]string{"id", "status", "progress"}).AddRow(id, "running", 50)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectRollback()
+
+	job, err := jrd.SetProgressById(id, dto.UpdateJobProgressRequest{Progress: &progress})
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_SetProgressById_NoError_Returns_Job(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	var progress int32 = 60
+	rows := sqlmock.NewRows([]string{"id", "status", "progress"}).
+		AddRow(id, "running", 50)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, progress, eta, progress_message, progress_logged_at) = ($1, $2, $3, $4, $5) WHERE id = $6`, table))).
+		WithArgs(AnyTime{}, 60, AnyTime{}, "Pass 2", AnyTime{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	job, err := jrd.SetProgressById(id, dto.UpdateJobProgressRequest{Progress: &progress, Message: "Pass 2", Eta: "2026-10-17T12:00:00Z"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 60, job.Progress)
+	assert.EqualValues(t, "Pass 2", job.ProgressMessage)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_AddChildren_ParentNotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	parentId := ksuid.New().String()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(parentId).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	parent, err := jrd.AddChildren(parentId, []domain.Job{})
+
+	assert.Nil(t, parent)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No job found for id %v", parentId), err.Message())
+}
+
+func Test_AddChildren_ParentDone_Returns_ConflictError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	parentId := ksuid.New().String()
+	parentRows := sqlmock.NewRows([]string{"id", "status"}).AddRow(parentId, "finished")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(parentId).WillReturnRows(parentRows)
+	mock.ExpectRollback()
+
+	parent, err := jrd.AddChildren(parentId, []domain.Job{})
+
+	assert.Nil(t, parent)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Job with id %v is already finished and cannot get child jobs", parentId), err.Message())
+}
+
+func Test_AddChildren_NoError_Returns_Parent(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	parentId := ksuid.New().String()
+	child, _ := domain.NewJob("rendition 1", "encoding")
+	child.ParentId = parentId
+	parentRows := sqlmock.NewRows([]string{"id", "status", "lease_owner"}).AddRow(parentId, "running", "worker-1")
+	aggParentRows := sqlmock.NewRows([]string{"id", "status"}).AddRow(parentId, "running")
+	childRows := sqlmock.NewRows([]string{"id", "status", "progress"}).AddRow(child.Id.String(), "created", 0)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(parentId).WillReturnRows(parentRows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v (`, table))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) =`, table))).
+		WithArgs(AnyTime{}, "running", "", nil, parentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(parentId).WillReturnRows(aggParentRows)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE parent_id = $1 UNION ALL`, table))).
+		WithArgs(parentId).WillReturnRows(childRows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress) = ($1, $2, $3) WHERE id = $4`, table))).
+		WithArgs(AnyTime{}, "running", 0, parentId).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	parent, err := jrd.AddChildren(parentId, []domain.Job{*child})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.StatusRunning, parent.Status)
+	assert.EqualValues(t, "", parent.LeaseOwner)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_FindChildren_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	parentId := ksuid.New().String()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE parent_id = $1 UNION ALL`, table))).
+		WithArgs(parentId).WillReturnError(sql.ErrConnDone)
+
+	children, err := jrd.FindChildren(parentId)
+
+	assert.Nil(t, children)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Database error getting child jobs", err.Message())
+}
+
+func Test_SetStatusById_FailedWithAttemptsLeft_Requeues_Job(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	newStatus := "failed"
+	message := "Encoder crashed"
+	rows := sqlmock.NewRows([]string{"status", "attempts", "max_attempts", "backoff_policy", "backoff_seconds"}).
+		AddRow("running", 1, 3, "exponential", 30)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at, not_before, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8) WHERE id = $9`, table))).
+		WithArgs(AnyTime{}, "created", "", nil, AnyTime{}, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetStatusById(id, dto.UpdateJobStatusRequest{Status: newStatus}, message)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Update_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		SubType: "sub type",
+	}
+	mock.ExpectBegin().WillReturnError(sqlErr)
+
+	job, err := jrd.Update(id, jobUpdReq)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database transaction start error updating job", err.Message())
+}
+
+func Test_Update_DbSelectError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		SubType: "sub type",
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnError(sqlErr)
+
+	job, err := jrd.Update(id, jobUpdReq)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error updating job (select)", err.Message())
+}
+
+func Test_Update_DbUpdateError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	extraData := `{"note":"no extra data 1"}`
+	oldJob := domain.Job{
+		Id:            ksuid.New(),
+		CorrelationId: "Corr Id 1",
+		Name:          "Job 1",
+		CreatedAt:     time.Now().UTC(),
+		CreatedBy:     "me",
+		ModifiedAt:    time.Now().UTC(),
+		ModifiedBy:    "you",
+		Status:        "running",
+		Source:        "source 1",
+		Destination:   "destination 1",
+		Type:          "encoding",
+		SubType:       "subtype 1",
+		Action:        "action 1",
+		ActionDetails: "action details 1",
+		Progress:      0,
+		History:       "2022-01-05T06:07:55Z: Job created\n",
+		ExtraData:     &extraData,
+		Priority:      2,
+		Rank:          0,
+	}
+
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		SubType: "subtype 2",
+	}
+	rows := sqlmock.NewRows([]string{
+		"id",
+		"correlation_id",
+		"name",
+		"created_at",
+		"created_by",
+		"modified_at",
+		"modified_by",
+		"status",
+		"source",
+		"destination",
+		"type",
+		"sub_type",
+		"action",
+		"action_details",
+		"progress",
+		"extra_data",
+		"priority",
+		"rank"}).
+		AddRow(
+			oldJob.Id.String(),
+			oldJob.CorrelationId,
+			oldJob.Name,
+			oldJob.CreatedAt,
+			oldJob.CreatedBy,
+			oldJob.ModifiedAt,
+			oldJob.ModifiedBy,
+			oldJob.Status,
+			oldJob.Source,
+			oldJob.Destination,
+			oldJob.Type,
+			oldJob.SubType,
+			oldJob.Action,
+			oldJob.ActionDetails,
+			oldJob.Progress,
+			oldJob.ExtraData,
+			oldJob.Priority,
+			oldJob.Rank)
+	mergedJob := mergeJobs(&oldJob, jobUpdReq)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
+			correlation_id, 
+			name, 
+			modified_at, 
+			modified_by, 
+			source, 
+			destination, 
+			type, 
+			sub_type, 
+			action, 
+			action_details, 
+			extra_data, 
+			priority, 
+			rank, 
+			max_attempts, 
+			backoff_policy, 
+			backoff_seconds, 
+			status, 
+			run_at, 
+			dependency_policy, 
+			labels, 
+			unique_key) = 
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) WHERE id = $22`, table))).
+		WithArgs(
+			mergedJob.CorrelationId,
+			mergedJob.Name,
+			AnyTime{},
+			mergedJob.ModifiedBy,
+			mergedJob.Source,
+			mergedJob.Destination,
+			mergedJob.Type,
+			mergedJob.SubType,
+			mergedJob.Action,
+			mergedJob.ActionDetails,
+			mergedJob.ExtraData,
+			mergedJob.Priority,
+			mergedJob.Rank,
+			mergedJob.MaxAttempts,
+			mergedJob.BackoffPolicy,
+			mergedJob.BackoffSeconds,
+			mergedJob.Status,
+			mergedJob.RunAt,
+			mergedJob.DependencyPolicy,
+			mergedJob.Labels,
+			mergedJob.UniqueKey,
+			oldJob.Id.String()).
+		WillReturnError(sqlErr)
+
+	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error updating job (update)", err.Message())
+}
+
+func Test_Update_TransactionCommitError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrTxDone
+	extraData := `{"note":"no extra data 1"}`
+	oldJob := domain.Job{
+		Id:            ksuid.New(),
+		CorrelationId: "Corr Id 1",
+		Name:          "Job 1",
+		CreatedAt:     time.Now().UTC(),
+		CreatedBy:     "me",
+		ModifiedAt:    time.Now().UTC(),
+		ModifiedBy:    "you",
+		Status:        "running",
+		Source:        "source 1",
+		Destination:   "destination 1",
+		Type:          "encoding",
+		SubType:       "subtype 1",
+		Action:        "action 1",
+		ActionDetails: "action details 1",
+		Progress:      0,
+		History:       "2022-01-05T06:07:55Z: Job created\n",
+		ExtraData:     &extraData,
+		Priority:      2,
+		Rank:          0,
+	}
+
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		SubType: "subtype 2",
+	}
+	rows := sqlmock.NewRows([]string{
+		"id",
+		"correlation_id",
+		"name",
+		"created_at",
+		"created_by",
+		"modified_at",
+		"modified_by",
+		"status",
+		"source",
+		"destination",
+		"type",
+		"sub_type",
+		"action",
+		"action_details",
+		"progress",
+		"extra_data",
+		"priority",
+		"rank"}).
+		AddRow(
+			oldJob.Id.String(),
+			oldJob.CorrelationId,
+			oldJob.Name,
+			oldJob.CreatedAt,
+			oldJob.CreatedBy,
+			oldJob.ModifiedAt,
+			oldJob.ModifiedBy,
+			oldJob.Status,
+			oldJob.Source,
+			oldJob.Destination,
+			oldJob.Type,
+			oldJob.SubType,
+			oldJob.Action,
+			oldJob.ActionDetails,
+			oldJob.Progress,
+			oldJob.ExtraData,
+			oldJob.Priority,
+			oldJob.Rank)
+	mergedJob := mergeJobs(&oldJob, jobUpdReq)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
+		correlation_id, 
+		name, 
+		modified_at, 
+		modified_by, 
+		source, 
+		destination, 
+		type, 
+		sub_type, 
+		action, 
+		action_details, 
+		extra_data, 
+		priority, 
+		rank, 
+		max_attempts, 
+		backoff_policy, 
+		backoff_seconds, 
+		status, 
+		run_at, 
+		dependency_policy, 
+		labels, 
+		unique_key) = 
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) WHERE id = $22`, table))).
+		WithArgs(
+			mergedJob.CorrelationId,
+			mergedJob.Name,
+			AnyTime{},
+			mergedJob.ModifiedBy,
+			mergedJob.Source,
+			mergedJob.Destination,
+			mergedJob.Type,
+			mergedJob.SubType,
+			mergedJob.Action,
+			mergedJob.ActionDetails,
+			mergedJob.ExtraData,
+			mergedJob.Priority,
+			mergedJob.Rank,
+			mergedJob.MaxAttempts,
+			mergedJob.BackoffPolicy,
+			mergedJob.BackoffSeconds,
+			mergedJob.Status,
+			mergedJob.RunAt,
+			mergedJob.DependencyPolicy,
+			mergedJob.Labels,
+			mergedJob.UniqueKey,
+			oldJob.Id.String()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit().WillReturnError(sqlErr)
+
+	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database transaction end error updating job", err.Message())
+}
+
+func Test_Update_NoError_Returns_Job(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	extraData := `{"note":"no extra data 1"}`
+	oldJob := domain.Job{
+		Id:            ksuid.New(),
+		CorrelationId: "Corr Id 1",
+		Name:          "Job 1",
+		CreatedAt:     time.Now().UTC(),
+		CreatedBy:     "me",
+		ModifiedAt:    time.Now().UTC(),
+		ModifiedBy:    "you",
+		Status:        "running",
+		Source:        "source 1",
+		Destination:   "destination 1",
+		Type:          "encoding",
+		SubType:       "subtype 1",
+		Action:        "action 1",
+		ActionDetails: "action details 1",
+		Progress:      0,
+		History:       "2022-01-05T06:07:55Z: Job created\n",
+		ExtraData:     &extraData,
+		Priority:      2,
+		Rank:          0,
+	}
+
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		SubType: "subtype 2",
+	}
+	rows := sqlmock.NewRows([]string{
+		"id",
+		"correlation_id",
+		"name",
+		"created_at",
+		"created_by",
+		"modified_at",
+		"modified_by",
 		"status",
 		"source",
 		"destination",
@@ -1065,7 +1937,6 @@ func Test_Update_TransactionCommitError_Returns_InternalServerError(t *testing.T
 		"action",
 		"action_details",
 		"progress",
-		"history",
 		"extra_data",
 		"priority",
 		"rank"}).
@@ -1085,360 +1956,824 @@ func Test_Update_TransactionCommitError_Returns_InternalServerError(t *testing.T
 			oldJob.Action,
 			oldJob.ActionDetails,
 			oldJob.Progress,
-			oldJob.History,
 			oldJob.ExtraData,
 			oldJob.Priority,
 			oldJob.Rank)
 	mergedJob := mergeJobs(&oldJob, jobUpdReq)
 	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
-		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
-		correlation_id, 
-		name, 
-		modified_at, 
-		modified_by, 
-		source, 
-		destination, 
-		type, 
-		sub_type, 
-		action, 
-		action_details, 
-		history, 
-		extra_data, 
-		priority, 
-		rank) = 
-		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) WHERE id = $15`, table))).
-		WithArgs(
-			mergedJob.CorrelationId,
-			mergedJob.Name,
-			AnyTime{},
-			mergedJob.ModifiedBy,
-			mergedJob.Source,
-			mergedJob.Destination,
-			mergedJob.Type,
-			mergedJob.SubType,
-			mergedJob.Action,
-			mergedJob.ActionDetails,
-			mergedJob.History,
-			mergedJob.ExtraData,
-			mergedJob.Priority,
-			mergedJob.Rank,
-			oldJob.Id.String()).
+		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
+			correlation_id, 
+			name, 
+			modified_at, 
+			modified_by, 
+			source, 
+			destination, 
+			type, 
+			sub_type, 
+			action, 
+			action_details, 
+			extra_data, 
+			priority, 
+			rank, 
+			max_attempts, 
+			backoff_policy, 
+			backoff_seconds, 
+			status, 
+			run_at, 
+			dependency_policy, 
+			labels, 
+			unique_key) = 
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) WHERE id = $22`, table))).
+		WithArgs(
+			mergedJob.CorrelationId,
+			mergedJob.Name,
+			AnyTime{},
+			mergedJob.ModifiedBy,
+			mergedJob.Source,
+			mergedJob.Destination,
+			mergedJob.Type,
+			mergedJob.SubType,
+			mergedJob.Action,
+			mergedJob.ActionDetails,
+			mergedJob.ExtraData,
+			mergedJob.Priority,
+			mergedJob.Rank,
+			mergedJob.MaxAttempts,
+			mergedJob.BackoffPolicy,
+			mergedJob.BackoffSeconds,
+			mergedJob.Status,
+			mergedJob.RunAt,
+			mergedJob.DependencyPolicy,
+			mergedJob.Labels,
+			mergedJob.UniqueKey,
+			oldJob.Id.String()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+
+	assert.NotNil(t, job)
+	assert.Nil(t, err)
+	assert.EqualValues(t, oldJob.Id, job.Id)
+	assert.EqualValues(t, jobUpdReq.SubType, job.SubType)
+}
+
+func Test_SetHistoryById_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	message := "Job History Updated"
+	mock.ExpectBegin().WillReturnError(sqlErr)
+
+	err := jrd.SetHistoryById(id, message)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database transaction start error setting job history by id", err.Message())
+}
+
+func Test_SetHistoryById_DbSelectError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	message := "Job History Updated"
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnError(sqlErr)
+
+	err := jrd.SetHistoryById(id, message)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error setting job history by id (select)", err.Message())
+}
+
+func Test_SetHistoryById_DbUpdateError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	message := "Job History Updated"
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(id)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnRows(rows)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET modified_at = $1 WHERE id = $2`, table))).
+		WithArgs(AnyTime{}, id).WillReturnError(sqlErr)
+
+	err := jrd.SetHistoryById(id, message)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error setting job history by id (update)", err.Message())
+}
+
+func Test_SetHistoryById_TransactionCommitError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrTxDone
+	id := ksuid.New().String()
+	message := "Job History Updated"
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(id)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnRows(rows)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET modified_at = $1 WHERE id = $2`, table))).
+		WithArgs(AnyTime{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit().WillReturnError(sqlErr)
+
+	err := jrd.SetHistoryById(id, message)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database transaction end error setting job history by id", err.Message())
+}
+
+func Test_SetHistoryById_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	message := "Job History Updated"
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(id)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnRows(rows)
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET modified_at = $1 WHERE id = $2`, table))).
+		WithArgs(AnyTime{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := jrd.SetHistoryById(id, message)
+
+	assert.Nil(t, err)
+}
+
+func Test_FindEvents_CountError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE job_id = $1`, eventTable))).
+		WithArgs(id).WillReturnError(sql.ErrConnDone)
+
+	events, totalCount, err := jrd.FindEvents(id, 10, 0)
+
+	assert.Nil(t, events)
+	assert.EqualValues(t, 0, totalCount)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error getting job history", err.Message())
+}
+
+func Test_FindEvents_NoEvents_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(0)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE job_id = $1`, eventTable))).
+		WithArgs(id).WillReturnRows(countRows)
+
+	events, totalCount, err := jrd.FindEvents(id, 10, 0)
+
+	assert.Nil(t, events)
+	assert.EqualValues(t, 0, totalCount)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No history found for job id %v", id), err.Message())
+}
+
+func Test_FindEvents_NoError_Returns_Events(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	now := date.GetNowUtc()
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(3)
+	eventRows := sqlmock.NewRows([]string{"id", "job_id", "created_at", "event_type", "actor", "message", "old_status", "new_status"}).
+		AddRow(2, id, now, "status_changed", "worker 1", "Dequeuing job for processing", "created", "running").
+		AddRow(3, id, now, "progress", "worker 1", "Progress 50%", "", "")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE job_id = $1`, eventTable))).
+		WithArgs(id).WillReturnRows(countRows)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE job_id = $1 ORDER BY id LIMIT $2 OFFSET $3`, eventTable))).
+		WithArgs(id, 2, 1).WillReturnRows(eventRows)
+
+	events, totalCount, err := jrd.FindEvents(id, 2, 1)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, totalCount)
+	assert.EqualValues(t, 2, len(*events))
+	assert.EqualValues(t, domain.EventStatusChanged, (*events)[0].EventType)
+	assert.EqualValues(t, "running", (*events)[0].NewStatus)
+	assert.EqualValues(t, domain.EventProgress, (*events)[1].EventType)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_FindById_WithEvents_Returns_RenderedHistory(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	id := ksuid.New().String()
+	createdAt := time.Date(2022, 1, 5, 6, 7, 55, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(id, "running")
+	eventRows := sqlmock.NewRows([]string{"id", "job_id", "created_at", "event_type", "actor", "message", "old_status", "new_status"}).
+		AddRow(1, id, createdAt, "created", "system", "Job created", "", "created").
+		AddRow(2, id, createdAt.Add(time.Minute), "status_changed", "worker 1", "Dequeuing job for processing", "created", "running")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM job_events WHERE job_id = ANY($1) ORDER BY id`)).
+		WithArgs(pq.Array([]string{id})).WillReturnRows(eventRows)
+
+	job, err := jrd.FindById(id)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "2022-01-05T06:07:55Z: Job created\n2022-01-05T06:08:55Z: Dequeuing job for processing\n", job.History)
+}
+
+func Test_DeleteAllJobs_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlError := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v`, table))).
+		WillReturnError(sqlError)
+
+	err := jrd.DeleteAllJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error deleting all jobs", err.Message())
+}
+
+func Test_DeleteAllJobs_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v`, table))).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit().WillReturnError(sqlErr)
 
-	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+	err := jrd.DeleteAllJobs()
+
+	assert.Nil(t, err)
+}
+
+func Test_CleanupJobs_SucceededDeleteFailed_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlError := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnError(sqlError)
+
+	err := jrd.CleanupJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error deleting expired succeeded jobs", err.Message())
+}
+
+func Test_CleanupJobs_DeadLetterDeleteFailed_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlError := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, deadLetterTable, table))).
+		WithArgs(AnyTime{}).WillReturnError(sqlError)
+
+	err := jrd.CleanupJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error deleting expired dead-lettered jobs", err.Message())
+}
+
+func Test_CleanupJobs_CancelledDeleteFailed_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlError := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, deadLetterTable, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %[1]v prereq WHERE status = 'cancelled' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %[1]v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, table))).
+		WithArgs(AnyTime{}).WillReturnError(sqlError)
+
+	err := jrd.CleanupJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error deleting expired cancelled jobs", err.Message())
+}
+
+func Test_CleanupJobs_EventDeleteFailed_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlErr := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, deadLetterTable, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %[1]v prereq WHERE status = 'cancelled' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %[1]v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v ev WHERE NOT EXISTS (SELECT 1 FROM %v job WHERE job.id = ev.job_id)`, eventTable, table))).
+		WillReturnError(sqlErr)
+
+	err := jrd.CleanupJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error deleting orphaned job events", err.Message())
+}
+
+func Test_CleanupJobs_InProgressFailed_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlError := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, deadLetterTable, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %[1]v prereq WHERE status = 'cancelled' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %[1]v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v ev WHERE NOT EXISTS (SELECT 1 FROM %v job WHERE job.id = ev.job_id)`, eventTable, table))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE status = 'running' AND modified_at < $1`, table))).
+		WithArgs(AnyTime{}).WillReturnError(sqlError)
+
+	err := jrd.CleanupJobs()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error checking for stale in-progress jobs", err.Message())
+}
+
+func Test_CleanupJobs_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v job WHERE status = 'finished' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v wf WHERE wf.status = 'running' AND wf.stages::jsonb -> wf.current_stage ->> 'jobId' = job.id)`, table, cfg.Db.WorkflowTable))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v prereq WHERE modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, deadLetterTable, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %[1]v prereq WHERE status = 'cancelled' AND modified_at < $1 AND NOT EXISTS (SELECT 1 FROM %[1]v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, table))).
+		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v ev WHERE NOT EXISTS (SELECT 1 FROM %v job WHERE job.id = ev.job_id)`, eventTable, table))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE status = 'running' AND modified_at < $1`, table))).
+		WithArgs(AnyTime{}).WillReturnRows(countRows)
+
+	err := jrd.CleanupJobs()
+
+	assert.Nil(t, err)
+}
+
+func Test_FindDeadLetterById_NoJob_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New().String()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, deadLetterTable))).
+		WithArgs(id).WillReturnError(sql.ErrNoRows)
+
+	job, err := jrd.FindDeadLetterById(id)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No dead-lettered job found for id %v", id), err.Message())
+}
+
+func Test_FindDeadLetterById_NoError_Returns_Job(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(id, "failed")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, deadLetterTable))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+
+	job, err := jrd.FindDeadLetterById(id)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, job)
+	assert.EqualValues(t, id, job.Id.String())
+	assert.EqualValues(t, domain.StatusFailed, job.Status)
+}
+
+func Test_FindAllDeadLetters_NoError_Returns_Jobs(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	safReq := dto.SortAndFilterRequest{
+		Sorts: dto.SortBy{
+			Field: "id",
+			Dir:   "DESC",
+		},
+		Limit:  10,
+		Offset: 0,
+	}
+	rows := sqlmock.NewRows([]string{"id", "status"}).AddRow(ksuid.New().String(), "failed")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY id DESC LIMIT $1 OFFSET $2`, deadLetterTable))).
+		WithArgs(10, 0).WillReturnRows(rows)
+	expectLoadHistory()
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count_estimate('SELECT 1 FROM %v')`, deadLetterTable))).
+		WillReturnRows(countRows)
+
+	jobs, totalCount, err := jrd.FindAllDeadLetters(safReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*jobs))
+	assert.EqualValues(t, 1, totalCount)
+}
+
+func Test_RequeueDeadLetter_NoJob_Returns_NotFoundError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := ksuid.New().String()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, deadLetterTable))).
+		WithArgs(id).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	job, err := jrd.RequeueDeadLetter(id)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No dead-lettered job found for id %v", id), err.Message())
+}
+
+func Test_RequeueDeadLetter_MoveError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlErr := sql.ErrConnDone
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status", "attempts"}).
+		AddRow(id, "failed", 3)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, deadLetterTable))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, attempts, lease_owner, lease_expires_at, not_before, cancel_requested, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) WHERE id = $13`, deadLetterTable))).
+		WithArgs(AnyTime{}, "created", 0, 0, "", nil, nil, false, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, table, deadLetterTable))).
+		WithArgs(id).WillReturnError(sqlErr)
+	mock.ExpectRollback()
+
+	job, err := jrd.RequeueDeadLetter(id)
 
 	assert.Nil(t, job)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database transaction end error updating job", err.Message())
+	assert.EqualValues(t, "Database error requeuing dead-lettered job (move)", err.Message())
 }
 
-func Test_Update_NoError_Returns_Job(t *testing.T) {
+func Test_RequeueDeadLetter_NoError_Returns_Job(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-
-	oldJob := domain.Job{
-		Id:            ksuid.New(),
-		CorrelationId: "Corr Id 1",
-		Name:          "Job 1",
-		CreatedAt:     time.Now().UTC(),
-		CreatedBy:     "me",
-		ModifiedAt:    time.Now().UTC(),
-		ModifiedBy:    "you",
-		Status:        "running",
-		Source:        "source 1",
-		Destination:   "destination 1",
-		Type:          "encoding",
-		SubType:       "subtype 1",
-		Action:        "action 1",
-		ActionDetails: "action details 1",
-		Progress:      0,
-		History:       "2022-01-05T06:07:55Z: Job created\n",
-		ExtraData:     "no extra data 1",
-		Priority:      2,
-		Rank:          0,
-	}
-
-	jobUpdReq := dto.CreateUpdateJobRequest{
-		SubType: "subtype 2",
-	}
-	rows := sqlmock.NewRows([]string{
-		"id",
-		"correlation_id",
-		"name",
-		"created_at",
-		"created_by",
-		"modified_at",
-		"modified_by",
-		"status",
-		"source",
-		"destination",
-		"type",
-		"sub_type",
-		"action",
-		"action_details",
-		"progress",
-		"history",
-		"extra_data",
-		"priority",
-		"rank"}).
-		AddRow(
-			oldJob.Id.String(),
-			oldJob.CorrelationId,
-			oldJob.Name,
-			oldJob.CreatedAt,
-			oldJob.CreatedBy,
-			oldJob.ModifiedAt,
-			oldJob.ModifiedBy,
-			oldJob.Status,
-			oldJob.Source,
-			oldJob.Destination,
-			oldJob.Type,
-			oldJob.SubType,
-			oldJob.Action,
-			oldJob.ActionDetails,
-			oldJob.Progress,
-			oldJob.History,
-			oldJob.ExtraData,
-			oldJob.Priority,
-			oldJob.Rank)
-	mergedJob := mergeJobs(&oldJob, jobUpdReq)
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status", "attempts"}).
+		AddRow(id, "failed", 3)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, table))).
-		WithArgs(oldJob.Id.String()).WillReturnRows(rows)
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (
-			correlation_id, 
-			name, 
-			modified_at, 
-			modified_by, 
-			source, 
-			destination, 
-			type, 
-			sub_type, 
-			action, 
-			action_details, 
-			history, 
-			extra_data, 
-			priority, 
-			rank) = 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) WHERE id = $15`, table))).
-		WithArgs(
-			mergedJob.CorrelationId,
-			mergedJob.Name,
-			AnyTime{},
-			mergedJob.ModifiedBy,
-			mergedJob.Source,
-			mergedJob.Destination,
-			mergedJob.Type,
-			mergedJob.SubType,
-			mergedJob.Action,
-			mergedJob.ActionDetails,
-			mergedJob.History,
-			mergedJob.ExtraData,
-			mergedJob.Priority,
-			mergedJob.Rank,
-			oldJob.Id.String()).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, deadLetterTable))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, attempts, lease_owner, lease_expires_at, not_before, cancel_requested, result, error_code, error_message, error_retryable) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) WHERE id = $13`, deadLetterTable))).
+		WithArgs(AnyTime{}, "created", 0, 0, "", nil, nil, false, nil, "", "", false, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, table, deadLetterTable))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, deadLetterTable))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	job, err := jrd.Update(oldJob.Id.String(), jobUpdReq)
+	job, err := jrd.RequeueDeadLetter(id)
 
-	assert.NotNil(t, job)
 	assert.Nil(t, err)
-	assert.EqualValues(t, oldJob.Id, job.Id)
-	assert.EqualValues(t, jobUpdReq.SubType, job.SubType)
+	assert.NotNil(t, job)
+	assert.EqualValues(t, domain.StatusCreated, job.Status)
+	assert.EqualValues(t, 0, job.Attempts)
+	assert.Contains(t, job.History, "Requeued job from dead-letter queue")
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
-func Test_SetHistoryById_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
+func Test_ReleaseExpiredLeases_TransactionBeginError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-
 	sqlErr := sql.ErrConnDone
-	id := ksuid.New().String()
-	message := "Job History Updated"
 	mock.ExpectBegin().WillReturnError(sqlErr)
 
-	err := jrd.SetHistoryById(id, message)
+	released, err := jrd.ReleaseExpiredLeases()
 
 	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, released)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database transaction start error setting job history by id", err.Message())
+	assert.EqualValues(t, "Database transaction start error releasing expired leases", err.Message())
 }
 
-func Test_SetHistoryById_DbSelectError_Returns_InternalServerError(t *testing.T) {
+func Test_ReleaseExpiredLeases_DbSelectError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-
 	sqlErr := sql.ErrConnDone
-	id := ksuid.New().String()
-	message := "Job History Updated"
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT history FROM %v WHERE id = $1`, table))).
-		WithArgs(id).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND lease_expires_at < $2 FOR UPDATE SKIP LOCKED`, table))).
+		WithArgs(string(domain.StatusRunning), AnyTime{}).WillReturnError(sqlErr)
 
-	err := jrd.SetHistoryById(id, message)
+	released, err := jrd.ReleaseExpiredLeases()
 
 	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, released)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error setting job history by id (select)", err.Message())
+	assert.EqualValues(t, "Database error releasing expired leases (select)", err.Message())
 }
 
-func Test_SetHistoryById_DbUpdateError_Returns_InternalServerError(t *testing.T) {
+func Test_ReleaseExpiredLeases_DbUpdateError_Returns_InternalServerError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-
 	sqlErr := sql.ErrConnDone
-	id := ksuid.New().String()
-	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"history"}).
-		AddRow("2022-01-05T06:07:55Z: Job created\n")
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "lease_owner", "attempts", "max_attempts"}).
+		AddRow(id, "running", "worker 1", 1, 3)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT history FROM %v WHERE id = $1`, table))).
-		WithArgs(id).WillReturnRows(rows)
-
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, history) = ($1, $2) WHERE id = $3`, table))).
-		WithArgs(AnyTime{}, AnyString{}, id).WillReturnError(sqlErr)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND lease_expires_at < $2 FOR UPDATE SKIP LOCKED`, table))).
+		WithArgs(string(domain.StatusRunning), AnyTime{}).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at) = ($1, $2, $3, $4, $5) WHERE id = $6`, table))).
+		WithArgs(AnyTime{}, string(domain.StatusCreated), 0, "", nil, id).WillReturnError(sqlErr)
 
-	err := jrd.SetHistoryById(id, message)
+	released, err := jrd.ReleaseExpiredLeases()
 
 	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, released)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error setting job history by id (update)", err.Message())
+	assert.EqualValues(t, "Database error releasing expired leases (update)", err.Message())
 }
 
-func Test_SetHistoryById_TransactionCommitError_Returns_InternalServerError(t *testing.T) {
+func Test_ReleaseExpiredLeases_NoError_Returns_ReleasedCount(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "lease_owner", "attempts", "max_attempts"}).
+		AddRow(id, "running", "worker 1", 1, 3)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND lease_expires_at < $2 FOR UPDATE SKIP LOCKED`, table))).
+		WithArgs(string(domain.StatusRunning), AnyTime{}).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress, lease_owner, lease_expires_at) = ($1, $2, $3, $4, $5) WHERE id = $6`, table))).
+		WithArgs(AnyTime{}, string(domain.StatusCreated), 0, "", nil, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
 
-	sqlErr := sql.ErrTxDone
-	id := ksuid.New().String()
-	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"history"}).
-		AddRow("2022-01-05T06:07:55Z: Job created\n")
+	released, err := jrd.ReleaseExpiredLeases()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, released)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_ReleaseExpiredLeases_NoAttemptsLeft_Moves_ToDeadLetter(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "lease_owner", "attempts", "max_attempts"}).
+		AddRow(id, "running", "worker 1", 3, 3)
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT history FROM %v WHERE id = $1`, table))).
-		WithArgs(id).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1 AND lease_expires_at < $2 FOR UPDATE SKIP LOCKED`, table))).
+		WithArgs(string(domain.StatusRunning), AnyTime{}).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table))).
+		WithArgs(AnyTime{}, string(domain.StatusFailed), "", nil, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, deadLetterTable, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, table))).
+		WithArgs(id).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on)`, table))).
+		WithArgs(id, "fail", "created", "scheduled", "queued").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
 
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, history) = ($1, $2) WHERE id = $3`, table))).
-		WithArgs(AnyTime{}, AnyString{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit().WillReturnError(sqlErr)
+	released, err := jrd.ReleaseExpiredLeases()
 
-	err := jrd.SetHistoryById(id, message)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, released)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_Heartbeat_DbSelectError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlErr := sql.ErrConnDone
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnError(sqlErr)
 
+	job, err := jrd.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1"})
+
+	assert.Nil(t, job)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database transaction end error setting job history by id", err.Message())
+	assert.EqualValues(t, "Database error processing heartbeat (select)", err.Message())
 }
 
-func Test_SetHistoryById_NoError_Returns_NoError(t *testing.T) {
+func Test_Heartbeat_OtherWorker_Returns_ConflictError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "lease_owner"}).
+		AddRow(id, "running", "worker 2")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
 
-	id := ksuid.New().String()
-	message := "Job History Updated"
-	rows := sqlmock.NewRows([]string{"history"}).
-		AddRow("2022-01-05T06:07:55Z: Job created\n")
+	job, err := jrd.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1"})
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Job with id %v is not leased by worker worker 1", id), err.Message())
+}
+
+func Test_Heartbeat_DbUpdateError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	sqlErr := sql.ErrConnDone
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "progress", "lease_owner"}).
+		AddRow(id, "running", 10, "worker 1")
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT history FROM %v WHERE id = $1`, table))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
 		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, progress, progress_logged_at, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table))).
+		WithArgs(AnyTime{}, 10, nil, AnyTime{}, id).WillReturnError(sqlErr)
+
+	job, err := jrd.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1"})
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error processing heartbeat (update)", err.Message())
+}
 
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, history) = ($1, $2) WHERE id = $3`, table))).
-		WithArgs(AnyTime{}, AnyString{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+func Test_Heartbeat_NoError_Returns_Job(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	var progress int32 = 42
+	rows := sqlmock.NewRows([]string{"id", "status", "progress", "lease_owner"}).
+		AddRow(id, "running", 10, "worker 1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (modified_at, progress, progress_logged_at, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table))).
+		WithArgs(AnyTime{}, progress, AnyTime{}, AnyTime{}, id).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
 	mock.ExpectCommit()
 
-	err := jrd.SetHistoryById(id, message)
+	job, err := jrd.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1", Progress: &progress, Message: "Encoding pass 1 done"})
 
+	assert.NotNil(t, job)
 	assert.Nil(t, err)
+	assert.EqualValues(t, progress, job.Progress)
+	assert.NotNil(t, job.LeaseExpiresAt)
+	assert.Contains(t, job.History, "Encoding pass 1 done")
 }
 
-func Test_DeleteAllJobs_DbError_Returns_InternalServerError(t *testing.T) {
+func Test_Heartbeat_ProgressBackwards_Returns_ConflictError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	sqlError := sql.ErrConnDone
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v`, table))).
-		WillReturnError(sqlError)
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	var progress int32 = 5
+	rows := sqlmock.NewRows([]string{"id", "status", "progress", "lease_owner"}).
+		AddRow(id, "running", 10, "worker 1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectRollback()
 
-	err := jrd.DeleteAllJobs()
+	job, err := jrd.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1", Progress: &progress})
 
+	assert.Nil(t, job)
 	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error deleting all jobs", err.Message())
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Progress 5 of job with id %v is lower than its current progress 10", id), err.Message())
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
-func Test_DeleteAllJobs_NoError_Returns_NoError(t *testing.T) {
+func Test_FindActiveByUniqueKey_NoResult_Returns_NotFoundError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v`, table))).
-		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err := jrd.DeleteAllJobs()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE unique_key = $1 AND status NOT IN ($2, $3, $4) LIMIT 1`, table))).
+		WithArgs("encode-a.mxf", "finished", "failed", "cancelled").WillReturnError(sql.ErrNoRows)
 
-	assert.Nil(t, err)
+	job, err := jrd.FindActiveByUniqueKey("encode-a.mxf")
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, "No active job found for unique key encode-a.mxf", err.Message())
 }
 
-func Test_CleanupJobs_FailedDeleteFailed_Returns_InternalServerError(t *testing.T) {
+func Test_FindActiveByUniqueKey_NoError_Returns_Job(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	sqlError := sql.ErrConnDone
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'failed' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnError(sqlError)
 
-	err := jrd.CleanupJobs()
+	id := "23GaSImHjnOuKwdxYGP9fY8KmPC"
+	rows := sqlmock.NewRows([]string{"id", "status", "type", "unique_key"}).
+		AddRow(id, "running", "encoding", "encode-a.mxf")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE unique_key = $1 AND status NOT IN ($2, $3, $4) LIMIT 1`, table))).
+		WithArgs("encode-a.mxf", "finished", "failed", "cancelled").WillReturnRows(rows)
+	expectLoadHistory()
 
-	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error deleting expired failed jobs", err.Message())
+	job, err := jrd.FindActiveByUniqueKey("encode-a.mxf")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, id, job.Id.String())
+	assert.EqualValues(t, "encode-a.mxf", job.UniqueKey)
 }
 
-func Test_CleanupJobs_SucceededDeleteFailed_Returns_InternalServerError(t *testing.T) {
+func Test_Store_DuplicateUniqueKey_Returns_ConflictError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	sqlError := sql.ErrConnDone
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'failed' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'finished' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnError(sqlError)
 
-	err := jrd.CleanupJobs()
+	job, _ := domain.NewJob("Job 1", "Encoding")
+	job.UniqueKey = "encode-a.mxf"
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, table))).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := jrd.Store(*job)
 
 	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error deleting expired succeeded jobs", err.Message())
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Active job with unique key encode-a.mxf already exists", err.Message())
 }
 
-func Test_CleanupJobs_InProgressFailed_Returns_InternalServerError(t *testing.T) {
+func Test_Update_DuplicateUniqueKey_Returns_ConflictError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	sqlError := sql.ErrConnDone
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'failed' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'finished' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE status = 'running' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnError(sqlError)
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status", "type", "source", "unique_key"}).
+		AddRow(id, "created", "encoding", "a.mxf", `type="encoding"|source="a.mxf"`)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT * FROM %v WHERE id = $1 FOR UPDATE", table))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET`, table))).
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		Source:    "b.mxf",
+		UniqueKey: `type="encoding"|source="b.mxf"`,
+	}
 
-	err := jrd.CleanupJobs()
+	job, err := jrd.Update(id, jobUpdReq)
 
+	assert.Nil(t, job)
 	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
-	assert.EqualValues(t, "Database error checking for stale in-progress jobs", err.Message())
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, `Active job with unique key type="encoding"|source="b.mxf" already exists`, err.Message())
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
 
-func Test_CleanupJobs_NoError_Returns_NoError(t *testing.T) {
+func Test_RequeueDeadLetter_DuplicateUniqueKey_Returns_ConflictError(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'failed' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE status = 'finished' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
-	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT count(*) FROM %v WHERE status = 'running' AND modified_at < $1`, table))).
-		WithArgs(AnyTime{}).WillReturnRows(countRows)
+	id := ksuid.New().String()
+	rows := sqlmock.NewRows([]string{"id", "status", "attempts", "unique_key"}).
+		AddRow(id, "failed", 3, "encode-a.mxf")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, deadLetterTable))).
+		WithArgs(id).WillReturnRows(rows)
+	expectLoadHistory()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET`, deadLetterTable))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, table, deadLetterTable))).
+		WithArgs(id).WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
 
-	err := jrd.CleanupJobs()
+	job, err := jrd.RequeueDeadLetter(id)
 
-	assert.Nil(t, err)
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Cannot requeue job %v. Active job with unique key encode-a.mxf already exists", id), err.Message())
+	assert.Nil(t, mock.ExpectationsWereMet())
 }
@@ -1,90 +1,73 @@
 package repositories
 
 import (
+	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/johannes-kuhfuss/jobsvc/domain"
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
 )
 
 func mergeJobs(oldJob *domain.Job, updJobReq dto.CreateUpdateJobRequest) *domain.Job {
-	changed := make(map[string]string)
+	mergedReq, changed := domain.MergeJobRequestChanges(oldJob.ToJobRequestDto(), updJobReq)
 	mergedJob := domain.Job{}
 	mergedJob.Id = oldJob.Id
-	if updJobReq.CorrelationId != "" {
-		mergedJob.CorrelationId = updJobReq.CorrelationId
-		changed["CorrelationId"] = updJobReq.CorrelationId
-	} else {
-		mergedJob.CorrelationId = oldJob.CorrelationId
-	}
-	if updJobReq.Name != "" {
-		mergedJob.Name = updJobReq.Name
-		changed["Name"] = updJobReq.Name
-	} else {
-		mergedJob.Name = oldJob.Name
-	}
+	mergedJob.History = oldJob.History
+	mergedJob.CorrelationId = mergedReq.CorrelationId
+	mergedJob.Name = mergedReq.Name
 	mergedJob.CreatedAt = oldJob.CreatedAt
 	mergedJob.CreatedBy = oldJob.CreatedBy
 	mergedJob.ModifiedAt = date.GetNowUtc()
 	mergedJob.ModifiedBy = ""
 	mergedJob.Status = domain.JobStatus(oldJob.Status)
-	if updJobReq.Source != "" {
-		mergedJob.Source = updJobReq.Source
-		changed["Source"] = updJobReq.Source
-	} else {
-		mergedJob.Source = oldJob.Source
-	}
-	if updJobReq.Destination != "" {
-		mergedJob.Destination = updJobReq.Destination
-		changed["Destination"] = updJobReq.Destination
-	} else {
-		mergedJob.Destination = oldJob.Destination
-	}
-	if updJobReq.Type != "" {
-		mergedJob.Type = updJobReq.Type
-		changed["Type"] = updJobReq.Type
-	} else {
-		mergedJob.Type = oldJob.Type
-	}
-	if updJobReq.SubType != "" {
-		mergedJob.SubType = updJobReq.SubType
-		changed["SubType"] = updJobReq.SubType
-	} else {
-		mergedJob.SubType = oldJob.SubType
-	}
-	if updJobReq.Action != "" {
-		mergedJob.Action = updJobReq.Action
-		changed["Action"] = updJobReq.Action
-	} else {
-		mergedJob.Action = oldJob.Action
-	}
-	if updJobReq.ActionDetails != "" {
-		mergedJob.ActionDetails = updJobReq.ActionDetails
-		changed["ActionDetails"] = updJobReq.ActionDetails
-	} else {
-		mergedJob.ActionDetails = oldJob.ActionDetails
-	}
+	mergedJob.Source = mergedReq.Source
+	mergedJob.Destination = mergedReq.Destination
+	mergedJob.Type = mergedReq.Type
+	mergedJob.SubType = mergedReq.SubType
+	mergedJob.Action = mergedReq.Action
+	mergedJob.ActionDetails = mergedReq.ActionDetails
 	mergedJob.Progress = oldJob.Progress
-	if updJobReq.ExtraData != "" {
-		mergedJob.ExtraData = updJobReq.ExtraData
-		changed["ExtraData"] = updJobReq.ExtraData
+	if updJobReq.HasExtraData() {
+		extraData := string(updJobReq.ExtraData)
+		mergedJob.ExtraData = &extraData
 	} else {
 		mergedJob.ExtraData = oldJob.ExtraData
 	}
+	if len(updJobReq.Labels) > 0 {
+		mergedJob.Labels = domain.JobLabels(mergedReq.Labels)
+	} else {
+		mergedJob.Labels = oldJob.Labels
+	}
 	if updJobReq.Priority != "" {
-		prio, _ := domain.JobPriority.AsIndex(updJobReq.Priority)
-		mergedJob.Priority = prio
-		changed["Priority"] = updJobReq.Priority
+		mergedJob.Priority, _ = domain.JobPriority.AsIndex(mergedReq.Priority)
 	} else {
 		mergedJob.Priority = oldJob.Priority
 	}
-	if updJobReq.Rank != 0 {
-		mergedJob.Rank = updJobReq.Rank
-		changed["Rank"] = string(updJobReq.Rank)
-	} else {
-		mergedJob.Rank = oldJob.Rank
+	mergedJob.Rank = mergedReq.Rank
+	mergedJob.Attempts = oldJob.Attempts
+	mergedJob.MaxAttempts = mergedReq.MaxAttempts
+	mergedJob.BackoffPolicy = domain.BackoffPolicy(mergedReq.BackoffPolicy)
+	mergedJob.BackoffSeconds = mergedReq.BackoffSeconds
+	mergedJob.DependsOn = oldJob.DependsOn
+	delete(changed, "DependsOn")
+	mergedJob.DependencyPolicy = domain.DependencyPolicy(mergedReq.DependencyPolicy)
+	mergedJob.UniqueKey = mergedReq.UniqueKey
+	mergedJob.NotBefore = oldJob.NotBefore
+	mergedJob.RunAt = oldJob.RunAt
+	delete(changed, "RunAt")
+	if updJobReq.RunAt != "" {
+		runAt, err := domain.ParseRunAt(updJobReq.RunAt)
+		if err == nil {
+			mergedJob.ScheduleAt(runAt)
+			changed["RunAt"] = runAt.Format(time.RFC3339)
+		}
 	}
 
 	if len(changed) > 0 {
@@ -92,9 +75,8 @@ func mergeJobs(oldJob *domain.Job, updJobReq dto.CreateUpdateJobRequest) *domain
 		for k, v := range changed {
 			changedStr = fmt.Sprintf("%v%v: %v; ", changedStr, k, v)
 		}
-		oldJob.AddHistory(fmt.Sprintf("Job data changed. New Data: %v", changedStr))
+		mergedJob.AddEvent(domain.EventNote, domain.ActorApi, fmt.Sprintf("Job data changed. New Data: %v", changedStr))
 	}
-	mergedJob.History = oldJob.History
 	return &mergedJob
 }
 
@@ -103,7 +85,11 @@ func constructWhereClause(safReq dto.SortAndFilterRequest) string {
 	for idx, where := range safReq.Filters {
 		sqlFilter := dto.SqlOperatorReplacement[where.Operator]
 		val := strings.Replace(sqlFilter.ValueReplace, "@@", fmt.Sprintf("%v", where.Value), -1)
-		sb.WriteString(where.Field)
+		if column, ok := numericJsonColumn(where.Field, where.Operator, val); ok {
+			sb.WriteString(column)
+		} else {
+			sb.WriteString(filterColumn(where.Field))
+		}
 		sb.WriteString(" ")
 		sb.WriteString(sqlFilter.SqlOperator)
 		sb.WriteString(" '")
@@ -115,3 +101,361 @@ func constructWhereClause(safReq dto.SortAndFilterRequest) string {
 	}
 	return sb.String()
 }
+
+// Range filters on numeric values compare JSON paths as numbers, so that 9 sorts before 10.
+func numericJsonColumn(field string, operator string, val string) (string, bool) {
+	if operator != "gt" && operator != "lt" && operator != "gte" && operator != "lte" {
+		return "", false
+	}
+	if _, err := strconv.ParseFloat(val, 64); err != nil {
+		return "", false
+	}
+	column, path, ok := domain.SplitJsonPath(field)
+	if !ok {
+		return "", false
+	}
+	jsonPath := strings.Join(path, ",")
+	return fmt.Sprintf("CASE WHEN jsonb_typeof(%[1]v #> '{%[2]v}') = 'number' THEN (%[1]v #>> '{%[2]v}')::numeric END", column, jsonPath), true
+}
+
+func filterColumn(field string) string {
+	if labelKey, ok := domain.SplitLabelFilter(field); ok {
+		return fmt.Sprintf("labels ->> '%v'", labelKey)
+	}
+	if column, path, ok := domain.SplitJsonPath(field); ok {
+		return fmt.Sprintf("%v #>> '{%v}'", column, strings.Join(path, ","))
+	}
+	if domain.IsJsonField(field) {
+		return fmt.Sprintf("%v::text", field)
+	}
+	return field
+}
+
+func (jrd JobRepositoryDb) newLease(worker string, leaseSeconds int, now time.Time) (string, time.Time) {
+	leaseOwner := worker
+	if leaseOwner == "" {
+		leaseOwner = ksuid.New().String()
+	}
+	if leaseSeconds <= 0 {
+		leaseSeconds = jrd.cfg.Lease.DurationSeconds
+	}
+	return leaseOwner, now.Add(time.Duration(leaseSeconds) * time.Second)
+}
+
+func (jrd JobRepositoryDb) dequeueOrderBy() string {
+	agingRate := jrd.cfg.Scheduling.PriorityAgingPerMinute
+	if agingRate <= 0 {
+		return "priority DESC, rank DESC"
+	}
+	return fmt.Sprintf("LEAST(priority + %v * EXTRACT(EPOCH FROM (now() - COALESCE(not_before, run_at, created_at))) / 60, %v) DESC, rank DESC", agingRate, domain.MaxJobPriority)
+}
+
+func dequeueCount(dqReq dto.DequeueRequest) int {
+	if dqReq.Count <= 0 {
+		return 1
+	}
+	return dqReq.Count
+}
+
+func dequeueWhereClause(dqReq dto.DequeueRequest) (string, []interface{}) {
+	var sb strings.Builder
+	args := []interface{}{string(domain.StatusCreated), pq.Array(dqReq.AllTypes())}
+	sb.WriteString("(status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now())")
+	sb.WriteString(fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on))", table, deadLetterTable))
+	if len(dqReq.SubTypes) > 0 {
+		args = append(args, pq.Array(dqReq.SubTypes))
+		sb.WriteString(fmt.Sprintf(" AND sub_type = ANY($%d)", len(args)))
+	}
+	if len(dqReq.Actions) > 0 {
+		args = append(args, pq.Array(dqReq.Actions))
+		sb.WriteString(fmt.Sprintf(" AND action = ANY($%d)", len(args)))
+	}
+	if len(dqReq.Labels) > 0 {
+		labels, _ := domain.JobLabels(dqReq.Labels).Value()
+		args = append(args, labels)
+		sb.WriteString(fmt.Sprintf(" AND labels @> $%d", len(args)))
+	}
+	for _, key := range domain.JobLabels(dqReq.ExcludeLabels).SortedKeys() {
+		if dqReq.ExcludeLabels[key] == "" {
+			args = append(args, key)
+			sb.WriteString(fmt.Sprintf(" AND NOT labels ? $%d", len(args)))
+		} else {
+			labels, _ := domain.JobLabels{key: dqReq.ExcludeLabels[key]}.Value()
+			args = append(args, labels)
+			sb.WriteString(fmt.Sprintf(" AND NOT labels @> $%d", len(args)))
+		}
+	}
+	return sb.String(), args
+}
+
+func insertJob(execer sqlx.Execer, job domain.Job) error {
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
+		id, 
+		correlation_id, 
+		name, 
+		created_at, 
+		created_by, 
+		modified_at, 
+		modified_by, 
+		status, 
+		source, 
+		destination, 
+		type, 
+		sub_type, 
+		action, 
+		action_details, 
+		progress, 
+		extra_data, 
+		priority, 
+		rank, 
+		attempts, 
+		max_attempts, 
+		backoff_policy, 
+		backoff_seconds, 
+		run_at, 
+		depends_on, 
+		dependency_policy, 
+		parent_id, 
+		labels, 
+		unique_key) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)`, table)
+	_, err := execer.Exec(sqlInsert,
+		job.Id.String(),
+		job.CorrelationId,
+		job.Name,
+		job.CreatedAt,
+		job.CreatedBy,
+		job.ModifiedAt,
+		job.ModifiedBy,
+		job.Status,
+		job.Source,
+		job.Destination,
+		job.Type,
+		job.SubType,
+		job.Action,
+		job.ActionDetails,
+		job.Progress,
+		job.ExtraData,
+		job.Priority,
+		job.Rank,
+		job.Attempts,
+		job.MaxAttempts,
+		job.BackoffPolicy,
+		job.BackoffSeconds,
+		job.RunAt,
+		job.DependsOn,
+		job.DependencyPolicy,
+		job.ParentId,
+		job.Labels,
+		job.UniqueKey)
+	if err != nil {
+		return err
+	}
+	return insertEvents(execer, &job)
+}
+
+func insertEvents(execer sqlx.Execer, job *domain.Job) error {
+	events := job.PendingEvents()
+	if len(events) == 0 {
+		return nil
+	}
+	var jobIds, createdAts, eventTypes, actors, messages, oldStatuses, newStatuses []string
+	for _, event := range events {
+		jobIds = append(jobIds, event.JobId)
+		createdAts = append(createdAts, event.CreatedAt.Format(time.RFC3339Nano))
+		eventTypes = append(eventTypes, string(event.EventType))
+		actors = append(actors, event.Actor)
+		messages = append(messages, event.Message)
+		oldStatuses = append(oldStatuses, event.OldStatus)
+		newStatuses = append(newStatuses, event.NewStatus)
+	}
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (job_id, created_at, event_type, actor, message, old_status, new_status) 
+		SELECT * FROM unnest($1::varchar[], $2::timestamptz[], $3::varchar[], $4::varchar[], $5::varchar[], $6::varchar[], $7::varchar[])`, eventTable)
+	_, err := execer.Exec(sqlInsert, pq.Array(jobIds), pq.Array(createdAts), pq.Array(eventTypes), pq.Array(actors),
+		pq.Array(messages), pq.Array(oldStatuses), pq.Array(newStatuses))
+	if err != nil {
+		return err
+	}
+	job.ClearPendingEvents()
+	return nil
+}
+
+func loadHistory(queryer sqlx.Queryer, jobs ...*domain.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.Id.String())
+	}
+	events := make([]domain.JobEvent, 0)
+	sqlSelect := fmt.Sprintf(`SELECT * FROM %v WHERE job_id = ANY($1) ORDER BY id`, eventTable)
+	err := sqlx.Select(queryer, &events, sqlSelect, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	eventsByJob := make(map[string][]domain.JobEvent)
+	for _, event := range events {
+		eventsByJob[event.JobId] = append(eventsByJob[event.JobId], event)
+	}
+	for _, job := range jobs {
+		job.SetHistory(eventsByJob[job.Id.String()])
+	}
+	return nil
+}
+
+func jobRefs(jobs []domain.Job) []*domain.Job {
+	refs := make([]*domain.Job, 0, len(jobs))
+	for idx := range jobs {
+		refs = append(refs, &jobs[idx])
+	}
+	return refs
+}
+
+func moveJob(tx *sqlx.Tx, id string, fromTable string, toTable string) error {
+	_, err := tx.Exec(fmt.Sprintf(`INSERT INTO %v SELECT * FROM %v WHERE id = $1`, toTable, fromTable), id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, fromTable), id)
+	return err
+}
+
+// A blocked dependent must keep seeing its failed or cancelled prerequisite, so cleanup leaves it in place.
+func noPendingDependents() string {
+	return fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM %v dep WHERE prereq.id = ANY(dep.depends_on) AND dep.status NOT IN ('finished', 'failed', 'cancelled'))`, table)
+}
+
+func failDependents(tx *sqlx.Tx, id string, now time.Time) error {
+	failed := []string{id}
+	sqlSelect := fmt.Sprintf(`SELECT * FROM %v WHERE $1 = ANY(depends_on) AND dependency_policy = $2 AND (status = $3 OR status = $4 OR status = $5) FOR UPDATE`, table)
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status) = ($1, $2) WHERE id = $3`, table)
+	for len(failed) > 0 {
+		prereqId := failed[0]
+		failed = failed[1:]
+		dependents := make([]domain.Job, 0)
+		err := tx.Select(&dependents, sqlSelect, prereqId, string(domain.DependencyFail), string(domain.StatusCreated), string(domain.StatusScheduled), string(domain.StatusQueued))
+		if err != nil {
+			return err
+		}
+		for _, dependent := range dependents {
+			dependent.ChangeStatus(domain.StatusFailed, domain.ActorSystem, fmt.Sprintf("Prerequisite job %v did not finish", prereqId))
+			dependent.AddHistory("Moving job to dead-letter queue")
+			_, err = tx.Exec(sqlUpdate, now, string(dependent.Status), dependent.Id.String())
+			if err != nil {
+				return err
+			}
+			err = insertEvents(tx, &dependent)
+			if err != nil {
+				return err
+			}
+			err = moveJob(tx, dependent.Id.String(), table, deadLetterTable)
+			if err != nil {
+				return err
+			}
+			failed = append(failed, dependent.Id.String())
+		}
+	}
+	return nil
+}
+
+func aggregateParents(tx *sqlx.Tx, parentId string, now time.Time) error {
+	sqlParent := fmt.Sprintf(`SELECT * FROM %v WHERE id = $1 FOR UPDATE`, table)
+	sqlChildren := fmt.Sprintf(`SELECT * FROM %v WHERE parent_id = $1 UNION ALL SELECT * FROM %v WHERE parent_id = $1`, table, deadLetterTable)
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, progress) = ($1, $2, $3) WHERE id = $4`, table)
+	for parentId != "" {
+		var parent domain.Job
+		err := tx.Get(&parent, sqlParent, parentId)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if parent.Status != domain.StatusRunning {
+			return nil
+		}
+		children := make([]domain.Job, 0)
+		err = tx.Select(&children, sqlChildren, parentId)
+		if err != nil {
+			return err
+		}
+		done := parent.AggregateChildren(children)
+		if done && parent.Status == domain.StatusFailed {
+			parent.AddHistory("Moving job to dead-letter queue")
+		}
+		_, err = tx.Exec(sqlUpdate, now, string(parent.Status), parent.Progress, parentId)
+		if err != nil {
+			return err
+		}
+		err = insertEvents(tx, &parent)
+		if err != nil {
+			return err
+		}
+		if done && parent.Status == domain.StatusFailed {
+			err = moveJob(tx, parentId, table, deadLetterTable)
+			if err != nil {
+				return err
+			}
+			err = failDependents(tx, parentId, now)
+			if err != nil {
+				return err
+			}
+		}
+		parentId = parent.ParentId
+	}
+	return nil
+}
+
+func failExpiredJob(tx *sqlx.Tx, job *domain.Job, now time.Time) error {
+	job.ChangeStatus(domain.StatusFailed, domain.ActorSystem, fmt.Sprintf("Attempt %d of %d failed. Lease of worker %v expired. No attempts left", job.Attempts, job.MaxAttempts, job.LeaseOwner))
+	job.AddHistory("Moving job to dead-letter queue")
+	job.ModifiedAt = now
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table)
+	_, err := tx.Exec(sqlUpdate, now, string(job.Status), "", nil, job.Id.String())
+	if err != nil {
+		return err
+	}
+	err = insertEvents(tx, job)
+	if err != nil {
+		return err
+	}
+	err = moveJob(tx, job.Id.String(), table, deadLetterTable)
+	if err != nil {
+		return err
+	}
+	err = failDependents(tx, job.Id.String(), now)
+	if err != nil {
+		return err
+	}
+	if job.ParentId != "" {
+		return aggregateParents(tx, job.ParentId, now)
+	}
+	return nil
+}
+
+func cancelJob(tx *sqlx.Tx, job *domain.Job, actor string, now time.Time) error {
+	job.ChangeStatus(domain.StatusCancelled, actor, "Job cancelled")
+	job.ModifiedAt = now
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (modified_at, status, lease_owner, lease_expires_at) = ($1, $2, $3, $4) WHERE id = $5`, table)
+	_, err := tx.Exec(sqlUpdate, now, string(job.Status), "", nil, job.Id.String())
+	if err != nil {
+		return err
+	}
+	err = insertEvents(tx, job)
+	if err != nil {
+		return err
+	}
+	err = failDependents(tx, job.Id.String(), now)
+	if err != nil {
+		return err
+	}
+	if job.ParentId != "" {
+		return aggregateParents(tx, job.ParentId, now)
+	}
+	return nil
+}
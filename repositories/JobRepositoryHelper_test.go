@@ -1,18 +1,22 @@
 package repositories
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/johannes-kuhfuss/jobsvc/config"
 	"github.com/johannes-kuhfuss/jobsvc/domain"
 	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/lib/pq"
 	"github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_mergeJobs_NoUpdates_ReturnsJob(t *testing.T) {
+	extraData := `{"note":"no extra data 1"}`
 	oldJob := domain.Job{
 		Id:            ksuid.New(),
 		CorrelationId: "Corr Id 1",
@@ -30,7 +34,7 @@ func Test_mergeJobs_NoUpdates_ReturnsJob(t *testing.T) {
 		ActionDetails: "action details 1",
 		Progress:      0,
 		History:       "2022-01-05T06:07:55Z: Job created\n",
-		ExtraData:     "no extra data 1",
+		ExtraData:     &extraData,
 		Priority:      2,
 		Rank:          0,
 	}
@@ -58,7 +62,45 @@ func Test_mergeJobs_NoUpdates_ReturnsJob(t *testing.T) {
 	assert.EqualValues(t, oldJob.Rank, newJob.Rank)
 }
 
+func Test_mergeJobs_FutureRunAt_SchedulesCreatedJob(t *testing.T) {
+	oldJob := domain.Job{
+		Id:      ksuid.New(),
+		Status:  domain.StatusCreated,
+		Type:    "encoding",
+		History: "2022-01-05T06:07:55Z: Job created\n",
+	}
+	runAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		RunAt: runAt.Format(time.RFC3339),
+	}
+
+	newJob := mergeJobs(&oldJob, jobUpdReq)
+
+	assert.NotNil(t, newJob)
+	assert.EqualValues(t, domain.StatusScheduled, newJob.Status)
+	assert.EqualValues(t, runAt, *newJob.RunAt)
+	assert.Contains(t, newJob.History, "RunAt")
+}
+
+func Test_mergeJobs_RunAtOnRunningJob_KeepsStatus(t *testing.T) {
+	oldJob := domain.Job{
+		Id:      ksuid.New(),
+		Status:  domain.StatusRunning,
+		Type:    "encoding",
+		History: "2022-01-05T06:07:55Z: Job created\n",
+	}
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		RunAt: time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+	}
+
+	newJob := mergeJobs(&oldJob, jobUpdReq)
+
+	assert.EqualValues(t, domain.StatusRunning, newJob.Status)
+	assert.NotNil(t, newJob.RunAt)
+}
+
 func Test_mergeJobs_AllUpdates_ReturnsJob(t *testing.T) {
+	extraData := `{"note":"no extra data 1"}`
 	oldJob := domain.Job{
 		Id:            ksuid.New(),
 		CorrelationId: "Corr Id 1",
@@ -76,7 +118,7 @@ func Test_mergeJobs_AllUpdates_ReturnsJob(t *testing.T) {
 		ActionDetails: "action details 1",
 		Progress:      0,
 		History:       "2022-01-05T06:07:55Z: Job created\n",
-		ExtraData:     "no extra data 1",
+		ExtraData:     &extraData,
 		Priority:      2,
 		Rank:          0,
 	}
@@ -89,7 +131,7 @@ func Test_mergeJobs_AllUpdates_ReturnsJob(t *testing.T) {
 		SubType:       "new sub type",
 		Action:        "new action",
 		ActionDetails: "new action details",
-		ExtraData:     "new extra data",
+		ExtraData:     json.RawMessage(`{"note":"new extra data"}`),
 		Priority:      "high",
 		Rank:          15,
 	}
@@ -110,7 +152,7 @@ func Test_mergeJobs_AllUpdates_ReturnsJob(t *testing.T) {
 	assert.EqualValues(t, jobUpdReq.Action, newJob.Action)
 	assert.EqualValues(t, jobUpdReq.ActionDetails, newJob.ActionDetails)
 	assert.EqualValues(t, oldJob.Progress, newJob.Progress)
-	assert.EqualValues(t, jobUpdReq.ExtraData, newJob.ExtraData)
+	assert.EqualValues(t, string(jobUpdReq.ExtraData), *newJob.ExtraData)
 	prio, _ := domain.JobPriority.AsIndex(jobUpdReq.Priority)
 	assert.EqualValues(t, prio, newJob.Priority)
 	assert.EqualValues(t, jobUpdReq.Rank, newJob.Rank)
@@ -161,3 +203,162 @@ func Test_constructWhereClause_MultiCond_Returns_WhereClause(t *testing.T) {
 	assert.NotNil(t, where)
 	assert.EqualValues(t, "status != 'running' AND created_at >= '2021-12-10'", where)
 }
+
+func Test_constructWhereClause_JsonFields_Returns_WhereClause(t *testing.T) {
+	safReq := dto.SortAndFilterRequest{
+		Filters: []dto.FilterBy{{
+			Field:    "extra_data.asset.id",
+			Operator: "eq",
+			Value:    "123",
+		}, {
+			Field:    "result",
+			Operator: "ct",
+			Value:    "mp4",
+		}},
+	}
+
+	where := constructWhereClause(safReq)
+
+	assert.EqualValues(t, "extra_data #>> '{asset,id}' = '123' AND result::text LIKE '%mp4%'", where)
+}
+
+func Test_constructWhereClause_JsonPathRange_Returns_NumericWhereClause(t *testing.T) {
+	safReq := dto.SortAndFilterRequest{
+		Filters: []dto.FilterBy{{
+			Field:    "extra_data.asset.size",
+			Operator: "gt",
+			Value:    "9",
+		}, {
+			Field:    "extra_data.asset.name",
+			Operator: "lt",
+			Value:    "m",
+		}},
+	}
+
+	where := constructWhereClause(safReq)
+
+	assert.EqualValues(t, "CASE WHEN jsonb_typeof(extra_data #> '{asset,size}') = 'number' THEN (extra_data #>> '{asset,size}')::numeric END > '9' AND extra_data #>> '{asset,name}' < 'm'", where)
+}
+
+func Test_constructWhereClause_Label_Returns_WhereClause(t *testing.T) {
+	safReq := dto.SortAndFilterRequest{
+		Filters: []dto.FilterBy{{
+			Field:    "label.customer",
+			Operator: "eq",
+			Value:    "acme",
+		}},
+	}
+
+	where := constructWhereClause(safReq)
+
+	assert.EqualValues(t, "labels ->> 'customer' = 'acme'", where)
+}
+
+func Test_mergeJobs_Labels_Replaces_Labels(t *testing.T) {
+	oldJob := domain.Job{
+		Id:     ksuid.New(),
+		Status: domain.StatusCreated,
+		Labels: domain.JobLabels{"region": "eu"},
+	}
+	jobUpdReq := dto.CreateUpdateJobRequest{
+		Labels: map[string]string{"region": "us", "customer": "acme"},
+	}
+
+	newJob := mergeJobs(&oldJob, jobUpdReq)
+
+	assert.EqualValues(t, domain.JobLabels{"region": "us", "customer": "acme"}, newJob.Labels)
+	assert.Contains(t, newJob.PendingEvents()[0].Message, "Labels: customer=acme,region=us")
+}
+
+func Test_newLease_NoWorkerNoDuration_Returns_GeneratedOwnerAndDefaultDuration(t *testing.T) {
+	leaseCfg := config.AppConfig{}
+	leaseCfg.Lease.DurationSeconds = 300
+	leaseRepo := NewJobRepositoryDb(&leaseCfg)
+	now := time.Now().UTC()
+
+	owner, expiry := leaseRepo.newLease("", 0, now)
+
+	_, parseErr := ksuid.Parse(owner)
+	assert.Nil(t, parseErr)
+	assert.EqualValues(t, now.Add(300*time.Second), expiry)
+}
+
+func Test_newLease_WithWorkerAndDuration_Returns_RequestedLease(t *testing.T) {
+	leaseCfg := config.AppConfig{}
+	leaseCfg.Lease.DurationSeconds = 300
+	leaseRepo := NewJobRepositoryDb(&leaseCfg)
+	now := time.Now().UTC()
+
+	owner, expiry := leaseRepo.newLease("worker 1", 60, now)
+
+	assert.EqualValues(t, "worker 1", owner)
+	assert.EqualValues(t, now.Add(60*time.Second), expiry)
+}
+
+func Test_dequeueOrderBy_NoAging_Returns_PriorityOrder(t *testing.T) {
+	agingCfg := config.AppConfig{}
+	agingRepo := NewJobRepositoryDb(&agingCfg)
+
+	orderBy := agingRepo.dequeueOrderBy()
+
+	assert.EqualValues(t, "priority DESC, rank DESC", orderBy)
+}
+
+func Test_dequeueOrderBy_WithAging_Returns_AgedPriorityOrder(t *testing.T) {
+	agingCfg := config.AppConfig{}
+	agingCfg.Scheduling.PriorityAgingPerMinute = 0.5
+	agingRepo := NewJobRepositoryDb(&agingCfg)
+
+	orderBy := agingRepo.dequeueOrderBy()
+
+	assert.EqualValues(t, "LEAST(priority + 0.5 * EXTRACT(EPOCH FROM (now() - COALESCE(not_before, run_at, created_at))) / 60, 50) DESC, rank DESC", orderBy)
+}
+
+func Test_dequeueWhereClause_TypeOnly_Returns_TypeCondition(t *testing.T) {
+	dqReq := dto.DequeueRequest{
+		Type: "encoding",
+	}
+
+	where, args := dequeueWhereClause(dqReq)
+
+	assert.EqualValues(t, "(status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now())"+fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on))", table, deadLetterTable), where)
+	assert.EqualValues(t, []interface{}{"created", pq.Array([]string{"encoding"})}, args)
+}
+
+func Test_dequeueWhereClause_Labels_Returns_LabelConditions(t *testing.T) {
+	dqReq := dto.DequeueRequest{
+		Type:          "encoding",
+		Labels:        map[string]string{"region": "eu"},
+		ExcludeLabels: map[string]string{"tier": "", "customer": "acme"},
+	}
+
+	where, args := dequeueWhereClause(dqReq)
+
+	assert.True(t, strings.HasSuffix(where, " AND labels @> $3 AND NOT labels @> $4 AND NOT labels ? $5"))
+	assert.EqualValues(t, []interface{}{
+		"created",
+		pq.Array([]string{"encoding"}),
+		`{"region":"eu"}`,
+		`{"customer":"acme"}`,
+		"tier",
+	}, args)
+}
+
+func Test_dequeueWhereClause_TypesSubTypesActions_Returns_AllConditions(t *testing.T) {
+	dqReq := dto.DequeueRequest{
+		Type:     "encoding",
+		Types:    []string{"proxy", "encoding"},
+		SubTypes: []string{"h264", "hevc"},
+		Actions:  []string{"transcode"},
+	}
+
+	where, args := dequeueWhereClause(dqReq)
+
+	assert.EqualValues(t, "(status = $1 OR status = 'scheduled' OR status = 'queued') AND type = ANY($2) AND (not_before IS NULL OR not_before <= now()) AND (run_at IS NULL OR run_at <= now())"+fmt.Sprintf(" AND NOT EXISTS (SELECT 1 FROM %[1]v prereq WHERE prereq.id = ANY(%[1]v.depends_on) AND prereq.status <> 'finished') AND NOT EXISTS (SELECT 1 FROM %[2]v prereq WHERE prereq.id = ANY(%[1]v.depends_on))", table, deadLetterTable)+" AND sub_type = ANY($3) AND action = ANY($4)", where)
+	assert.EqualValues(t, []interface{}{
+		"created",
+		pq.Array([]string{"encoding", "proxy"}),
+		pq.Array([]string{"h264", "hevc"}),
+		pq.Array([]string{"transcode"}),
+	}, args)
+}
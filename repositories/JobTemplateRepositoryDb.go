@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
+)
+
+type JobTemplateRepositoryDb struct {
+	cfg *config.AppConfig
+}
+
+var (
+	jobTemplateTable string
+)
+
+func NewJobTemplateRepositoryDb(c *config.AppConfig) JobTemplateRepositoryDb {
+	jobTemplateTable = c.Db.JobTemplateTable
+	return JobTemplateRepositoryDb{c}
+}
+
+func (trd JobTemplateRepositoryDb) Store(template domain.JobTemplate) api_error.ApiErr {
+	conn := trd.cfg.RunTime.DbConn
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
+		name,
+		version,
+		description,
+		job_template,
+		created_at)
+		VALUES ($1, $2, $3, $4, $5)`, jobTemplateTable)
+	_, err := conn.Exec(sqlInsert,
+		template.Name,
+		template.Version,
+		template.Description,
+		template.JobTemplate,
+		template.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			msg := fmt.Sprintf("Job template %v version %d already exists", template.Name, template.Version)
+			logger.Info(msg)
+			return api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error storing new job template"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (trd JobTemplateRepositoryDb) FindAll() (*[]domain.JobTemplate, api_error.ApiErr) {
+	conn := trd.cfg.RunTime.DbConn
+	templates := make([]domain.JobTemplate, 0)
+	findAllSql := fmt.Sprintf(`SELECT DISTINCT ON (name) * FROM %v ORDER BY name, version DESC`, jobTemplateTable)
+	err := conn.Select(&templates, findAllSql)
+	if err != nil {
+		msg := "Database error getting all job templates"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &templates, nil
+}
+
+func (trd JobTemplateRepositoryDb) FindByName(name string, version int32) (*domain.JobTemplate, api_error.ApiErr) {
+	conn := trd.cfg.RunTime.DbConn
+	var (
+		template domain.JobTemplate
+		err      error
+	)
+	if version == 0 {
+		findByNameSql := fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 ORDER BY version DESC LIMIT 1`, jobTemplateTable)
+		err = conn.Get(&template, findByNameSql, name)
+	} else {
+		findByNameSql := fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 AND version = $2`, jobTemplateTable)
+		err = conn.Get(&template, findByNameSql, name, version)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No job template found for name %v", name)
+			if version != 0 {
+				msg = fmt.Sprintf("No job template found for name %v and version %d", name, version)
+			}
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting job template by name"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	return &template, nil
+}
+
+func (trd JobTemplateRepositoryDb) FindVersions(name string) (*[]domain.JobTemplate, api_error.ApiErr) {
+	conn := trd.cfg.RunTime.DbConn
+	templates := make([]domain.JobTemplate, 0)
+	findVersionsSql := fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 ORDER BY version`, jobTemplateTable)
+	err := conn.Select(&templates, findVersionsSql, name)
+	if err != nil {
+		msg := "Database error getting job template versions"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &templates, nil
+}
+
+func (trd JobTemplateRepositoryDb) DeleteByName(name string) api_error.ApiErr {
+	conn := trd.cfg.RunTime.DbConn
+	sqlDelete := fmt.Sprintf(`DELETE FROM %v WHERE name = $1`, jobTemplateTable)
+	_, err := conn.Exec(sqlDelete, name)
+	if err != nil {
+		msg := "Database error deleting job template by name"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
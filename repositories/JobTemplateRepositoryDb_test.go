@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var (
+	tmrd JobTemplateRepositoryDb
+)
+
+func setupJobTemplateTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	cfg.Db.JobTemplateTable = "job_templates"
+	tmrd = NewJobTemplateRepositoryDb(&cfg)
+	return teardown
+}
+
+func Test_StoreJobTemplate_Duplicate_Returns_ConflictError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	template := domain.JobTemplate{Name: "encode_hd", Version: 2}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, jobTemplateTable))).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := tmrd.Store(template)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Job template encode_hd version 2 already exists", err.Message())
+}
+
+func Test_StoreJobTemplate_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	template := domain.JobTemplate{Name: "encode_hd", Version: 1, JobTemplate: `{"type":"encoding"}`}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, jobTemplateTable))).
+		WithArgs("encode_hd", 1, "", `{"type":"encoding"}`, AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := tmrd.Store(template)
+
+	assert.Nil(t, err)
+}
+
+func Test_FindAllJobTemplates_NoError_Returns_LatestVersions(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"name", "version", "job_template"}).
+		AddRow("encode_hd", 3, `{"type":"encoding"}`)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT DISTINCT ON (name) * FROM %v ORDER BY name, version DESC`, jobTemplateTable))).
+		WillReturnRows(rows)
+
+	templates, err := tmrd.FindAll()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*templates))
+	assert.EqualValues(t, 3, (*templates)[0].Version)
+}
+
+func Test_FindJobTemplateByName_NoVersion_Returns_LatestVersion(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"name", "version"}).AddRow("encode_hd", 3)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 ORDER BY version DESC LIMIT 1`, jobTemplateTable))).
+		WithArgs("encode_hd").WillReturnRows(rows)
+
+	template, err := tmrd.FindByName("encode_hd", 0)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, template.Version)
+}
+
+func Test_FindJobTemplateByName_Version_NoRows_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 AND version = $2`, jobTemplateTable))).
+		WithArgs("encode_hd", 7).WillReturnError(sql.ErrNoRows)
+
+	template, err := tmrd.FindByName("encode_hd", 7)
+
+	assert.Nil(t, template)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, "No job template found for name encode_hd and version 7", err.Message())
+}
+
+func Test_FindJobTemplateVersions_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE name = $1 ORDER BY version`, jobTemplateTable))).
+		WithArgs("encode_hd").WillReturnError(sql.ErrConnDone)
+
+	templates, err := tmrd.FindVersions("encode_hd")
+
+	assert.Nil(t, templates)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error getting job template versions", err.Message())
+}
+
+func Test_DeleteJobTemplateByName_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupJobTemplateTest(t)
+	defer teardown()
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE name = $1`, jobTemplateTable))).
+		WithArgs("encode_hd").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := tmrd.DeleteByName("encode_hd")
+
+	assert.Nil(t, err)
+}
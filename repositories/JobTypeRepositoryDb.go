@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
+)
+
+type JobTypeRepositoryDb struct {
+	cfg *config.AppConfig
+}
+
+var (
+	jobTypeTable string
+)
+
+func NewJobTypeRepositoryDb(c *config.AppConfig) JobTypeRepositoryDb {
+	jobTypeTable = c.Db.JobTypeTable
+	return JobTypeRepositoryDb{c}
+}
+
+func (trd JobTypeRepositoryDb) Store(jobType domain.JobType) api_error.ApiErr {
+	conn := trd.cfg.RunTime.DbConn
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
+		name,
+		description,
+		sub_types,
+		actions,
+		required_fields,
+		unique_fields,
+		extra_data_schema,
+		created_at,
+		modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`, jobTypeTable)
+	_, err := conn.Exec(sqlInsert,
+		jobType.Name,
+		jobType.Description,
+		jobType.SubTypes,
+		jobType.Actions,
+		jobType.RequiredFields,
+		jobType.UniqueFields,
+		jobType.ExtraDataSchema,
+		jobType.CreatedAt,
+		jobType.ModifiedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			msg := fmt.Sprintf("Job type %v already exists", jobType.Name)
+			logger.Info(msg)
+			return api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error storing new job type"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (trd JobTypeRepositoryDb) FindAll() (*[]domain.JobType, api_error.ApiErr) {
+	conn := trd.cfg.RunTime.DbConn
+	jobTypes := make([]domain.JobType, 0)
+	findAllSql := fmt.Sprintf(`SELECT * FROM %v ORDER BY name`, jobTypeTable)
+	err := conn.Select(&jobTypes, findAllSql)
+	if err != nil {
+		msg := "Database error getting all job types"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &jobTypes, nil
+}
+
+func (trd JobTypeRepositoryDb) FindByName(name string) (*domain.JobType, api_error.ApiErr) {
+	conn := trd.cfg.RunTime.DbConn
+	var jobType domain.JobType
+	findByNameSql := fmt.Sprintf(`SELECT * FROM %v WHERE name = $1`, jobTypeTable)
+	err := conn.Get(&jobType, findByNameSql, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No job type found for name %v", name)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting job type by name"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	return &jobType, nil
+}
+
+func (trd JobTypeRepositoryDb) Update(jobType domain.JobType) api_error.ApiErr {
+	conn := trd.cfg.RunTime.DbConn
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (
+		description,
+		sub_types,
+		actions,
+		required_fields,
+		unique_fields,
+		extra_data_schema,
+		modified_at) =
+		($1, $2, $3, $4, $5, $6, $7) WHERE name = $8`, jobTypeTable)
+	_, err := conn.Exec(sqlUpdate,
+		jobType.Description,
+		jobType.SubTypes,
+		jobType.Actions,
+		jobType.RequiredFields,
+		jobType.UniqueFields,
+		jobType.ExtraDataSchema,
+		jobType.ModifiedAt,
+		jobType.Name)
+	if err != nil {
+		msg := "Database error updating job type"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (trd JobTypeRepositoryDb) DeleteByName(name string) api_error.ApiErr {
+	conn := trd.cfg.RunTime.DbConn
+	sqlDelete := fmt.Sprintf(`DELETE FROM %v WHERE name = $1`, jobTypeTable)
+	_, err := conn.Exec(sqlDelete, name)
+	if err != nil {
+		msg := "Database error deleting job type by name"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
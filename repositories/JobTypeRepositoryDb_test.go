@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var (
+	trd JobTypeRepositoryDb
+)
+
+func setupJobTypeTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	cfg.Db.JobTypeTable = "job_types"
+	trd = NewJobTypeRepositoryDb(&cfg)
+	return teardown
+}
+
+func Test_StoreJobType_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	jobType := domain.JobType{Name: "encoding"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, jobTypeTable))).
+		WillReturnError(sql.ErrConnDone)
+
+	err := trd.Store(jobType)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error storing new job type", err.Message())
+}
+
+func Test_StoreJobType_Duplicate_Returns_ConflictError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	jobType := domain.JobType{Name: "encoding"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, jobTypeTable))).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := trd.Store(jobType)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Job type encoding already exists", err.Message())
+}
+
+func Test_StoreJobType_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	jobType := domain.JobType{Name: "encoding"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, jobTypeTable))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := trd.Store(jobType)
+
+	assert.Nil(t, err)
+}
+
+func Test_FindAllJobTypes_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY name`, jobTypeTable))).
+		WillReturnError(sql.ErrConnDone)
+
+	jobTypes, err := trd.FindAll()
+
+	assert.Nil(t, jobTypes)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error getting all job types", err.Message())
+}
+
+func Test_FindAllJobTypes_NoError_Returns_JobTypes(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"name", "description", "sub_types"}).
+		AddRow("encoding", "Transcode media", "{h264,hevc}")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY name`, jobTypeTable))).
+		WillReturnRows(rows)
+
+	jobTypes, err := trd.FindAll()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*jobTypes))
+	assert.EqualValues(t, "encoding", (*jobTypes)[0].Name)
+	assert.EqualValues(t, pq.StringArray{"h264", "hevc"}, (*jobTypes)[0].SubTypes)
+}
+
+func Test_FindJobTypeByName_NoRows_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE name = $1`, jobTypeTable))).
+		WithArgs("encodng").WillReturnError(sql.ErrNoRows)
+
+	jobType, err := trd.FindByName("encodng")
+
+	assert.Nil(t, jobType)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, "No job type found for name encodng", err.Message())
+}
+
+func Test_FindJobTypeByName_NoError_Returns_JobType(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"name", "extra_data_schema"}).
+		AddRow("encoding", `{"type":"object"}`)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE name = $1`, jobTypeTable))).
+		WithArgs("encoding").WillReturnRows(rows)
+
+	jobType, err := trd.FindByName("encoding")
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encoding", jobType.Name)
+	assert.EqualValues(t, `{"type":"object"}`, *jobType.ExtraDataSchema)
+}
+
+func Test_UpdateJobType_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	jobType := domain.JobType{Name: "encoding"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET`, jobTypeTable))).
+		WillReturnError(sql.ErrConnDone)
+
+	err := trd.Update(jobType)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error updating job type", err.Message())
+}
+
+func Test_DeleteJobTypeByName_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupJobTypeTest(t)
+	defer teardown()
+
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE name = $1`, jobTypeTable))).
+		WithArgs("encoding").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := trd.DeleteByName("encoding")
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+type ScheduleRepositoryDb struct {
+	cfg *config.AppConfig
+}
+
+var (
+	scheduleTable string
+)
+
+func NewScheduleRepositoryDb(c *config.AppConfig) ScheduleRepositoryDb {
+	scheduleTable = c.Db.ScheduleTable
+	return ScheduleRepositoryDb{c}
+}
+
+func (srd ScheduleRepositoryDb) Store(sched domain.Schedule) api_error.ApiErr {
+	conn := srd.cfg.RunTime.DbConn
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
+		id,
+		name,
+		cron_expr,
+		time_zone,
+		job_template,
+		enabled,
+		missed_run_policy,
+		last_run_at,
+		next_run_at,
+		created_at,
+		modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`, scheduleTable)
+	_, err := conn.Exec(sqlInsert,
+		sched.Id.String(),
+		sched.Name,
+		sched.CronExpr,
+		sched.TimeZone,
+		sched.JobTemplate,
+		sched.Enabled,
+		sched.MissedRunPolicy,
+		sched.LastRunAt,
+		sched.NextRunAt,
+		sched.CreatedAt,
+		sched.ModifiedAt)
+	if err != nil {
+		msg := "Database error storing new schedule"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (srd ScheduleRepositoryDb) FindAll() (*[]domain.Schedule, api_error.ApiErr) {
+	conn := srd.cfg.RunTime.DbConn
+	schedules := make([]domain.Schedule, 0)
+	findAllSql := fmt.Sprintf(`SELECT * FROM %v ORDER BY id DESC`, scheduleTable)
+	err := conn.Select(&schedules, findAllSql)
+	if err != nil {
+		msg := "Database error getting all schedules"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &schedules, nil
+}
+
+func (srd ScheduleRepositoryDb) FindById(id string) (*domain.Schedule, api_error.ApiErr) {
+	conn := srd.cfg.RunTime.DbConn
+	var sched domain.Schedule
+	findByIdSql := fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, scheduleTable)
+	err := conn.Get(&sched, findByIdSql, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No schedule found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting schedule by id"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	return &sched, nil
+}
+
+func (srd ScheduleRepositoryDb) Update(sched domain.Schedule) api_error.ApiErr {
+	conn := srd.cfg.RunTime.DbConn
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (
+		name,
+		cron_expr,
+		time_zone,
+		job_template,
+		enabled,
+		missed_run_policy,
+		next_run_at,
+		modified_at) =
+		($1, $2, $3, $4, $5, $6, $7, $8) WHERE id = $9`, scheduleTable)
+	_, err := conn.Exec(sqlUpdate,
+		sched.Name,
+		sched.CronExpr,
+		sched.TimeZone,
+		sched.JobTemplate,
+		sched.Enabled,
+		sched.MissedRunPolicy,
+		sched.NextRunAt,
+		sched.ModifiedAt,
+		sched.Id.String())
+	if err != nil {
+		msg := "Database error updating schedule"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (srd ScheduleRepositoryDb) DeleteById(id string) api_error.ApiErr {
+	conn := srd.cfg.RunTime.DbConn
+	sqlDelete := fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, scheduleTable)
+	_, err := conn.Exec(sqlDelete, id)
+	if err != nil {
+		msg := "Database error deleting schedule by id"
+		logger.Error(msg, err)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (srd ScheduleRepositoryDb) ClaimRun(id string, expectedNextRun *time.Time, lastRun *time.Time, nextRun *time.Time) (bool, api_error.ApiErr) {
+	conn := srd.cfg.RunTime.DbConn
+	sqlClaim := fmt.Sprintf(`UPDATE %v SET (last_run_at, next_run_at) = ($1, $2)
+		WHERE id = $3 AND next_run_at IS NOT DISTINCT FROM $4`, scheduleTable)
+	res, err := conn.Exec(sqlClaim, lastRun, nextRun, id, expectedNextRun)
+	if err != nil {
+		msg := "Database error claiming schedule run"
+		logger.Error(msg, err)
+		return false, api_error.NewInternalServerError(msg, nil)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		msg := "Database error claiming schedule run"
+		logger.Error(msg, err)
+		return false, api_error.NewInternalServerError(msg, nil)
+	}
+	return rows == 1, nil
+}
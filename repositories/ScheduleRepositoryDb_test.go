@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var (
+	srd ScheduleRepositoryDb
+)
+
+func setupScheduleTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	cfg.Db.ScheduleTable = "schedules"
+	srd = NewScheduleRepositoryDb(&cfg)
+	return teardown
+}
+
+func Test_StoreSchedule_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	sched := domain.Schedule{Id: ksuid.New(), CronExpr: "0 6 * * *"}
+	sqlErr := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, scheduleTable))).
+		WillReturnError(sqlErr)
+
+	err := srd.Store(sched)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error storing new schedule", err.Message())
+}
+
+func Test_StoreSchedule_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	sched := domain.Schedule{Id: ksuid.New(), CronExpr: "0 6 * * *"}
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, scheduleTable))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := srd.Store(sched)
+
+	assert.Nil(t, err)
+}
+
+func Test_FindAllSchedules_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	sqlErr := sql.ErrConnDone
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY id DESC`, scheduleTable))).
+		WillReturnError(sqlErr)
+
+	schedules, err := srd.FindAll()
+
+	assert.Nil(t, schedules)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error getting all schedules", err.Message())
+}
+
+func Test_FindAllSchedules_NoError_Returns_Schedules(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	rows := sqlmock.NewRows([]string{"id", "name", "cron_expr", "time_zone", "enabled"}).
+		AddRow(id.String(), "nightly", "0 2 * * *", "UTC", true)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v ORDER BY id DESC`, scheduleTable))).
+		WillReturnRows(rows)
+
+	schedules, err := srd.FindAll()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*schedules))
+	assert.EqualValues(t, id, (*schedules)[0].Id)
+	assert.EqualValues(t, "nightly", (*schedules)[0].Name)
+}
+
+func Test_FindScheduleById_NoRows_Returns_NotFoundError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, scheduleTable))).
+		WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+
+	sched, err := srd.FindById(id.String())
+
+	assert.Nil(t, sched)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No schedule found for id %v", id.String()), err.Message())
+}
+
+func Test_FindScheduleById_NoError_Returns_Schedule(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	rows := sqlmock.NewRows([]string{"id", "cron_expr"}).AddRow(id.String(), "0 6 * * *")
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, scheduleTable))).
+		WithArgs(id.String()).WillReturnRows(rows)
+
+	sched, err := srd.FindById(id.String())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, id, sched.Id)
+	assert.EqualValues(t, "0 6 * * *", sched.CronExpr)
+}
+
+func Test_UpdateSchedule_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	sched := domain.Schedule{Id: ksuid.New(), CronExpr: "0 6 * * *"}
+	sqlErr := sql.ErrConnDone
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET`, scheduleTable))).
+		WillReturnError(sqlErr)
+
+	err := srd.Update(sched)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error updating schedule", err.Message())
+}
+
+func Test_DeleteScheduleById_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`DELETE FROM %v WHERE id = $1`, scheduleTable))).
+		WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := srd.DeleteById(id.String())
+
+	assert.Nil(t, err)
+}
+
+func Test_ClaimRun_RowUpdated_Returns_True(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	now := time.Now().UTC()
+	nextRun := now.Add(time.Hour)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (last_run_at, next_run_at) = ($1, $2)`, scheduleTable))).
+		WithArgs(&now, &nextRun, id.String(), &now).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	claimed, err := srd.ClaimRun(id.String(), &now, &now, &nextRun)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, claimed)
+}
+
+func Test_ClaimRun_AlreadyClaimed_Returns_False(t *testing.T) {
+	teardown := setupScheduleTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	now := time.Now().UTC()
+	nextRun := now.Add(time.Hour)
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (last_run_at, next_run_at) = ($1, $2)`, scheduleTable))).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	claimed, err := srd.ClaimRun(id.String(), &now, &now, &nextRun)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, false, claimed)
+}
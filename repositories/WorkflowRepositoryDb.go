@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/lib/pq"
+)
+
+type WorkflowRepositoryDb struct {
+	cfg *config.AppConfig
+}
+
+var (
+	workflowTable string
+)
+
+func NewWorkflowRepositoryDb(c *config.AppConfig) WorkflowRepositoryDb {
+	workflowTable = c.Db.WorkflowTable
+	return WorkflowRepositoryDb{c}
+}
+
+func (wrd WorkflowRepositoryDb) Store(wf domain.Workflow, firstJob domain.Job) api_error.ApiErr {
+	conn := wrd.cfg.RunTime.DbConn
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error storing new workflow"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	sqlInsert := fmt.Sprintf(`INSERT INTO %v (
+		id,
+		name,
+		correlation_id,
+		status,
+		stages,
+		current_stage,
+		created_at,
+		modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`, workflowTable)
+	_, sqlErr = tx.Exec(sqlInsert,
+		wf.Id.String(),
+		wf.Name,
+		wf.CorrelationId,
+		wf.Status,
+		wf.Stages,
+		wf.CurrentStage,
+		wf.CreatedAt,
+		wf.ModifiedAt)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error storing new workflow"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = insertJob(tx, firstJob)
+	if sqlErr != nil {
+		tx.Rollback()
+		if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && firstJob.UniqueKey != "" {
+			msg := fmt.Sprintf("Active job with unique key %v already exists", firstJob.UniqueKey)
+			logger.Info(msg)
+			return api_error.NewProcessingConflictError(msg)
+		}
+		msg := "Database error storing first job of new workflow"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error storing new workflow"
+		logger.Error(msg, sqlErr)
+		return api_error.NewInternalServerError(msg, nil)
+	}
+	return nil
+}
+
+func (wrd WorkflowRepositoryDb) FindAll() (*[]domain.Workflow, api_error.ApiErr) {
+	conn := wrd.cfg.RunTime.DbConn
+	workflows := make([]domain.Workflow, 0)
+	findAllSql := fmt.Sprintf(`SELECT * FROM %v ORDER BY id DESC`, workflowTable)
+	err := conn.Select(&workflows, findAllSql)
+	if err != nil {
+		msg := "Database error getting all workflows"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &workflows, nil
+}
+
+func (wrd WorkflowRepositoryDb) FindById(id string) (*domain.Workflow, api_error.ApiErr) {
+	conn := wrd.cfg.RunTime.DbConn
+	var wf domain.Workflow
+	findByIdSql := fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, workflowTable)
+	err := conn.Get(&wf, findByIdSql, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("No workflow found for id %v", id)
+			logger.Info(msg)
+			return nil, api_error.NewNotFoundError(msg)
+		} else {
+			msg := "Database error getting workflow by id"
+			logger.Error(msg, err)
+			return nil, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	return &wf, nil
+}
+
+func (wrd WorkflowRepositoryDb) FindRunning() (*[]domain.Workflow, api_error.ApiErr) {
+	conn := wrd.cfg.RunTime.DbConn
+	workflows := make([]domain.Workflow, 0)
+	findRunningSql := fmt.Sprintf(`SELECT * FROM %v WHERE status = $1`, workflowTable)
+	err := conn.Select(&workflows, findRunningSql, string(domain.WorkflowRunning))
+	if err != nil {
+		msg := "Database error getting running workflows"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &workflows, nil
+}
+
+func (wrd WorkflowRepositoryDb) FindStageJobs(ids []string) (*[]domain.Job, api_error.ApiErr) {
+	conn := wrd.cfg.RunTime.DbConn
+	jobs := make([]domain.Job, 0)
+	findJobsSql := fmt.Sprintf(`SELECT * FROM %v WHERE id = ANY($1) UNION ALL SELECT * FROM %v WHERE id = ANY($1)`, table, deadLetterTable)
+	err := conn.Select(&jobs, findJobsSql, pq.Array(ids))
+	if err != nil {
+		msg := "Database error getting workflow stage jobs"
+		logger.Error(msg, err)
+		return nil, api_error.NewInternalServerError(msg, nil)
+	}
+	return &jobs, nil
+}
+
+func (wrd WorkflowRepositoryDb) AdvanceStage(wf domain.Workflow, expectedStage int32, nextJob *domain.Job) (bool, api_error.ApiErr) {
+	conn := wrd.cfg.RunTime.DbConn
+	var sqlErr error
+	var tx *sqlx.Tx
+
+	tx, sqlErr = conn.Beginx()
+	if sqlErr != nil {
+		msg := "Database transaction start error advancing workflow"
+		logger.Error(msg, sqlErr)
+		return false, api_error.NewInternalServerError(msg, nil)
+	}
+	sqlUpdate := fmt.Sprintf(`UPDATE %v SET (status, stages, current_stage, modified_at) = ($1, $2, $3, $4)
+		WHERE id = $5 AND status = $6 AND current_stage = $7`, workflowTable)
+	res, sqlErr := tx.Exec(sqlUpdate, wf.Status, wf.Stages, wf.CurrentStage, wf.ModifiedAt, wf.Id.String(), string(domain.WorkflowRunning), expectedStage)
+	if sqlErr != nil {
+		tx.Rollback()
+		msg := "Database error advancing workflow (update)"
+		logger.Error(msg, sqlErr)
+		return false, api_error.NewInternalServerError(msg, nil)
+	}
+	rows, sqlErr := res.RowsAffected()
+	if sqlErr != nil || rows != 1 {
+		tx.Rollback()
+		return false, nil
+	}
+	if nextJob != nil {
+		sqlErr = insertJob(tx, *nextJob)
+		if sqlErr != nil {
+			tx.Rollback()
+			if pqErr, ok := sqlErr.(*pq.Error); ok && pqErr.Code == "23505" && nextJob.UniqueKey != "" {
+				msg := fmt.Sprintf("Active job with unique key %v already exists", nextJob.UniqueKey)
+				logger.Info(msg)
+				return false, api_error.NewProcessingConflictError(msg)
+			}
+			msg := "Database error advancing workflow (insert)"
+			logger.Error(msg, sqlErr)
+			return false, api_error.NewInternalServerError(msg, nil)
+		}
+	}
+	sqlErr = tx.Commit()
+	if sqlErr != nil {
+		msg := "Database transaction end error advancing workflow"
+		logger.Error(msg, sqlErr)
+		return false, api_error.NewInternalServerError(msg, nil)
+	}
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+var (
+	wrd WorkflowRepositoryDb
+)
+
+func setupWorkflowTest(t *testing.T) func() {
+	teardown := setupTest(t)
+	cfg.Db.WorkflowTable = "workflows"
+	wrd = NewWorkflowRepositoryDb(&cfg)
+	return teardown
+}
+
+func newTestWorkflow() (*domain.Workflow, *domain.Job) {
+	wf, firstJob, _ := domain.NewWorkflowFromRequestDto(dto.CreateWorkflowRequest{
+		Stages: []dto.CreateUpdateJobRequest{{Type: "encoding"}, {Type: "proxy"}},
+	})
+	return wf, firstJob
+}
+
+func Test_StoreWorkflow_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	wf, firstJob := newTestWorkflow()
+	sqlErr := sql.ErrConnDone
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, workflowTable))).
+		WillReturnError(sqlErr)
+	mock.ExpectRollback()
+
+	err := wrd.Store(*wf, *firstJob)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error storing new workflow", err.Message())
+}
+
+func Test_StoreWorkflow_NoError_Returns_NoError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	wf, firstJob := newTestWorkflow()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v`, workflowTable))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v (`, table))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	err := wrd.Store(*wf, *firstJob)
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_FindWorkflowById_NoRows_Returns_NotFoundError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = $1`, workflowTable))).
+		WithArgs(id.String()).WillReturnError(sql.ErrNoRows)
+
+	wf, err := wrd.FindById(id.String())
+
+	assert.Nil(t, wf)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("No workflow found for id %v", id.String()), err.Message())
+}
+
+func Test_FindRunning_NoError_Returns_Workflows(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	id := ksuid.New()
+	rows := sqlmock.NewRows([]string{"id", "status", "current_stage"}).AddRow(id.String(), "running", 1)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE status = $1`, workflowTable))).
+		WithArgs("running").WillReturnRows(rows)
+
+	workflows, err := wrd.FindRunning()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*workflows))
+	assert.EqualValues(t, 1, (*workflows)[0].CurrentStage)
+}
+
+func Test_FindStageJobs_DbError_Returns_InternalServerError(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM %v WHERE id = ANY($1) UNION ALL SELECT * FROM %v WHERE id = ANY($1)`, table, deadLetterTable))).
+		WillReturnError(sql.ErrConnDone)
+
+	jobs, err := wrd.FindStageJobs([]string{ksuid.New().String()})
+
+	assert.Nil(t, jobs)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Database error getting workflow stage jobs", err.Message())
+}
+
+func Test_AdvanceStage_AlreadyAdvanced_Returns_False(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = domain.StatusFinished
+	nextJob, _, _ := wf.Advance(firstJob)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (status, stages, current_stage, modified_at) = ($1, $2, $3, $4)`, workflowTable))).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	claimed, err := wrd.AdvanceStage(*wf, 0, nextJob)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, false, claimed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func Test_AdvanceStage_Claimed_Returns_TrueAndStoresJob(t *testing.T) {
+	teardown := setupWorkflowTest(t)
+	defer teardown()
+
+	wf, firstJob := newTestWorkflow()
+	firstJob.Status = domain.StatusFinished
+	nextJob, _, _ := wf.Advance(firstJob)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`UPDATE %v SET (status, stages, current_stage, modified_at) = ($1, $2, $3, $4)`, workflowTable))).
+		WithArgs(wf.Status, wf.Stages, wf.CurrentStage, AnyTime{}, wf.Id.String(), "running", 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`INSERT INTO %v (`, table))).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectInsertEvents()
+	mock.ExpectCommit()
+
+	claimed, err := wrd.AdvanceStage(*wf, 0, nextJob)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, true, claimed)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
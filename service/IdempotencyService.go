@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockIdempotencyService.go -package=service github.com/johannes-kuhfuss/jobsvc/service IdempotencyService
+type IdempotencyService interface {
+	BeginJobRequest(string, dto.CreateUpdateJobRequest) (*dto.IdempotentJobResponse, api_error.ApiErr)
+	CompleteJobRequest(string, int, dto.JobResponse) api_error.ApiErr
+	AbortJobRequest(string) api_error.ApiErr
+	CleanKeys() api_error.ApiErr
+}
+
+type DefaultIdempotencyService struct {
+	repo domain.IdempotencyKeyRepository
+	Cfg  *config.AppConfig
+}
+
+func NewIdempotencyService(cfg *config.AppConfig, repository domain.IdempotencyKeyRepository) DefaultIdempotencyService {
+	return DefaultIdempotencyService{
+		repo: repository,
+		Cfg:  cfg,
+	}
+}
+
+func (s DefaultIdempotencyService) window() time.Duration {
+	return time.Duration(s.Cfg.Idempotency.WindowHours) * time.Hour
+}
+
+func (s DefaultIdempotencyService) pendingTimeout() time.Duration {
+	return time.Duration(s.Cfg.Idempotency.PendingSeconds) * time.Second
+}
+
+func (s DefaultIdempotencyService) BeginJobRequest(key string, jobReq dto.CreateUpdateJobRequest) (*dto.IdempotentJobResponse, api_error.ApiErr) {
+	newKey := domain.NewIdempotencyKey(key, jobReq)
+	err := s.repo.Store(newKey)
+	if err == nil {
+		return nil, nil
+	}
+	if err.StatusCode() != http.StatusConflict {
+		return nil, err
+	}
+	existing, err := s.repo.FindByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing.IsExpired(s.window()) {
+		return nil, s.reclaimKey(newKey)
+	}
+	if existing.RequestHash != newKey.RequestHash {
+		return nil, api_error.NewValidationError(fmt.Sprintf("Idempotency key %v was already used with a different request body", key))
+	}
+	if !existing.IsCompleted() {
+		if existing.IsExpired(s.pendingTimeout()) {
+			logger.Warn(fmt.Sprintf("Request with idempotency key %v was never completed. Reclaiming key", key))
+			return nil, s.reclaimKey(newKey)
+		}
+		return nil, api_error.NewProcessingConflictError(fmt.Sprintf("Request with idempotency key %v is still being processed", key))
+	}
+	return existing.ToIdempotentJobResponseDto()
+}
+
+func (s DefaultIdempotencyService) reclaimKey(newKey domain.IdempotencyKey) api_error.ApiErr {
+	err := s.repo.DeleteByKey(newKey.Key)
+	if err != nil {
+		return err
+	}
+	return s.repo.Store(newKey)
+}
+
+func (s DefaultIdempotencyService) CompleteJobRequest(key string, statusCode int, jobResp dto.JobResponse) api_error.ApiErr {
+	idemKey := domain.IdempotencyKey{Key: key}
+	idemKey.SetResponse(statusCode, jobResp)
+	return s.repo.Complete(idemKey)
+}
+
+func (s DefaultIdempotencyService) AbortJobRequest(key string) api_error.ApiErr {
+	return s.repo.DeleteByKey(key)
+}
+
+func (s DefaultIdempotencyService) CleanKeys() api_error.ApiErr {
+	return s.repo.DeleteExpired(date.GetNowUtc().Add(-s.window()))
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realdomain "github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockIdemRepo *domain.MockIdempotencyKeyRepository
+	idemService  DefaultIdempotencyService
+)
+
+func setupIdempotency(t *testing.T) func() {
+	teardown := setupJob(t)
+	cfg.Idempotency.WindowHours = 24
+	cfg.Idempotency.PendingSeconds = 60
+	mockIdemRepo = domain.NewMockIdempotencyKeyRepository(jobCtrl)
+	idemService = NewIdempotencyService(&cfg, mockIdemRepo)
+	return teardown
+}
+
+func Test_BeginJobRequest_NewKey_Returns_NoResponse(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	stored, err := idemService.BeginJobRequest("abc", dto.CreateUpdateJobRequest{Type: "encoding"})
+
+	assert.Nil(t, err)
+	assert.Nil(t, stored)
+}
+
+func Test_BeginJobRequest_DifferentBody_Returns_ValidationError(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	existing := realdomain.NewIdempotencyKey("abc", dto.CreateUpdateJobRequest{Type: "encoding", Source: "a.mxf"})
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Idempotency key abc already exists"))
+	mockIdemRepo.EXPECT().FindByKey("abc").Return(&existing, nil)
+
+	stored, err := idemService.BeginJobRequest("abc", dto.CreateUpdateJobRequest{Type: "encoding", Source: "b.mxf"})
+
+	assert.Nil(t, stored)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.StatusCode())
+	assert.EqualValues(t, "Idempotency key abc was already used with a different request body", err.Message())
+}
+
+func Test_BeginJobRequest_InProgress_Returns_ConflictError(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	existing := realdomain.NewIdempotencyKey("abc", jobReq)
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Idempotency key abc already exists"))
+	mockIdemRepo.EXPECT().FindByKey("abc").Return(&existing, nil)
+
+	stored, err := idemService.BeginJobRequest("abc", jobReq)
+
+	assert.Nil(t, stored)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Request with idempotency key abc is still being processed", err.Message())
+}
+
+func Test_BeginJobRequest_Completed_Returns_StoredResponse(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	existing := realdomain.NewIdempotencyKey("abc", jobReq)
+	existing.SetResponse(http.StatusCreated, dto.JobResponse{Id: "2G7VHsD6jkOXq2RmBAbVE1bBqIW"})
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Idempotency key abc already exists"))
+	mockIdemRepo.EXPECT().FindByKey("abc").Return(&existing, nil)
+
+	stored, err := idemService.BeginJobRequest("abc", jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusCreated, stored.StatusCode)
+	assert.EqualValues(t, "2G7VHsD6jkOXq2RmBAbVE1bBqIW", stored.Job.Id)
+}
+
+func Test_BeginJobRequest_Expired_Returns_NoResponse(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	existing := realdomain.NewIdempotencyKey("abc", dto.CreateUpdateJobRequest{Type: "playout"})
+	existing.CreatedAt = date.GetNowUtc().Add(-25 * time.Hour)
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Idempotency key abc already exists"))
+	mockIdemRepo.EXPECT().FindByKey("abc").Return(&existing, nil)
+	mockIdemRepo.EXPECT().DeleteByKey("abc").Return(nil)
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	stored, err := idemService.BeginJobRequest("abc", dto.CreateUpdateJobRequest{Type: "encoding"})
+
+	assert.Nil(t, err)
+	assert.Nil(t, stored)
+}
+
+func Test_BeginJobRequest_StalePending_Returns_NoResponse(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+	existing := realdomain.NewIdempotencyKey("abc", jobReq)
+	existing.CreatedAt = date.GetNowUtc().Add(-2 * time.Minute)
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Idempotency key abc already exists"))
+	mockIdemRepo.EXPECT().FindByKey("abc").Return(&existing, nil)
+	mockIdemRepo.EXPECT().DeleteByKey("abc").Return(nil)
+	mockIdemRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	stored, err := idemService.BeginJobRequest("abc", jobReq)
+
+	assert.Nil(t, err)
+	assert.Nil(t, stored)
+}
+
+func Test_CompleteJobRequest_Stores_Response(t *testing.T) {
+	teardown := setupIdempotency(t)
+	defer teardown()
+	mockIdemRepo.EXPECT().Complete(gomock.Any()).DoAndReturn(func(idemKey realdomain.IdempotencyKey) api_error.ApiErr {
+		assert.EqualValues(t, "abc", idemKey.Key)
+		assert.EqualValues(t, http.StatusCreated, idemKey.StatusCode)
+		assert.Contains(t, *idemKey.Response, "2G7VHsD6jkOXq2RmBAbVE1bBqIW")
+		return nil
+	})
+
+	err := idemService.CompleteJobRequest("abc", http.StatusCreated, dto.JobResponse{Id: "2G7VHsD6jkOXq2RmBAbVE1bBqIW"})
+
+	assert.Nil(t, err)
+}
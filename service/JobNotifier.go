@@ -0,0 +1,27 @@
+package service
+
+import "sync"
+
+type JobNotifier struct {
+	mu     sync.Mutex
+	signal chan struct{}
+}
+
+func NewJobNotifier() *JobNotifier {
+	return &JobNotifier{
+		signal: make(chan struct{}),
+	}
+}
+
+func (n *JobNotifier) Wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.signal
+}
+
+func (n *JobNotifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.signal)
+	n.signal = make(chan struct{})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JobNotifier_Notify_WakesAllWaiters(t *testing.T) {
+	notifier := NewJobNotifier()
+	waiter1 := notifier.Wait()
+	waiter2 := notifier.Wait()
+
+	notifier.Notify()
+
+	for _, waiter := range []<-chan struct{}{waiter1, waiter2} {
+		select {
+		case <-waiter:
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not woken up")
+		}
+	}
+}
+
+func Test_JobNotifier_Wait_AfterNotify_Blocks(t *testing.T) {
+	notifier := NewJobNotifier()
+	notifier.Notify()
+	waiter := notifier.Wait()
+
+	select {
+	case <-waiter:
+		t.Fatal("waiter was woken up without notification")
+	default:
+	}
+	assert.NotNil(t, waiter)
+}
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/johannes-kuhfuss/jobsvc/config"
 	"github.com/johannes-kuhfuss/jobsvc/domain"
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
 )
 
 //go:generate mockgen -destination=../mocks/service/mockJobService.go -package=service github.com/johannes-kuhfuss/jobsvc/service JobService
@@ -15,27 +19,45 @@ type JobService interface {
 	CreateJob(dto.CreateUpdateJobRequest) (*dto.JobResponse, api_error.ApiErr)
 	GetAllJobs(dto.SortAndFilterRequest) (*[]dto.JobResponse, int, api_error.ApiErr)
 	GetJobById(string) (*dto.JobResponse, api_error.ApiErr)
+	CreateChildJobs(string, dto.CreateChildJobsRequest) (*[]dto.JobResponse, api_error.ApiErr)
+	GetChildJobs(string) (*[]dto.JobResponse, api_error.ApiErr)
+	GetAllDeadLetters(dto.SortAndFilterRequest) (*[]dto.JobResponse, int, api_error.ApiErr)
+	GetDeadLetterById(string) (*dto.JobResponse, api_error.ApiErr)
+	RequeueDeadLetter(string) (*dto.JobResponse, api_error.ApiErr)
 	DeleteJobById(string) api_error.ApiErr
-	Dequeue(dto.DequeueRequest) (*dto.JobResponse, api_error.ApiErr)
+	Dequeue(context.Context, dto.DequeueRequest) (*[]dto.JobResponse, api_error.ApiErr)
+	Heartbeat(string, dto.HeartbeatRequest) (*dto.JobResponse, api_error.ApiErr)
 	UpdateJob(string, dto.CreateUpdateJobRequest) (*dto.JobResponse, api_error.ApiErr)
 	SetStatusById(string, dto.UpdateJobStatusRequest) api_error.ApiErr
+	CancelJob(string) (*dto.JobResponse, api_error.ApiErr)
+	SetProgressById(string, dto.UpdateJobProgressRequest) (*dto.JobResponse, api_error.ApiErr)
 	SetHistoryById(string, dto.UpdateJobHistoryRequest) api_error.ApiErr
+	GetJobHistory(string, int, int) (*[]dto.JobEventResponse, int, api_error.ApiErr)
 	DeleteAllJobs() api_error.ApiErr
 	CleanJobs() api_error.ApiErr
+	ReleaseExpiredLeases() api_error.ApiErr
 }
 
 type DefaultJobService struct {
-	repo domain.JobRepository
-	Cfg  *config.AppConfig
+	repo     domain.JobRepository
+	typeRepo domain.JobTypeRepository
+	notifier *JobNotifier
+	Cfg      *config.AppConfig
 }
 
-func NewJobService(cfg *config.AppConfig, repository domain.JobRepository) DefaultJobService {
+func NewJobService(cfg *config.AppConfig, repository domain.JobRepository, typeRepository domain.JobTypeRepository) DefaultJobService {
 	return DefaultJobService{
-		repo: repository,
-		Cfg:  cfg,
+		repo:     repository,
+		typeRepo: typeRepository,
+		notifier: NewJobNotifier(),
+		Cfg:      cfg,
 	}
 }
 
+func (s DefaultJobService) WakeDequeuers() {
+	s.notifier.Notify()
+}
+
 func (s DefaultJobService) GetAllJobs(safReq dto.SortAndFilterRequest) (*[]dto.JobResponse, int, api_error.ApiErr) {
 	jobs, totalCount, err := s.repo.FindAll(safReq)
 	if err != nil {
@@ -49,23 +71,207 @@ func (s DefaultJobService) GetAllJobs(safReq dto.SortAndFilterRequest) (*[]dto.J
 }
 
 func (s DefaultJobService) CreateJob(jobReq dto.CreateUpdateJobRequest) (*dto.JobResponse, api_error.ApiErr) {
+	uniqueKey, err := uniqueKeyFor(s.typeRepo, jobReq)
+	if err != nil {
+		return nil, err
+	}
+	jobReq.UniqueKey = uniqueKey
 	newJob, err := domain.NewJobFromJobRequestDto(jobReq)
 	if err != nil {
 		return nil, err
 	}
+	err = s.checkDependencies(newJob)
+	if err != nil {
+		return nil, err
+	}
+	if newJob.UniqueKey != "" {
+		existing, err := s.findActiveDuplicate(newJob.UniqueKey, jobReq.UniquePolicy)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
 	err = s.repo.Store(*newJob)
 	if err != nil {
+		if newJob.UniqueKey != "" && err.StatusCode() == http.StatusConflict {
+			existing, findErr := s.findActiveDuplicate(newJob.UniqueKey, jobReq.UniquePolicy)
+			if findErr != nil || existing != nil {
+				return existing, findErr
+			}
+		}
 		return nil, err
 	}
+	s.WakeDequeuers()
+	err = s.repo.NotifyJobCreated(newJob.Type)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Could not notify other instances about new job %v", newJob.Id.String()))
+	}
 	response := newJob.ToJobResponseDto()
 	return &response, nil
 }
 
+func (s DefaultJobService) findActiveDuplicate(uniqueKey string, policy string) (*dto.JobResponse, api_error.ApiErr) {
+	existing, err := s.repo.FindActiveByUniqueKey(uniqueKey)
+	if err != nil {
+		if err.StatusCode() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if domain.AsUniquePolicy(policy) == domain.UniqueReturnExisting {
+		response := existing.ToJobResponseDto()
+		response.Deduplicated = true
+		return &response, nil
+	}
+	return nil, api_error.NewProcessingConflictError(fmt.Sprintf("Active job %v with unique key %v already exists", existing.Id.String(), uniqueKey))
+}
+
 func (s DefaultJobService) GetJobById(id string) (*dto.JobResponse, api_error.ApiErr) {
 	job, err := s.repo.FindById(id)
 	if err != nil {
 		return nil, err
 	}
+	if len(job.DependsOn) == 0 {
+		response := job.ToJobResponseDto()
+		return &response, nil
+	}
+	deps, err := s.repo.FindDependencies(job.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+	response := job.ToJobResponseDtoWithDependencies(*deps)
+	return &response, nil
+}
+
+func (s DefaultJobService) CreateChildJobs(parentId string, childReq dto.CreateChildJobsRequest) (*[]dto.JobResponse, api_error.ApiErr) {
+	parent, err := s.repo.FindById(parentId)
+	if err != nil {
+		return nil, err
+	}
+	children := make([]domain.Job, 0)
+	existing := make([]dto.JobResponse, 0)
+	for _, jobReq := range childReq.Children {
+		if jobReq.CorrelationId == "" {
+			jobReq.CorrelationId = parent.CorrelationId
+		}
+		uniqueKey, err := uniqueKeyFor(s.typeRepo, jobReq)
+		if err != nil {
+			return nil, err
+		}
+		jobReq.UniqueKey = uniqueKey
+		child, err := domain.NewJobFromJobRequestDto(jobReq)
+		if err != nil {
+			return nil, err
+		}
+		err = s.checkDependencies(child)
+		if err != nil {
+			return nil, err
+		}
+		if child.UniqueKey != "" {
+			duplicate, err := s.findActiveDuplicate(child.UniqueKey, jobReq.UniquePolicy)
+			if err != nil {
+				return nil, err
+			}
+			if duplicate != nil {
+				existing = append(existing, *duplicate)
+				continue
+			}
+		}
+		child.ParentId = parentId
+		child.AddHistory(fmt.Sprintf("Created as child of job %v", parentId))
+		children = append(children, *child)
+	}
+	if len(children) == 0 {
+		return &existing, nil
+	}
+	_, err = s.repo.AddChildren(parentId, children)
+	if err != nil {
+		return nil, err
+	}
+	s.WakeDequeuers()
+	notified := make(map[string]bool)
+	response := existing
+	for _, child := range children {
+		if !notified[child.Type] {
+			notified[child.Type] = true
+			if err := s.repo.NotifyJobCreated(child.Type); err != nil {
+				logger.Warn(fmt.Sprintf("Could not notify other instances about new child jobs of type %v", child.Type))
+			}
+		}
+		response = append(response, child.ToJobResponseDto())
+	}
+	return &response, nil
+}
+
+func (s DefaultJobService) GetChildJobs(parentId string) (*[]dto.JobResponse, api_error.ApiErr) {
+	children, err := s.repo.FindChildren(parentId)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]dto.JobResponse, 0)
+	for _, child := range *children {
+		response = append(response, child.ToJobResponseDto())
+	}
+	return &response, nil
+}
+
+func (s DefaultJobService) checkDependencies(job *domain.Job) api_error.ApiErr {
+	if len(job.DependsOn) == 0 {
+		return nil
+	}
+	deps, err := s.repo.FindDependencies(job.DependsOn)
+	if err != nil {
+		return err
+	}
+	statuses := make(map[string]domain.JobStatus)
+	for _, dep := range *deps {
+		statuses[dep.Id] = dep.Status
+	}
+	for _, depId := range job.DependsOn {
+		status, found := statuses[depId]
+		if !found {
+			return api_error.NewBadRequestError(fmt.Sprintf("Prerequisite job %v does not exist", depId))
+		}
+		if status == domain.StatusFailed && job.DependencyPolicy == domain.DependencyFail {
+			return api_error.NewBadRequestError(fmt.Sprintf("Prerequisite job %v has already failed", depId))
+		}
+		if status == domain.StatusCancelled && job.DependencyPolicy == domain.DependencyFail {
+			return api_error.NewBadRequestError(fmt.Sprintf("Prerequisite job %v was cancelled", depId))
+		}
+	}
+	return nil
+}
+
+func (s DefaultJobService) GetAllDeadLetters(safReq dto.SortAndFilterRequest) (*[]dto.JobResponse, int, api_error.ApiErr) {
+	jobs, totalCount, err := s.repo.FindAllDeadLetters(safReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	response := make([]dto.JobResponse, 0)
+	for _, job := range *jobs {
+		response = append(response, job.ToJobResponseDto())
+	}
+	return &response, totalCount, nil
+}
+
+func (s DefaultJobService) GetDeadLetterById(id string) (*dto.JobResponse, api_error.ApiErr) {
+	job, err := s.repo.FindDeadLetterById(id)
+	if err != nil {
+		return nil, err
+	}
+	response := job.ToJobResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobService) RequeueDeadLetter(id string) (*dto.JobResponse, api_error.ApiErr) {
+	job, err := s.repo.RequeueDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+	s.WakeDequeuers()
+	err = s.repo.NotifyJobCreated(job.Type)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Could not notify other instances about requeued job %v", id))
+	}
 	response := job.ToJobResponseDto()
 	return &response, nil
 }
@@ -82,8 +288,49 @@ func (s DefaultJobService) DeleteJobById(id string) api_error.ApiErr {
 	return nil
 }
 
-func (s DefaultJobService) Dequeue(dqReq dto.DequeueRequest) (*dto.JobResponse, api_error.ApiErr) {
-	job, err := s.repo.Dequeue(dqReq.Type)
+func (s DefaultJobService) Dequeue(ctx context.Context, dqReq dto.DequeueRequest) (*[]dto.JobResponse, api_error.ApiErr) {
+	deadline := time.Now().Add(time.Duration(dqReq.Wait) * time.Second)
+	for {
+		wakeUp := s.notifier.Wait()
+		if ctx.Err() != nil {
+			logger.Info(fmt.Sprintf("Dequeue request for type %v cancelled by worker", strings.Join(dqReq.AllTypes(), ", ")))
+			response := make([]dto.JobResponse, 0)
+			return &response, nil
+		}
+		jobs, err := s.repo.Dequeue(dqReq)
+		if err == nil {
+			response := make([]dto.JobResponse, 0)
+			for _, job := range *jobs {
+				response = append(response, job.ToJobResponseDto())
+			}
+			return &response, nil
+		}
+		if err.StatusCode() != http.StatusNotFound {
+			return nil, err
+		}
+		waitTime := time.Until(deadline)
+		if waitTime <= 0 {
+			response := make([]dto.JobResponse, 0)
+			return &response, nil
+		}
+		pollInterval := time.Duration(s.Cfg.Dequeue.PollIntervalSeconds) * time.Second
+		if pollInterval > 0 && pollInterval < waitTime {
+			waitTime = pollInterval
+		}
+		select {
+		case <-ctx.Done():
+		case <-wakeUp:
+		case <-time.After(waitTime):
+		}
+	}
+}
+
+func (s DefaultJobService) Heartbeat(id string, hbReq dto.HeartbeatRequest) (*dto.JobResponse, api_error.ApiErr) {
+	_, err := s.GetJobById(id)
+	if err != nil {
+		return nil, api_error.NewNotFoundError(fmt.Sprintf("Job with id %v does not exist", id))
+	}
+	job, err := s.repo.Heartbeat(id, hbReq)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +339,19 @@ func (s DefaultJobService) Dequeue(dqReq dto.DequeueRequest) (*dto.JobResponse,
 }
 
 func (s DefaultJobService) UpdateJob(id string, jobReq dto.CreateUpdateJobRequest) (*dto.JobResponse, api_error.ApiErr) {
-	_, err := s.GetJobById(id)
+	oldJob, err := s.repo.FindById(id)
 	if err != nil {
 		return nil, api_error.NewNotFoundError(fmt.Sprintf("Job with id %v does not exist", id))
 	}
+	if jobReq.UniqueKey == "" {
+		oldReq := oldJob.ToJobRequestDto()
+		oldReq.UniqueKey = ""
+		uniqueKey, err := uniqueKeyFor(s.typeRepo, domain.MergeJobRequests(oldReq, jobReq))
+		if err != nil {
+			return nil, err
+		}
+		jobReq.UniqueKey = uniqueKey
+	}
 	newJob, err := s.repo.Update(id, jobReq)
 	if err != nil {
 		return nil, err
@@ -115,13 +371,36 @@ func (s DefaultJobService) SetStatusById(id string, statusReq dto.UpdateJobStatu
 	} else {
 		message = fmt.Sprintf("Job status changed. New status: %v; %v", statusReq.Status, statusReq.Message)
 	}
-	err = s.repo.SetStatusById(id, statusReq.Status, message)
+	err = s.repo.SetStatusById(id, statusReq, message)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (s DefaultJobService) CancelJob(id string) (*dto.JobResponse, api_error.ApiErr) {
+	job, err := s.repo.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == domain.StatusCancelled {
+		logger.Info(fmt.Sprintf("Cancelled job %v", id))
+	} else {
+		logger.Info(fmt.Sprintf("Requested cancellation of job %v from worker %v", id, job.LeaseOwner))
+	}
+	response := job.ToJobResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobService) SetProgressById(id string, progReq dto.UpdateJobProgressRequest) (*dto.JobResponse, api_error.ApiErr) {
+	job, err := s.repo.SetProgressById(id, progReq)
+	if err != nil {
+		return nil, err
+	}
+	response := job.ToJobResponseDto()
+	return &response, nil
+}
+
 func (s DefaultJobService) SetHistoryById(id string, historyReq dto.UpdateJobHistoryRequest) api_error.ApiErr {
 	_, err := s.GetJobById(id)
 	if err != nil {
@@ -134,6 +413,18 @@ func (s DefaultJobService) SetHistoryById(id string, historyReq dto.UpdateJobHis
 	return nil
 }
 
+func (s DefaultJobService) GetJobHistory(id string, limit int, offset int) (*[]dto.JobEventResponse, int, api_error.ApiErr) {
+	events, totalCount, err := s.repo.FindEvents(id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	response := make([]dto.JobEventResponse, 0)
+	for _, event := range *events {
+		response = append(response, event.ToJobEventResponseDto())
+	}
+	return &response, totalCount, nil
+}
+
 func (s DefaultJobService) DeleteAllJobs() api_error.ApiErr {
 	err := s.repo.DeleteAllJobs()
 	if err != nil {
@@ -149,3 +440,15 @@ func (s DefaultJobService) CleanJobs() api_error.ApiErr {
 	}
 	return nil
 }
+
+func (s DefaultJobService) ReleaseExpiredLeases() api_error.ApiErr {
+	released, err := s.repo.ReleaseExpiredLeases()
+	if err != nil {
+		return err
+	}
+	if released > 0 {
+		s.WakeDequeuers()
+		logger.Info(fmt.Sprintf("Returned %d jobs with expired leases to the queue", released))
+	}
+	return nil
+}
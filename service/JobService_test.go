@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/johannes-kuhfuss/jobsvc/config"
@@ -11,6 +13,7 @@ import (
 	"github.com/johannes-kuhfuss/jobsvc/dto"
 	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
 	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/lib/pq"
 	"github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,7 +28,7 @@ var (
 func setupJob(t *testing.T) func() {
 	jobCtrl = gomock.NewController(t)
 	mockJobRepo = domain.NewMockJobRepository(jobCtrl)
-	jobService = NewJobService(&cfg, mockJobRepo)
+	jobService = NewJobService(&cfg, mockJobRepo, nil)
 	return func() {
 		jobService = nil
 		jobCtrl.Finish()
@@ -112,6 +115,171 @@ func Test_CreateJob_Returns_InternalServerError(t *testing.T) {
 	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
 }
 
+func Test_CreateJob_MissingPrerequisite_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	prereqId := ksuid.New().String()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "proxy",
+		DependsOn: []string{prereqId},
+	}
+	mockJobRepo.EXPECT().FindDependencies([]string{prereqId}).Return(&[]realdomain.JobDependency{}, nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Prerequisite job %v does not exist", prereqId), err.Message())
+}
+
+func Test_CreateJob_FailedPrerequisite_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	prereqId := ksuid.New().String()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "proxy",
+		DependsOn: []string{prereqId},
+	}
+	deps := []realdomain.JobDependency{{Id: prereqId, Status: realdomain.StatusFailed}}
+	mockJobRepo.EXPECT().FindDependencies([]string{prereqId}).Return(&deps, nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, fmt.Sprintf("Prerequisite job %v has already failed", prereqId), err.Message())
+}
+
+func Test_CreateJob_WithPrerequisite_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	prereqId := ksuid.New().String()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "proxy",
+		DependsOn: []string{prereqId},
+	}
+	deps := []realdomain.JobDependency{{Id: prereqId, Status: realdomain.StatusRunning}}
+	mockJobRepo.EXPECT().FindDependencies([]string{prereqId}).Return(&deps, nil)
+	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("proxy").Return(nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{prereqId}, result.DependsOn)
+}
+
+func Test_CreateJob_ActiveDuplicate_Returns_ConflictError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		UniqueKey: "encode-a.mxf",
+	}
+	existing, _ := realdomain.NewJob("job 1", "encoding")
+	mockJobRepo.EXPECT().FindActiveByUniqueKey("encode-a.mxf").Return(existing, nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Active job %v with unique key encode-a.mxf already exists", existing.Id.String()), err.Message())
+}
+
+func Test_CreateJob_ActiveDuplicateReturnExisting_Returns_ExistingJob(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:         "encoding",
+		UniqueKey:    "encode-a.mxf",
+		UniquePolicy: "return_existing",
+	}
+	existing, _ := realdomain.NewJob("job 1", "encoding")
+	mockJobRepo.EXPECT().FindActiveByUniqueKey("encode-a.mxf").Return(existing, nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, existing.Id.String(), result.Id)
+	assert.True(t, result.Deduplicated)
+}
+
+func Test_CreateJob_NoActiveDuplicate_Stores_Job(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		UniqueKey: "encode-a.mxf",
+	}
+	mockJobRepo.EXPECT().FindActiveByUniqueKey("encode-a.mxf").Return(nil, api_error.NewNotFoundError("No active job found for unique key encode-a.mxf"))
+	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encode-a.mxf", result.UniqueKey)
+	assert.False(t, result.Deduplicated)
+}
+
+func Test_CreateJob_ConcurrentDuplicate_Returns_ExistingJob(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:         "encoding",
+		UniqueKey:    "encode-a.mxf",
+		UniquePolicy: "return_existing",
+	}
+	existing, _ := realdomain.NewJob("job 1", "encoding")
+	gomock.InOrder(
+		mockJobRepo.EXPECT().FindActiveByUniqueKey("encode-a.mxf").Return(nil, api_error.NewNotFoundError("No active job found for unique key encode-a.mxf")),
+		mockJobRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Active job with unique key encode-a.mxf already exists")),
+		mockJobRepo.EXPECT().FindActiveByUniqueKey("encode-a.mxf").Return(existing, nil),
+	)
+
+	result, err := jobService.CreateJob(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, existing.Id.String(), result.Id)
+	assert.True(t, result.Deduplicated)
+}
+
+func Test_CreateJob_JobTypeUniqueFields_Derives_UniqueKey(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	jobService = NewJobService(&cfg, mockJobRepo, mockTypeRepo)
+	jobType := newTestJobType()
+	jobType.UniqueFields = pq.StringArray{"type", "source"}
+	uniqueKey := `type="encoding"|source="a.mxf"`
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(jobType, nil)
+	mockJobRepo.EXPECT().FindActiveByUniqueKey(uniqueKey).Return(nil, api_error.NewNotFoundError("No active job found"))
+	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	result, err := jobService.CreateJob(dto.CreateUpdateJobRequest{Type: "encoding", Source: "a.mxf"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, uniqueKey, result.UniqueKey)
+}
+
+func Test_CreateJob_UnknownJobType_Stores_JobWithoutUniqueKey(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	jobService = NewJobService(&cfg, mockJobRepo, mockTypeRepo)
+	mockTypeRepo.EXPECT().FindByName("encodng").Return(nil, api_error.NewNotFoundError("No job type found for name encodng"))
+	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encodng").Return(nil)
+
+	result, err := jobService.CreateJob(dto.CreateUpdateJobRequest{Type: "encodng"})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "", result.UniqueKey)
+}
+
 func Test_CreateJob_Returns_NoError(t *testing.T) {
 	teardown := setupJob(t)
 	defer teardown()
@@ -120,6 +288,7 @@ func Test_CreateJob_Returns_NoError(t *testing.T) {
 		Type: "encoding",
 	}
 	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
 
 	result, err := jobService.CreateJob(jobReq)
 
@@ -160,6 +329,225 @@ func Test_GetJobById_Returns_NoError(t *testing.T) {
 	assert.Equal(t, result, &jobResp)
 }
 
+func Test_GetJobById_WithPrerequisites_Returns_DependencyState(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	prereq1, _ := realdomain.NewJob("encode", "encoding")
+	prereq2, _ := realdomain.NewJob("proxy", "proxy")
+	newJob, _ := realdomain.NewJob("techqc", "qc")
+	newJob.DependsOn = []string{prereq1.Id.String(), prereq2.Id.String()}
+	deps := []realdomain.JobDependency{
+		{Id: prereq1.Id.String(), Status: realdomain.StatusFinished},
+		{Id: prereq2.Id.String(), Status: realdomain.StatusRunning},
+	}
+	id := newJob.Id.String()
+	mockJobRepo.EXPECT().FindById(id).Return(newJob, nil)
+	mockJobRepo.EXPECT().FindDependencies([]string(newJob.DependsOn)).Return(&deps, nil)
+
+	result, err := jobService.GetJobById(id)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "waiting", result.DependencyState)
+	assert.EqualValues(t, 2, len(result.Dependencies))
+	assert.EqualValues(t, "running", result.Dependencies[1].Status)
+}
+
+func Test_CancelJob_RepoError_Returns_Error(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewProcessingConflictError(fmt.Sprintf("Job with id %v is already finished and cannot be cancelled", id))
+	mockJobRepo.EXPECT().Cancel(id).Return(nil, apiError)
+
+	result, err := jobService.CancelJob(id)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+}
+
+func Test_CancelJob_NoError_Returns_Job(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	job, _ := realdomain.NewJob("Job 1", "encoding")
+	job.Status = realdomain.StatusRunning
+	job.LeaseOwner = "worker-1"
+	job.CancelRequested = true
+	mockJobRepo.EXPECT().Cancel(job.Id.String()).Return(job, nil)
+
+	result, err := jobService.CancelJob(job.Id.String())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "running", result.Status)
+	assert.EqualValues(t, true, result.CancelRequested)
+}
+
+func Test_SetProgressById_NoError_Returns_Job(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	job, _ := realdomain.NewJob("Job 1", "encoding")
+	job.Progress = 60
+	var progress int32 = 60
+	progReq := dto.UpdateJobProgressRequest{Progress: &progress}
+	mockJobRepo.EXPECT().SetProgressById(job.Id.String(), progReq).Return(job, nil)
+
+	result, err := jobService.SetProgressById(job.Id.String(), progReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 60, result.Progress)
+}
+
+func Test_CreateChildJobs_ParentNotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	parentId := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No job found for id %v", parentId))
+	mockJobRepo.EXPECT().FindById(parentId).Return(nil, apiError)
+	childReq := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{{Type: "encoding"}},
+	}
+
+	result, err := jobService.CreateChildJobs(parentId, childReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_CreateChildJobs_NoError_Returns_Children(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	parent, _ := realdomain.NewJob("package", "packaging")
+	parent.CorrelationId = "order-4711"
+	parentId := parent.Id.String()
+	childReq := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{
+			{Name: "1080p", Type: "encoding"},
+			{Name: "720p", Type: "encoding"},
+		},
+	}
+	mockJobRepo.EXPECT().FindById(parentId).Return(parent, nil)
+	mockJobRepo.EXPECT().AddChildren(parentId, gomock.Len(2)).Return(parent, nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil).Times(1)
+
+	result, err := jobService.CreateChildJobs(parentId, childReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(*result))
+	assert.EqualValues(t, parentId, (*result)[0].ParentId)
+	assert.EqualValues(t, "order-4711", (*result)[1].CorrelationId)
+}
+
+func Test_CreateChildJobs_JobTypeUniqueFields_Rejects_ActiveDuplicate(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	jobService = NewJobService(&cfg, mockJobRepo, mockTypeRepo)
+	parent, _ := realdomain.NewJob("package", "packaging")
+	parentId := parent.Id.String()
+	existing, _ := realdomain.NewJob("1080p", "encoding")
+	jobType := newTestJobType()
+	jobType.UniqueFields = pq.StringArray{"type", "source"}
+	mockJobRepo.EXPECT().FindById(parentId).Return(parent, nil)
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(jobType, nil)
+	mockJobRepo.EXPECT().FindActiveByUniqueKey(`type="encoding"|source="a.mxf"`).Return(existing, nil)
+	childReq := dto.CreateChildJobsRequest{
+		Children: []dto.CreateUpdateJobRequest{{Name: "1080p", Type: "encoding", Source: "a.mxf"}},
+	}
+
+	result, err := jobService.CreateChildJobs(parentId, childReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+}
+
+func Test_GetChildJobs_NoError_Returns_Children(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	parentId := ksuid.New().String()
+	child, _ := realdomain.NewJob("1080p", "encoding")
+	child.ParentId = parentId
+	children := []realdomain.Job{*child}
+	mockJobRepo.EXPECT().FindChildren(parentId).Return(&children, nil)
+
+	result, err := jobService.GetChildJobs(parentId)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*result))
+	assert.EqualValues(t, child.Id.String(), (*result)[0].Id)
+}
+
+func Test_GetAllDeadLetters_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	job1, _ := realdomain.NewJob("job 1", "encoding")
+	jobs := make([]realdomain.Job, 0)
+	jobs = append(jobs, *job1)
+	jobResult := make([]dto.JobResponse, 0)
+	jobResult = append(jobResult, job1.ToJobResponseDto())
+	safReq := dto.SortAndFilterRequest{
+		Sorts: dto.SortBy{
+			Field: "id",
+			Dir:   "DESC",
+		},
+	}
+	mockJobRepo.EXPECT().FindAllDeadLetters(safReq).Return(&jobs, len(jobs), nil)
+
+	result, totalCount, err := jobService.GetAllDeadLetters(safReq)
+
+	assert.NotNil(t, result)
+	assert.Nil(t, err)
+	assert.Equal(t, result, &jobResult)
+	assert.EqualValues(t, len(jobs), totalCount)
+}
+
+func Test_GetDeadLetterById_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No dead-lettered job found for id %v", id))
+	mockJobRepo.EXPECT().FindDeadLetterById(id).Return(nil, apiError)
+
+	result, err := jobService.GetDeadLetterById(id)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
+	assert.EqualValues(t, apiError.Message(), err.Message())
+}
+
+func Test_RequeueDeadLetter_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No dead-lettered job found for id %v", id))
+	mockJobRepo.EXPECT().RequeueDeadLetter(id).Return(nil, apiError)
+
+	result, err := jobService.RequeueDeadLetter(id)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
+	assert.EqualValues(t, apiError.Message(), err.Message())
+}
+
+func Test_RequeueDeadLetter_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	newJob, _ := realdomain.NewJob("job 1", "encoding")
+	jobResp := newJob.ToJobResponseDto()
+	id := newJob.Id.String()
+	mockJobRepo.EXPECT().RequeueDeadLetter(id).Return(newJob, nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	result, err := jobService.RequeueDeadLetter(id)
+
+	assert.NotNil(t, result)
+	assert.Nil(t, err)
+	assert.Equal(t, result, &jobResp)
+}
+
 func Test_DeleteJobById_Returns_NotFoundError(t *testing.T) {
 	teardown := setupJob(t)
 	defer teardown()
@@ -203,18 +591,34 @@ func Test_DeleteJobById_Returns_NoError(t *testing.T) {
 	assert.Nil(t, err)
 }
 
-func Test_Dequeue_Returns_NotFoundError(t *testing.T) {
+func Test_Dequeue_NoJob_Returns_EmptyList(t *testing.T) {
 	teardown := setupJob(t)
 	defer teardown()
 	apiError := api_error.NewNotFoundError("No next job found")
 	dqReq := dto.DequeueRequest{
 		Type: "Encoding",
 	}
-	mockJobRepo.EXPECT().Dequeue(dqReq.Type).Return(nil, apiError)
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError)
 
-	job, err := jobService.Dequeue(dqReq)
+	jobs, err := jobService.Dequeue(context.Background(), dqReq)
 
-	assert.Nil(t, job)
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(*jobs))
+}
+
+func Test_Dequeue_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("Database error dequeuing job", nil)
+	dqReq := dto.DequeueRequest{
+		Type: "Encoding",
+	}
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError)
+
+	jobs, err := jobService.Dequeue(context.Background(), dqReq)
+
+	assert.Nil(t, jobs)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, apiError.Message(), err.Message())
 	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
@@ -227,14 +631,120 @@ func Test_Dequeue_Returns_NoError(t *testing.T) {
 	dqReq := dto.DequeueRequest{
 		Type: "Encoding",
 	}
-	mockJobRepo.EXPECT().Dequeue(dqReq.Type).Return(nextJob, nil)
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(&[]realdomain.Job{*nextJob}, nil)
 
-	job, err := jobService.Dequeue(dqReq)
+	jobs, err := jobService.Dequeue(context.Background(), dqReq)
 
-	assert.NotNil(t, job)
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, len(*jobs))
+	assert.EqualValues(t, nextJob.Name, (*jobs)[0].Name)
+	assert.EqualValues(t, nextJob.Type, (*jobs)[0].Type)
+}
+
+func Test_Dequeue_WithCount_NoJob_Returns_EmptyList(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No next job found")
+	dqReq := dto.DequeueRequest{
+		Type:  "Encoding",
+		Count: 5,
+	}
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError)
+
+	jobs, err := jobService.Dequeue(context.Background(), dqReq)
+
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(*jobs))
+}
+
+func Test_Dequeue_WithWait_NoJob_Returns_EmptyListAfterTimeout(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No next job found")
+	dqReq := dto.DequeueRequest{
+		Type: "Encoding",
+		Wait: 1,
+	}
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError).MinTimes(1)
+	start := time.Now()
+
+	jobs, err := jobService.Dequeue(context.Background(), dqReq)
+
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(*jobs))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func Test_Dequeue_WithWait_JobCreated_Returns_Job(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No next job found")
+	nextJob, _ := realdomain.NewJob("job 1", "encoding")
+	dqReq := dto.DequeueRequest{
+		Type: "Encoding",
+		Wait: 10,
+	}
+	gomock.InOrder(
+		mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError),
+		mockJobRepo.EXPECT().Dequeue(dqReq).Return(&[]realdomain.Job{*nextJob}, nil),
+	)
+	svc := jobService.(DefaultJobService)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		svc.WakeDequeuers()
+	}()
+	start := time.Now()
+
+	jobs, err := svc.Dequeue(context.Background(), dqReq)
+
+	assert.NotNil(t, jobs)
 	assert.Nil(t, err)
-	assert.EqualValues(t, nextJob.Name, job.Name)
-	assert.EqualValues(t, nextJob.Type, job.Type)
+	assert.EqualValues(t, nextJob.Id.String(), (*jobs)[0].Id)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_Dequeue_WorkerGone_DoesNot_LeaseJob(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	dqReq := dto.DequeueRequest{
+		Type: "Encoding",
+	}
+	mockJobRepo.EXPECT().Dequeue(gomock.Any()).Times(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	jobs, err := jobService.Dequeue(ctx, dqReq)
+
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(*jobs))
+}
+
+func Test_Dequeue_WithWait_WorkerGone_Returns_EmptyList(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No next job found")
+	dqReq := dto.DequeueRequest{
+		Type: "Encoding",
+		Wait: 10,
+	}
+	mockJobRepo.EXPECT().Dequeue(dqReq).Return(nil, apiError).Times(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+
+	jobs, err := jobService.Dequeue(ctx, dqReq)
+
+	assert.NotNil(t, jobs)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 0, len(*jobs))
+	assert.Less(t, time.Since(start), 5*time.Second)
 }
 
 func Test_UpdateJob_Returns_NotFoundError(t *testing.T) {
@@ -288,6 +798,30 @@ func Test_UpdateJob_Returns_NoError(t *testing.T) {
 	assert.EqualValues(t, newJob.Type, job.Type)
 }
 
+func Test_UpdateJob_JobTypeUniqueFields_Recomputes_UniqueKey(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	jobService = NewJobService(&cfg, mockJobRepo, mockTypeRepo)
+	oldJob, _ := realdomain.NewJob("job 1", "encoding")
+	oldJob.Source = "a.mxf"
+	oldJob.UniqueKey = `type="encoding"|source="a.mxf"`
+	id := oldJob.Id.String()
+	jobType := newTestJobType()
+	jobType.UniqueFields = pq.StringArray{"type", "source"}
+	updReq := dto.CreateUpdateJobRequest{Source: "b.mxf"}
+	keyedReq := updReq
+	keyedReq.UniqueKey = `type="encoding"|source="b.mxf"`
+	mockJobRepo.EXPECT().FindById(id).Return(oldJob, nil)
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(jobType, nil)
+	mockJobRepo.EXPECT().Update(id, keyedReq).Return(oldJob, nil)
+
+	job, err := jobService.UpdateJob(id, updReq)
+
+	assert.NotNil(t, job)
+	assert.Nil(t, err)
+}
+
 func Test_SetStatusById_Returns_NotFoundError(t *testing.T) {
 	teardown := setupJob(t)
 	defer teardown()
@@ -318,7 +852,7 @@ func Test_SetStatusById_Returns_InternalServerError(t *testing.T) {
 	msg := fmt.Sprintf("Job status changed. New status: %v", updReq.Status)
 	apiError := api_error.NewInternalServerError("Database error", nil)
 	mockJobRepo.EXPECT().FindById(id).Return(newJob, nil)
-	mockJobRepo.EXPECT().SetStatusById(id, updReq.Status, msg).Return(apiError)
+	mockJobRepo.EXPECT().SetStatusById(id, updReq, msg).Return(apiError)
 
 	err := jobService.SetStatusById(id, updReq)
 
@@ -338,13 +872,48 @@ func Test_SetStatusById_Returns_NoError(t *testing.T) {
 	}
 	msg := fmt.Sprintf("Job status changed. New status: %v; %v", updReq.Status, updReq.Message)
 	mockJobRepo.EXPECT().FindById(id).Return(newJob, nil)
-	mockJobRepo.EXPECT().SetStatusById(id, updReq.Status, msg).Return(nil)
+	mockJobRepo.EXPECT().SetStatusById(id, updReq, msg).Return(nil)
 
 	err := jobService.SetStatusById(id, updReq)
 
 	assert.Nil(t, err)
 }
 
+func Test_GetJobHistory_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No history found for job id %v", id))
+	mockJobRepo.EXPECT().FindEvents(id, 10, 0).Return(nil, 0, apiError)
+
+	events, totalCount, err := jobService.GetJobHistory(id, 10, 0)
+
+	assert.Nil(t, events)
+	assert.EqualValues(t, 0, totalCount)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
+}
+
+func Test_GetJobHistory_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	events := []realdomain.JobEvent{
+		{Id: 1, JobId: id, EventType: realdomain.EventCreated, Actor: realdomain.ActorSystem, Message: "Job created", NewStatus: "created"},
+		{Id: 2, JobId: id, EventType: realdomain.EventStatusChanged, Actor: "worker 1", Message: "Dequeuing job for processing", OldStatus: "created", NewStatus: "running"},
+	}
+	mockJobRepo.EXPECT().FindEvents(id, 10, 0).Return(&events, 2, nil)
+
+	result, totalCount, err := jobService.GetJobHistory(id, 10, 0)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, totalCount)
+	assert.EqualValues(t, 2, len(*result))
+	assert.EqualValues(t, "status_changed", (*result)[1].EventType)
+	assert.EqualValues(t, "running", (*result)[1].NewStatus)
+}
+
 func Test_SetHistoryById_Returns_NotFoundError(t *testing.T) {
 	teardown := setupJob(t)
 	defer teardown()
@@ -442,3 +1011,75 @@ func Test_CleanJobs_Returns_NoError(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func Test_ReleaseExpiredLeases_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	apiError := api_error.NewInternalServerError("database error", nil)
+	mockJobRepo.EXPECT().ReleaseExpiredLeases().Return(0, apiError)
+
+	err := jobService.ReleaseExpiredLeases()
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
+}
+
+func Test_ReleaseExpiredLeases_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	mockJobRepo.EXPECT().ReleaseExpiredLeases().Return(2, nil)
+
+	err := jobService.ReleaseExpiredLeases()
+
+	assert.Nil(t, err)
+}
+
+func Test_Heartbeat_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("Job with id %v does not exist", id))
+	mockJobRepo.EXPECT().FindById(id).Return(nil, apiError)
+
+	job, err := jobService.Heartbeat(id, dto.HeartbeatRequest{Worker: "worker 1"})
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+	assert.EqualValues(t, apiError.StatusCode(), err.StatusCode())
+}
+
+func Test_Heartbeat_Returns_ConflictError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	oldJob, _ := realdomain.NewJob("job 1", "encoding")
+	id := oldJob.Id.String()
+	hbReq := dto.HeartbeatRequest{Worker: "worker 1"}
+	apiError := api_error.NewProcessingConflictError("not leased")
+	mockJobRepo.EXPECT().FindById(id).Return(oldJob, nil)
+	mockJobRepo.EXPECT().Heartbeat(id, hbReq).Return(nil, apiError)
+
+	job, err := jobService.Heartbeat(id, hbReq)
+
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, apiError.Message(), err.Message())
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+}
+
+func Test_Heartbeat_Returns_NoError(t *testing.T) {
+	teardown := setupJob(t)
+	defer teardown()
+	oldJob, _ := realdomain.NewJob("job 1", "encoding")
+	id := oldJob.Id.String()
+	hbReq := dto.HeartbeatRequest{Worker: "worker 1"}
+	mockJobRepo.EXPECT().FindById(id).Return(oldJob, nil)
+	mockJobRepo.EXPECT().Heartbeat(id, hbReq).Return(oldJob, nil)
+
+	job, err := jobService.Heartbeat(id, hbReq)
+
+	assert.NotNil(t, job)
+	assert.Nil(t, err)
+	assert.EqualValues(t, id, job.Id)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockJobTemplateService.go -package=service github.com/johannes-kuhfuss/jobsvc/service JobTemplateService
+type JobTemplateService interface {
+	CreateJobTemplate(dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr)
+	GetAllJobTemplates() (*[]dto.JobTemplateResponse, api_error.ApiErr)
+	GetJobTemplateByName(string, int32) (*dto.JobTemplateResponse, api_error.ApiErr)
+	GetJobTemplateVersions(string) (*[]dto.JobTemplateResponse, api_error.ApiErr)
+	UpdateJobTemplate(string, dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr)
+	DeleteJobTemplateByName(string) api_error.ApiErr
+	ResolveJobRequest(dto.CreateUpdateJobRequest) (*dto.CreateUpdateJobRequest, api_error.ApiErr)
+}
+
+type DefaultJobTemplateService struct {
+	repo domain.JobTemplateRepository
+	Cfg  *config.AppConfig
+}
+
+func NewJobTemplateService(cfg *config.AppConfig, repository domain.JobTemplateRepository) DefaultJobTemplateService {
+	return DefaultJobTemplateService{
+		repo: repository,
+		Cfg:  cfg,
+	}
+}
+
+func (s DefaultJobTemplateService) CreateJobTemplate(tmplReq dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	newTemplate, err := domain.NewJobTemplateFromRequestDto(tmplReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Store(*newTemplate)
+	if err != nil {
+		if err.StatusCode() == http.StatusConflict {
+			return nil, api_error.NewProcessingConflictError(fmt.Sprintf("Job template %v already exists", newTemplate.Name))
+		}
+		return nil, err
+	}
+	response := newTemplate.ToJobTemplateResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTemplateService) GetAllJobTemplates() (*[]dto.JobTemplateResponse, api_error.ApiErr) {
+	templates, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return toJobTemplateResponses(templates), nil
+}
+
+func (s DefaultJobTemplateService) GetJobTemplateByName(name string, version int32) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	template, err := s.repo.FindByName(name, version)
+	if err != nil {
+		return nil, err
+	}
+	response := template.ToJobTemplateResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTemplateService) GetJobTemplateVersions(name string) (*[]dto.JobTemplateResponse, api_error.ApiErr) {
+	templates, err := s.repo.FindVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(*templates) == 0 {
+		return nil, api_error.NewNotFoundError(fmt.Sprintf("No job template found for name %v", name))
+	}
+	return toJobTemplateResponses(templates), nil
+}
+
+func (s DefaultJobTemplateService) UpdateJobTemplate(name string, tmplReq dto.CreateUpdateJobTemplateRequest) (*dto.JobTemplateResponse, api_error.ApiErr) {
+	template, err := s.repo.FindByName(name, 0)
+	if err != nil {
+		return nil, err
+	}
+	nextTemplate, err := template.NextVersion(tmplReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Store(*nextTemplate)
+	if err != nil {
+		return nil, err
+	}
+	response := nextTemplate.ToJobTemplateResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTemplateService) DeleteJobTemplateByName(name string) api_error.ApiErr {
+	_, err := s.repo.FindByName(name, 0)
+	if err != nil {
+		return api_error.NewNotFoundError(fmt.Sprintf("Job template %v does not exist", name))
+	}
+	return s.repo.DeleteByName(name)
+}
+
+func (s DefaultJobTemplateService) ResolveJobRequest(jobReq dto.CreateUpdateJobRequest) (*dto.CreateUpdateJobRequest, api_error.ApiErr) {
+	if jobReq.Template == "" {
+		return &jobReq, nil
+	}
+	template, err := s.repo.FindByName(jobReq.Template, jobReq.TemplateVersion)
+	if err != nil {
+		if err.StatusCode() == http.StatusNotFound {
+			return nil, api_error.NewBadRequestError(err.Message())
+		}
+		return nil, err
+	}
+	return template.Apply(jobReq)
+}
+
+func toJobTemplateResponses(templates *[]domain.JobTemplate) *[]dto.JobTemplateResponse {
+	response := make([]dto.JobTemplateResponse, 0)
+	for _, template := range *templates {
+		response = append(response, template.ToJobTemplateResponseDto())
+	}
+	return &response
+}
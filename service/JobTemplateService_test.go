@@ -0,0 +1,150 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realdomain "github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockTemplateRepo *domain.MockJobTemplateRepository
+	templateService  DefaultJobTemplateService
+)
+
+func setupJobTemplate(t *testing.T) func() {
+	teardown := setupJob(t)
+	mockTemplateRepo = domain.NewMockJobTemplateRepository(jobCtrl)
+	templateService = NewJobTemplateService(&cfg, mockTemplateRepo)
+	return teardown
+}
+
+func newTestJobTemplate() *realdomain.JobTemplate {
+	return &realdomain.JobTemplate{
+		Name:        "encode_hd",
+		Version:     2,
+		JobTemplate: `{"type":"encoding","sub_type":"h264","priority":"high"}`,
+	}
+}
+
+func Test_CreateJobTemplate_Exists_Returns_ConflictError(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	tmplReq := dto.CreateUpdateJobTemplateRequest{
+		Name: "encode_hd",
+		Job:  &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	mockTemplateRepo.EXPECT().Store(gomock.Any()).Return(api_error.NewProcessingConflictError("Job template encode_hd version 1 already exists"))
+
+	result, err := templateService.CreateJobTemplate(tmplReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.StatusCode())
+	assert.EqualValues(t, "Job template encode_hd already exists", err.Message())
+}
+
+func Test_CreateJobTemplate_NoError_Returns_FirstVersion(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	tmplReq := dto.CreateUpdateJobTemplateRequest{
+		Name: "encode_hd",
+		Job:  &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	mockTemplateRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	result, err := templateService.CreateJobTemplate(tmplReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, result.Version)
+	assert.EqualValues(t, "encoding", result.Job.Type)
+}
+
+func Test_UpdateJobTemplate_NoError_Stores_NextVersion(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	tmplReq := dto.CreateUpdateJobTemplateRequest{
+		Job: &dto.CreateUpdateJobRequest{Priority: "low"},
+	}
+	mockTemplateRepo.EXPECT().FindByName("encode_hd", int32(0)).Return(newTestJobTemplate(), nil)
+	mockTemplateRepo.EXPECT().Store(gomock.Any()).DoAndReturn(func(template realdomain.JobTemplate) api_error.ApiErr {
+		assert.EqualValues(t, 3, template.Version)
+		return nil
+	})
+
+	result, err := templateService.UpdateJobTemplate("encode_hd", tmplReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, result.Version)
+	assert.EqualValues(t, "low", result.Job.Priority)
+	assert.EqualValues(t, "h264", result.Job.SubType)
+}
+
+func Test_GetJobTemplateVersions_NoVersions_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	mockTemplateRepo.EXPECT().FindVersions("encode_hd").Return(&[]realdomain.JobTemplate{}, nil)
+
+	result, err := templateService.GetJobTemplateVersions("encode_hd")
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_DeleteJobTemplateByName_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	mockTemplateRepo.EXPECT().FindByName("encode_hd", int32(0)).Return(nil, api_error.NewNotFoundError("No job template found for name encode_hd"))
+
+	err := templateService.DeleteJobTemplateByName("encode_hd")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, "Job template encode_hd does not exist", err.Message())
+}
+
+func Test_ResolveJobRequest_NoTemplate_Returns_Request(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Type: "encoding"}
+
+	result, err := templateService.ResolveJobRequest(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, jobReq, *result)
+}
+
+func Test_ResolveJobRequest_UnknownTemplate_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Template: "encode_hd", TemplateVersion: 7}
+	mockTemplateRepo.EXPECT().FindByName("encode_hd", int32(7)).Return(nil, api_error.NewNotFoundError("No job template found for name encode_hd and version 7"))
+
+	result, err := templateService.ResolveJobRequest(jobReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "No job template found for name encode_hd and version 7", err.Message())
+}
+
+func Test_ResolveJobRequest_Template_Returns_MergedRequest(t *testing.T) {
+	teardown := setupJobTemplate(t)
+	defer teardown()
+	jobReq := dto.CreateUpdateJobRequest{Template: "encode_hd", Name: "Trailer", Priority: "low"}
+	mockTemplateRepo.EXPECT().FindByName("encode_hd", int32(0)).Return(newTestJobTemplate(), nil)
+
+	result, err := templateService.ResolveJobRequest(jobReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encoding", result.Type)
+	assert.EqualValues(t, "h264", result.SubType)
+	assert.EqualValues(t, "Trailer", result.Name)
+	assert.EqualValues(t, "low", result.Priority)
+	assert.EqualValues(t, 2, result.TemplateVersion)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockJobTypeService.go -package=service github.com/johannes-kuhfuss/jobsvc/service JobTypeService
+type JobTypeService interface {
+	CreateJobType(dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr)
+	GetAllJobTypes() (*[]dto.JobTypeResponse, api_error.ApiErr)
+	GetJobTypeByName(string) (*dto.JobTypeResponse, api_error.ApiErr)
+	UpdateJobType(string, dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr)
+	DeleteJobTypeByName(string) api_error.ApiErr
+	ValidateJobRequest(dto.CreateUpdateJobRequest) api_error.ApiErr
+}
+
+type DefaultJobTypeService struct {
+	repo domain.JobTypeRepository
+	Cfg  *config.AppConfig
+}
+
+func NewJobTypeService(cfg *config.AppConfig, repository domain.JobTypeRepository) DefaultJobTypeService {
+	return DefaultJobTypeService{
+		repo: repository,
+		Cfg:  cfg,
+	}
+}
+
+func (s DefaultJobTypeService) CreateJobType(typeReq dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr) {
+	newType, err := domain.NewJobTypeFromRequestDto(typeReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Store(*newType)
+	if err != nil {
+		return nil, err
+	}
+	response := newType.ToJobTypeResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTypeService) GetAllJobTypes() (*[]dto.JobTypeResponse, api_error.ApiErr) {
+	jobTypes, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	response := make([]dto.JobTypeResponse, 0)
+	for _, jobType := range *jobTypes {
+		response = append(response, jobType.ToJobTypeResponseDto())
+	}
+	return &response, nil
+}
+
+func (s DefaultJobTypeService) GetJobTypeByName(name string) (*dto.JobTypeResponse, api_error.ApiErr) {
+	jobType, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	response := jobType.ToJobTypeResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTypeService) UpdateJobType(name string, typeReq dto.CreateUpdateJobTypeRequest) (*dto.JobTypeResponse, api_error.ApiErr) {
+	jobType, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	err = jobType.ApplyRequest(typeReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Update(*jobType)
+	if err != nil {
+		return nil, err
+	}
+	response := jobType.ToJobTypeResponseDto()
+	return &response, nil
+}
+
+func (s DefaultJobTypeService) DeleteJobTypeByName(name string) api_error.ApiErr {
+	_, err := s.repo.FindByName(name)
+	if err != nil {
+		return api_error.NewNotFoundError(fmt.Sprintf("Job type %v does not exist", name))
+	}
+	return s.repo.DeleteByName(name)
+}
+
+func (s DefaultJobTypeService) ValidateJobRequest(jobReq dto.CreateUpdateJobRequest) api_error.ApiErr {
+	jobType, err := s.repo.FindByName(jobReq.Type)
+	if err != nil {
+		if err.StatusCode() == http.StatusNotFound {
+			err = api_error.NewBadRequestError(fmt.Sprintf("Job type %v is not registered", jobReq.Type))
+		}
+		return s.enforce(err)
+	}
+	return s.enforce(jobType.ValidateJobRequest(jobReq))
+}
+
+// uniqueKeyFor returns the explicit unique key of the request or derives it from the
+// unique fields of the registered job type. Unknown types have no derived key.
+func uniqueKeyFor(repo domain.JobTypeRepository, jobReq dto.CreateUpdateJobRequest) (string, api_error.ApiErr) {
+	if jobReq.UniqueKey != "" || repo == nil {
+		return jobReq.UniqueKey, nil
+	}
+	jobType, err := repo.FindByName(jobReq.Type)
+	if err != nil {
+		if err.StatusCode() == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return jobType.UniqueKey(jobReq), nil
+}
+
+func (s DefaultJobTypeService) enforce(err api_error.ApiErr) api_error.ApiErr {
+	if err == nil || s.Cfg.JobTypes.Strict || err.StatusCode() != http.StatusBadRequest {
+		return err
+	}
+	logger.Warn(fmt.Sprintf("Accepting job in permissive job type mode: %v", err.Message()))
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realdomain "github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockTypeRepo *domain.MockJobTypeRepository
+	typeService  DefaultJobTypeService
+)
+
+func setupJobType(t *testing.T) func() {
+	teardown := setupJob(t)
+	mockTypeRepo = domain.NewMockJobTypeRepository(jobCtrl)
+	typeService = NewJobTypeService(&cfg, mockTypeRepo)
+	return func() {
+		cfg.JobTypes.Strict = false
+		teardown()
+	}
+}
+
+func newTestJobType() *realdomain.JobType {
+	schema := `{"type":"object","required":["asset"]}`
+	return &realdomain.JobType{
+		Name:            "encoding",
+		SubTypes:        pq.StringArray{"h264", "hevc"},
+		ExtraDataSchema: &schema,
+	}
+}
+
+func Test_CreateJobType_InvalidSchema_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	typeReq := dto.CreateUpdateJobTypeRequest{
+		Name:            "encoding",
+		ExtraDataSchema: json.RawMessage(`{"type":"text"}`),
+	}
+
+	result, err := typeService.CreateJobType(typeReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Extra data schema is not valid: unknown type text", err.Message())
+}
+
+func Test_CreateJobType_NoError_Returns_JobType(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	typeReq := dto.CreateUpdateJobTypeRequest{
+		Name:     "encoding",
+		SubTypes: []string{"h264"},
+	}
+	mockTypeRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	result, err := typeService.CreateJobType(typeReq)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "encoding", result.Name)
+	assert.EqualValues(t, []string{"h264"}, result.SubTypes)
+}
+
+func Test_UpdateJobType_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	apiError := api_error.NewNotFoundError("No job type found for name encoding")
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(nil, apiError)
+
+	result, err := typeService.UpdateJobType("encoding", dto.CreateUpdateJobTypeRequest{})
+
+	assert.Nil(t, result)
+	assert.EqualValues(t, apiError, err)
+}
+
+func Test_UpdateJobType_NoError_Returns_JobType(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(newTestJobType(), nil)
+	mockTypeRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	result, err := typeService.UpdateJobType("encoding", dto.CreateUpdateJobTypeRequest{Actions: []string{"transcode"}})
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"h264", "hevc"}, result.SubTypes)
+	assert.EqualValues(t, []string{"transcode"}, result.Actions)
+}
+
+func Test_DeleteJobTypeByName_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(nil, api_error.NewNotFoundError("No job type found for name encoding"))
+
+	err := typeService.DeleteJobTypeByName("encoding")
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, "Job type encoding does not exist", err.Message())
+}
+
+func Test_ValidateJobRequest_UnknownTypeStrict_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	cfg.JobTypes.Strict = true
+	mockTypeRepo.EXPECT().FindByName("encodng").Return(nil, api_error.NewNotFoundError("No job type found for name encodng"))
+
+	err := typeService.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encodng"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Job type encodng is not registered", err.Message())
+}
+
+func Test_ValidateJobRequest_UnknownTypePermissive_Returns_NoError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	mockTypeRepo.EXPECT().FindByName("encodng").Return(nil, api_error.NewNotFoundError("No job type found for name encodng"))
+
+	err := typeService.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encodng"})
+
+	assert.Nil(t, err)
+}
+
+func Test_ValidateJobRequest_DbErrorPermissive_Returns_InternalServerError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(nil, api_error.NewInternalServerError("Database error getting job type by name", nil))
+
+	err := typeService.ValidateJobRequest(dto.CreateUpdateJobRequest{Type: "encoding"})
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.StatusCode())
+	assert.EqualValues(t, "Database error getting job type by name", err.Message())
+}
+
+func Test_ValidateJobRequest_SchemaMismatchStrict_Returns_BadRequestError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	cfg.JobTypes.Strict = true
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(newTestJobType(), nil)
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		ExtraData: json.RawMessage(`{"note":"no asset"}`),
+	}
+
+	err := typeService.ValidateJobRequest(jobReq)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Extra data does not match schema of job type encoding: extra_data.asset is required", err.Message())
+}
+
+func Test_ValidateJobRequest_ValidStrict_Returns_NoError(t *testing.T) {
+	teardown := setupJobType(t)
+	defer teardown()
+	cfg.JobTypes.Strict = true
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(newTestJobType(), nil)
+	jobReq := dto.CreateUpdateJobRequest{
+		Type:      "encoding",
+		SubType:   "hevc",
+		ExtraData: json.RawMessage(`{"asset":{"id":123}}`),
+	}
+
+	err := typeService.ValidateJobRequest(jobReq)
+
+	assert.Nil(t, err)
+}
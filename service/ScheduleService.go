@@ -0,0 +1,274 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/date"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+	"github.com/robfig/cron/v3"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockScheduleService.go -package=service github.com/johannes-kuhfuss/jobsvc/service ScheduleService
+type ScheduleService interface {
+	CreateSchedule(dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr)
+	GetAllSchedules() (*[]dto.ScheduleResponse, api_error.ApiErr)
+	GetScheduleById(string) (*dto.ScheduleResponse, api_error.ApiErr)
+	UpdateSchedule(string, dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr)
+	DeleteScheduleById(string) api_error.ApiErr
+	RunSchedule(string) api_error.ApiErr
+}
+
+type DefaultScheduleService struct {
+	repo            domain.ScheduleRepository
+	jobService      JobService
+	typeService     JobTypeService
+	templateService JobTemplateService
+	runner          *scheduleRunner
+	Cfg             *config.AppConfig
+}
+
+type scheduleRunner struct {
+	mu      sync.Mutex
+	cron    *cron.Cron
+	entries map[string]scheduleEntry
+}
+
+type scheduleEntry struct {
+	entryId cron.EntryID
+	spec    string
+}
+
+func NewScheduleService(cfg *config.AppConfig, repository domain.ScheduleRepository, jobService JobService, typeService JobTypeService, templateService JobTemplateService) DefaultScheduleService {
+	return DefaultScheduleService{
+		repo:            repository,
+		jobService:      jobService,
+		typeService:     typeService,
+		templateService: templateService,
+		runner: &scheduleRunner{
+			entries: make(map[string]scheduleEntry),
+		},
+		Cfg: cfg,
+	}
+}
+
+func (s DefaultScheduleService) Start(c *cron.Cron) api_error.ApiErr {
+	s.runner.mu.Lock()
+	s.runner.cron = c
+	s.runner.mu.Unlock()
+	schedules, err := s.repo.FindAll()
+	if err != nil {
+		return err
+	}
+	now := date.GetNowUtc()
+	for _, sched := range *schedules {
+		if sched.IsMissed(now) {
+			s.handleMissedRun(sched)
+		}
+	}
+	return s.SyncSchedules()
+}
+
+func (s DefaultScheduleService) SyncSchedules() api_error.ApiErr {
+	schedules, err := s.repo.FindAll()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, sched := range *schedules {
+		known[sched.Id.String()] = true
+		s.register(sched)
+	}
+	s.runner.mu.Lock()
+	defer s.runner.mu.Unlock()
+	for id, entry := range s.runner.entries {
+		if !known[id] {
+			s.runner.cron.Remove(entry.entryId)
+			delete(s.runner.entries, id)
+		}
+	}
+	return nil
+}
+
+func (s DefaultScheduleService) register(sched domain.Schedule) {
+	id := sched.Id.String()
+	spec := sched.CronSpec()
+	s.runner.mu.Lock()
+	defer s.runner.mu.Unlock()
+	if s.runner.cron == nil {
+		return
+	}
+	entry, found := s.runner.entries[id]
+	if found && sched.Enabled && entry.spec == spec {
+		return
+	}
+	if found {
+		s.runner.cron.Remove(entry.entryId)
+		delete(s.runner.entries, id)
+	}
+	if !sched.Enabled {
+		return
+	}
+	entryId, err := s.runner.cron.AddFunc(spec, func() {
+		if err := s.RunSchedule(id); err != nil {
+			logger.Error(fmt.Sprintf("Error while running schedule %v", id), err)
+		}
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not register schedule %v with cron expression %v", id, spec), err)
+		return
+	}
+	s.runner.entries[id] = scheduleEntry{entryId: entryId, spec: spec}
+}
+
+func (s DefaultScheduleService) unregister(id string) {
+	s.runner.mu.Lock()
+	defer s.runner.mu.Unlock()
+	if entry, found := s.runner.entries[id]; found {
+		s.runner.cron.Remove(entry.entryId)
+		delete(s.runner.entries, id)
+	}
+}
+
+func (s DefaultScheduleService) handleMissedRun(sched domain.Schedule) {
+	now := date.GetNowUtc()
+	nextRun, err := sched.NextRun(now)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Could not compute next run of schedule %v", sched.Id.String()), err)
+		return
+	}
+	if sched.MissedRunPolicy == domain.MissedRunRunOnce {
+		claimed, err := s.repo.ClaimRun(sched.Id.String(), sched.NextRunAt, &now, nextRun)
+		if err != nil || !claimed {
+			return
+		}
+		logger.Info(fmt.Sprintf("Schedule %v missed its run at %v. Running it once now", sched.Id.String(), sched.NextRunAt))
+		s.createJob(sched)
+		return
+	}
+	claimed, err := s.repo.ClaimRun(sched.Id.String(), sched.NextRunAt, sched.LastRunAt, nextRun)
+	if err == nil && claimed {
+		logger.Info(fmt.Sprintf("Schedule %v missed its run at %v. Skipping it", sched.Id.String(), sched.NextRunAt))
+	}
+}
+
+func (s DefaultScheduleService) createJob(sched domain.Schedule) api_error.ApiErr {
+	jobReq, err := sched.JobRequest()
+	if err != nil {
+		return err
+	}
+	if jobReq.Template != "" && s.templateService != nil {
+		jobReq, err = s.templateService.ResolveJobRequest(*jobReq)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Could not resolve job template of schedule %v", sched.Id.String()), err)
+			return err
+		}
+	}
+	if s.typeService != nil {
+		err = s.typeService.ValidateJobRequest(*jobReq)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Job request of schedule %v does not match its job type", sched.Id.String()), err)
+			return err
+		}
+	}
+	job, err := s.jobService.CreateJob(*jobReq)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Schedule %v created job %v", sched.Id.String(), job.Id))
+	return nil
+}
+
+func (s DefaultScheduleService) CreateSchedule(schedReq dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr) {
+	newSchedule, err := domain.NewScheduleFromRequestDto(schedReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Store(*newSchedule)
+	if err != nil {
+		return nil, err
+	}
+	s.register(*newSchedule)
+	response := newSchedule.ToScheduleResponseDto()
+	return &response, nil
+}
+
+func (s DefaultScheduleService) GetAllSchedules() (*[]dto.ScheduleResponse, api_error.ApiErr) {
+	schedules, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	response := make([]dto.ScheduleResponse, 0)
+	for _, sched := range *schedules {
+		response = append(response, sched.ToScheduleResponseDto())
+	}
+	return &response, nil
+}
+
+func (s DefaultScheduleService) GetScheduleById(id string) (*dto.ScheduleResponse, api_error.ApiErr) {
+	sched, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	response := sched.ToScheduleResponseDto()
+	return &response, nil
+}
+
+func (s DefaultScheduleService) UpdateSchedule(id string, schedReq dto.CreateUpdateScheduleRequest) (*dto.ScheduleResponse, api_error.ApiErr) {
+	sched, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	err = sched.ApplyRequest(schedReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Update(*sched)
+	if err != nil {
+		return nil, err
+	}
+	s.register(*sched)
+	response := sched.ToScheduleResponseDto()
+	return &response, nil
+}
+
+func (s DefaultScheduleService) DeleteScheduleById(id string) api_error.ApiErr {
+	_, err := s.repo.FindById(id)
+	if err != nil {
+		return api_error.NewNotFoundError(fmt.Sprintf("Schedule with id %v does not exist", id))
+	}
+	err = s.repo.DeleteById(id)
+	if err != nil {
+		return err
+	}
+	s.unregister(id)
+	return nil
+}
+
+func (s DefaultScheduleService) RunSchedule(id string) api_error.ApiErr {
+	sched, err := s.repo.FindById(id)
+	if err != nil {
+		return err
+	}
+	if !sched.Enabled {
+		return nil
+	}
+	now := date.GetNowUtc()
+	nextRun, err := sched.NextRun(now)
+	if err != nil {
+		return err
+	}
+	claimed, err := s.repo.ClaimRun(id, sched.NextRunAt, &now, nextRun)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		logger.Info(fmt.Sprintf("Run of schedule %v was already claimed by another instance", id))
+		return nil
+	}
+	return s.createJob(*sched)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	realdomain "github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockSchedRepo *domain.MockScheduleRepository
+	schedService  DefaultScheduleService
+)
+
+func setupSchedule(t *testing.T) func() {
+	teardown := setupJob(t)
+	mockSchedRepo = domain.NewMockScheduleRepository(jobCtrl)
+	schedService = NewScheduleService(&cfg, mockSchedRepo, jobService, nil, nil)
+	return teardown
+}
+
+func newTestSchedule() realdomain.Schedule {
+	sched, _ := realdomain.NewScheduleFromRequestDto(dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Name: "Morning encode", Type: "encoding"},
+	})
+	return *sched
+}
+
+func Test_CreateSchedule_InvalidCron_Returns_BadRequestError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "every morning",
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+
+	result, err := schedService.CreateSchedule(schedReq)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_CreateSchedule_NoError_Returns_Schedule(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	schedReq := dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Type: "encoding"},
+	}
+	mockSchedRepo.EXPECT().Store(gomock.Any()).Return(nil)
+
+	result, err := schedService.CreateSchedule(schedReq)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, result)
+	assert.EqualValues(t, "0 6 * * *", result.CronExpr)
+	assert.EqualValues(t, "encoding", result.Job.Type)
+	assert.NotNil(t, result.NextRunAt)
+}
+
+func Test_GetScheduleById_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	id := ksuid.New().String()
+	apiError := api_error.NewNotFoundError(fmt.Sprintf("No schedule found for id %v", id))
+	mockSchedRepo.EXPECT().FindById(id).Return(nil, apiError)
+
+	result, err := schedService.GetScheduleById(id)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_DeleteScheduleById_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	id := ksuid.New().String()
+	mockSchedRepo.EXPECT().FindById(id).Return(nil, api_error.NewNotFoundError("not found"))
+
+	err := schedService.DeleteScheduleById(id)
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualValues(t, fmt.Sprintf("Schedule with id %v does not exist", id), err.Message())
+}
+
+func Test_RunSchedule_Disabled_Returns_NoError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	sched := newTestSchedule()
+	sched.Enabled = false
+	mockSchedRepo.EXPECT().FindById(sched.Id.String()).Return(&sched, nil)
+
+	err := schedService.RunSchedule(sched.Id.String())
+
+	assert.Nil(t, err)
+}
+
+func Test_RunSchedule_AlreadyClaimed_Returns_NoError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	sched := newTestSchedule()
+	mockSchedRepo.EXPECT().FindById(sched.Id.String()).Return(&sched, nil)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), sched.NextRunAt, gomock.Any(), gomock.Any()).Return(false, nil)
+
+	err := schedService.RunSchedule(sched.Id.String())
+
+	assert.Nil(t, err)
+}
+
+func Test_RunSchedule_Claimed_Returns_JobCreated(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	sched := newTestSchedule()
+	mockSchedRepo.EXPECT().FindById(sched.Id.String()).Return(&sched, nil)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), sched.NextRunAt, gomock.Any(), gomock.Any()).Return(true, nil)
+	mockJobRepo.EXPECT().Store(gomock.Any()).DoAndReturn(func(job realdomain.Job) api_error.ApiErr {
+		assert.EqualValues(t, "Morning encode", job.Name)
+		assert.EqualValues(t, "encoding", job.Type)
+		return nil
+	})
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	err := schedService.RunSchedule(sched.Id.String())
+
+	assert.Nil(t, err)
+}
+
+func Test_RunSchedule_Template_Returns_ResolvedJobCreated(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	mockTemplateRepo := domain.NewMockJobTemplateRepository(jobCtrl)
+	schedService = NewScheduleService(&cfg, mockSchedRepo, jobService, nil, NewJobTemplateService(&cfg, mockTemplateRepo))
+	sched, _ := realdomain.NewScheduleFromRequestDto(dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Name: "Morning encode", Type: "encoding", Template: "encode_hd"},
+	})
+	mockSchedRepo.EXPECT().FindById(sched.Id.String()).Return(sched, nil)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), sched.NextRunAt, gomock.Any(), gomock.Any()).Return(true, nil)
+	mockTemplateRepo.EXPECT().FindByName("encode_hd", int32(0)).Return(newTestJobTemplate(), nil)
+	mockJobRepo.EXPECT().Store(gomock.Any()).DoAndReturn(func(job realdomain.Job) api_error.ApiErr {
+		assert.EqualValues(t, "Morning encode", job.Name)
+		assert.EqualValues(t, "h264", job.SubType)
+		return nil
+	})
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	err := schedService.RunSchedule(sched.Id.String())
+
+	assert.Nil(t, err)
+}
+
+func Test_RunSchedule_InvalidForJobType_Returns_BadRequestError(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	cfg.JobTypes.Strict = true
+	defer func() { cfg.JobTypes.Strict = false }()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	schedService = NewScheduleService(&cfg, mockSchedRepo, jobService, NewJobTypeService(&cfg, mockTypeRepo), nil)
+	sched, _ := realdomain.NewScheduleFromRequestDto(dto.CreateUpdateScheduleRequest{
+		CronExpr: "0 6 * * *",
+		Job:      &dto.CreateUpdateJobRequest{Name: "Morning encode", Type: "encoding", SubType: "vp9"},
+	})
+	mockSchedRepo.EXPECT().FindById(sched.Id.String()).Return(sched, nil)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), sched.NextRunAt, gomock.Any(), gomock.Any()).Return(true, nil)
+	mockTypeRepo.EXPECT().FindByName("encoding").Return(newTestJobType(), nil)
+	mockJobRepo.EXPECT().Store(gomock.Any()).Times(0)
+
+	err := schedService.RunSchedule(sched.Id.String())
+
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+	assert.EqualValues(t, "Sub type vp9 is not allowed for job type encoding", err.Message())
+}
+
+func Test_Start_MissedRunOnce_Returns_JobCreated(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	sched := newTestSchedule()
+	sched.MissedRunPolicy = realdomain.MissedRunRunOnce
+	missed := time.Now().UTC().Add(-time.Hour)
+	sched.NextRunAt = &missed
+	schedules := []realdomain.Schedule{sched}
+	mockSchedRepo.EXPECT().FindAll().Return(&schedules, nil).Times(2)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), &missed, gomock.Any(), gomock.Any()).Return(true, nil)
+	mockJobRepo.EXPECT().Store(gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	err := schedService.Start(nil)
+
+	assert.Nil(t, err)
+}
+
+func Test_Start_MissedSkip_Returns_NoJobCreated(t *testing.T) {
+	teardown := setupSchedule(t)
+	defer teardown()
+	sched := newTestSchedule()
+	missed := time.Now().UTC().Add(-time.Hour)
+	sched.NextRunAt = &missed
+	schedules := []realdomain.Schedule{sched}
+	mockSchedRepo.EXPECT().FindAll().Return(&schedules, nil).Times(2)
+	mockSchedRepo.EXPECT().ClaimRun(sched.Id.String(), &missed, sched.LastRunAt, gomock.Any()).Return(true, nil)
+
+	err := schedService.Start(nil)
+
+	assert.Nil(t, err)
+}
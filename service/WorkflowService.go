@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/johannes-kuhfuss/jobsvc/config"
+	"github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/johannes-kuhfuss/services_utils/logger"
+)
+
+//go:generate mockgen -destination=../mocks/service/mockWorkflowService.go -package=service github.com/johannes-kuhfuss/jobsvc/service WorkflowService
+type WorkflowService interface {
+	CreateWorkflow(dto.CreateWorkflowRequest) (*dto.WorkflowResponse, api_error.ApiErr)
+	GetAllWorkflows() (*[]dto.WorkflowResponse, api_error.ApiErr)
+	GetWorkflowById(string) (*dto.WorkflowResponse, api_error.ApiErr)
+	AdvanceWorkflows() api_error.ApiErr
+}
+
+type DefaultWorkflowService struct {
+	repo     domain.WorkflowRepository
+	jobRepo  domain.JobRepository
+	typeRepo domain.JobTypeRepository
+	Cfg      *config.AppConfig
+}
+
+func NewWorkflowService(cfg *config.AppConfig, repository domain.WorkflowRepository, jobRepository domain.JobRepository, typeRepository domain.JobTypeRepository) DefaultWorkflowService {
+	return DefaultWorkflowService{
+		repo:     repository,
+		jobRepo:  jobRepository,
+		typeRepo: typeRepository,
+		Cfg:      cfg,
+	}
+}
+
+func (s DefaultWorkflowService) CreateWorkflow(wfReq dto.CreateWorkflowRequest) (*dto.WorkflowResponse, api_error.ApiErr) {
+	newWorkflow, firstJob, err := domain.NewWorkflowFromRequestDto(wfReq)
+	if err != nil {
+		return nil, err
+	}
+	err = s.setUniqueKey(*newWorkflow, firstJob)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Store(*newWorkflow, *firstJob)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyJobCreated(firstJob)
+	response := newWorkflow.ToWorkflowResponseDto([]domain.Job{*firstJob})
+	return &response, nil
+}
+
+func (s DefaultWorkflowService) GetAllWorkflows() (*[]dto.WorkflowResponse, api_error.ApiErr) {
+	workflows, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	jobIds := make([]string, 0)
+	for _, wf := range *workflows {
+		jobIds = append(jobIds, wf.StageJobIds()...)
+	}
+	stageJobs := &[]domain.Job{}
+	if len(jobIds) > 0 {
+		stageJobs, err = s.repo.FindStageJobs(jobIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response := make([]dto.WorkflowResponse, 0)
+	for _, wf := range *workflows {
+		response = append(response, wf.ToWorkflowResponseDto(*stageJobs))
+	}
+	return &response, nil
+}
+
+func (s DefaultWorkflowService) GetWorkflowById(id string) (*dto.WorkflowResponse, api_error.ApiErr) {
+	wf, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	stageJobs, err := s.repo.FindStageJobs(wf.StageJobIds())
+	if err != nil {
+		return nil, err
+	}
+	response := wf.ToWorkflowResponseDto(*stageJobs)
+	return &response, nil
+}
+
+func (s DefaultWorkflowService) AdvanceWorkflows() api_error.ApiErr {
+	workflows, err := s.repo.FindRunning()
+	if err != nil {
+		return err
+	}
+	for _, wf := range *workflows {
+		if err := s.advanceWorkflow(wf); err != nil {
+			logger.Error(fmt.Sprintf("Could not advance workflow %v", wf.Id.String()), err)
+		}
+	}
+	return nil
+}
+
+func (s DefaultWorkflowService) advanceWorkflow(wf domain.Workflow) api_error.ApiErr {
+	stages, err := wf.StageList()
+	if err != nil {
+		return err
+	}
+	expectedStage := wf.CurrentStage
+	var stageJob *domain.Job
+	if int(expectedStage) < len(stages) {
+		stageJobs, err := s.repo.FindStageJobs([]string{stages[expectedStage].JobId})
+		if err != nil {
+			return err
+		}
+		if len(*stageJobs) > 0 {
+			stageJob = &(*stageJobs)[0]
+		}
+	}
+	nextJob, changed, err := wf.Advance(stageJob)
+	if err != nil || !changed {
+		return err
+	}
+	if nextJob != nil {
+		err = s.setUniqueKey(wf, nextJob)
+		if err != nil {
+			return err
+		}
+	}
+	claimed, err := s.repo.AdvanceStage(wf, expectedStage, nextJob)
+	if err != nil || !claimed {
+		return err
+	}
+	if nextJob != nil {
+		s.notifyJobCreated(nextJob)
+		logger.Info(fmt.Sprintf("Workflow %v started stage %d with job %v", wf.Id.String(), wf.CurrentStage+1, nextJob.Id.String()))
+	} else {
+		logger.Info(fmt.Sprintf("Workflow %v is %v", wf.Id.String(), wf.Status))
+	}
+	return nil
+}
+
+// setUniqueKey derives the unique key of the job of the current stage from the stage request.
+func (s DefaultWorkflowService) setUniqueKey(wf domain.Workflow, job *domain.Job) api_error.ApiErr {
+	stages, err := wf.StageList()
+	if err != nil {
+		return err
+	}
+	uniqueKey, err := uniqueKeyFor(s.typeRepo, stages[wf.CurrentStage].Job)
+	if err != nil {
+		return err
+	}
+	job.UniqueKey = uniqueKey
+	return nil
+}
+
+func (s DefaultWorkflowService) notifyJobCreated(job *domain.Job) {
+	err := s.jobRepo.NotifyJobCreated(job.Type)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Could not notify other instances about new job %v", job.Id.String()))
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	realdomain "github.com/johannes-kuhfuss/jobsvc/domain"
+	"github.com/johannes-kuhfuss/jobsvc/dto"
+	"github.com/johannes-kuhfuss/jobsvc/mocks/domain"
+	"github.com/johannes-kuhfuss/services_utils/api_error"
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	mockWfRepo *domain.MockWorkflowRepository
+	wfService  DefaultWorkflowService
+)
+
+func setupWorkflow(t *testing.T) func() {
+	teardown := setupJob(t)
+	mockWfRepo = domain.NewMockWorkflowRepository(jobCtrl)
+	wfService = NewWorkflowService(&cfg, mockWfRepo, mockJobRepo, nil)
+	return teardown
+}
+
+func newTestWorkflowRequest() dto.CreateWorkflowRequest {
+	return dto.CreateWorkflowRequest{
+		Name: "media pipeline",
+		Stages: []dto.CreateUpdateJobRequest{
+			{Name: "encode", Type: "encoding", Destination: "/out/file.mp4"},
+			{Name: "proxy", Type: "proxy"},
+		},
+	}
+}
+
+func Test_CreateWorkflow_NoStages_Returns_BadRequestError(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+
+	result, err := wfService.CreateWorkflow(dto.CreateWorkflowRequest{})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.StatusCode())
+}
+
+func Test_CreateWorkflow_NoError_Returns_Workflow(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	mockWfRepo.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil)
+	mockJobRepo.EXPECT().NotifyJobCreated("encoding").Return(nil)
+
+	result, err := wfService.CreateWorkflow(newTestWorkflowRequest())
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, "running", result.Status)
+	assert.EqualValues(t, 2, result.StageCount)
+	assert.EqualValues(t, "created", result.Stages[0].Status)
+	assert.EqualValues(t, "pending", result.Stages[1].Status)
+}
+
+func Test_GetWorkflowById_NotFound_Returns_NotFoundError(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	id := ksuid.New().String()
+	mockWfRepo.EXPECT().FindById(id).Return(nil, api_error.NewNotFoundError("not found"))
+
+	result, err := wfService.GetWorkflowById(id)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.StatusCode())
+}
+
+func Test_GetAllWorkflows_Loads_StageJobsOnce(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	wf1, job1, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	wf2, job2, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	job2.Status = realdomain.StatusRunning
+	workflows := []realdomain.Workflow{*wf1, *wf2}
+	stageJobs := []realdomain.Job{*job1, *job2}
+	mockWfRepo.EXPECT().FindAll().Return(&workflows, nil)
+	mockWfRepo.EXPECT().FindStageJobs([]string{job1.Id.String(), job2.Id.String()}).Return(&stageJobs, nil).Times(1)
+
+	result, err := wfService.GetAllWorkflows()
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, len(*result))
+	assert.EqualValues(t, "created", (*result)[0].Stages[0].Status)
+	assert.EqualValues(t, "running", (*result)[1].Stages[0].Status)
+}
+
+func Test_AdvanceWorkflows_StageFinished_Creates_NextJob(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	wf, firstJob, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	firstJob.Status = realdomain.StatusFinished
+	firstJob.Destination = "/out/file.mp4"
+	workflows := []realdomain.Workflow{*wf}
+	stageJobs := []realdomain.Job{*firstJob}
+	mockWfRepo.EXPECT().FindRunning().Return(&workflows, nil)
+	mockWfRepo.EXPECT().FindStageJobs([]string{firstJob.Id.String()}).Return(&stageJobs, nil)
+	mockWfRepo.EXPECT().AdvanceStage(gomock.Any(), int32(0), gomock.Any()).DoAndReturn(
+		func(advWf realdomain.Workflow, expected int32, nextJob *realdomain.Job) (bool, api_error.ApiErr) {
+			assert.EqualValues(t, 1, advWf.CurrentStage)
+			assert.EqualValues(t, "proxy", nextJob.Type)
+			assert.EqualValues(t, "/out/file.mp4", nextJob.Source)
+			return true, nil
+		})
+	mockJobRepo.EXPECT().NotifyJobCreated("proxy").Return(nil)
+
+	err := wfService.AdvanceWorkflows()
+
+	assert.Nil(t, err)
+}
+
+func Test_AdvanceWorkflows_JobTypeUniqueFields_Sets_UniqueKey(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	mockTypeRepo := domain.NewMockJobTypeRepository(jobCtrl)
+	wfService = NewWorkflowService(&cfg, mockWfRepo, mockJobRepo, mockTypeRepo)
+	wf, firstJob, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	firstJob.Status = realdomain.StatusFinished
+	firstJob.Destination = "/out/file.mp4"
+	workflows := []realdomain.Workflow{*wf}
+	stageJobs := []realdomain.Job{*firstJob}
+	jobType := newTestJobType()
+	jobType.Name = "proxy"
+	jobType.UniqueFields = pq.StringArray{"source"}
+	mockWfRepo.EXPECT().FindRunning().Return(&workflows, nil)
+	mockWfRepo.EXPECT().FindStageJobs([]string{firstJob.Id.String()}).Return(&stageJobs, nil)
+	mockTypeRepo.EXPECT().FindByName("proxy").Return(jobType, nil)
+	mockWfRepo.EXPECT().AdvanceStage(gomock.Any(), int32(0), gomock.Any()).DoAndReturn(
+		func(advWf realdomain.Workflow, expected int32, nextJob *realdomain.Job) (bool, api_error.ApiErr) {
+			assert.EqualValues(t, `source="/out/file.mp4"`, nextJob.UniqueKey)
+			return true, nil
+		})
+	mockJobRepo.EXPECT().NotifyJobCreated("proxy").Return(nil)
+
+	err := wfService.AdvanceWorkflows()
+
+	assert.Nil(t, err)
+}
+
+func Test_AdvanceWorkflows_StageRunning_Returns_NoChange(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	wf, firstJob, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	firstJob.Status = realdomain.StatusRunning
+	workflows := []realdomain.Workflow{*wf}
+	stageJobs := []realdomain.Job{*firstJob}
+	mockWfRepo.EXPECT().FindRunning().Return(&workflows, nil)
+	mockWfRepo.EXPECT().FindStageJobs([]string{firstJob.Id.String()}).Return(&stageJobs, nil)
+
+	err := wfService.AdvanceWorkflows()
+
+	assert.Nil(t, err)
+}
+
+func Test_AdvanceWorkflows_StageFailed_Fails_Workflow(t *testing.T) {
+	teardown := setupWorkflow(t)
+	defer teardown()
+	wf, firstJob, _ := realdomain.NewWorkflowFromRequestDto(newTestWorkflowRequest())
+	firstJob.Status = realdomain.StatusFailed
+	workflows := []realdomain.Workflow{*wf}
+	stageJobs := []realdomain.Job{*firstJob}
+	mockWfRepo.EXPECT().FindRunning().Return(&workflows, nil)
+	mockWfRepo.EXPECT().FindStageJobs([]string{firstJob.Id.String()}).Return(&stageJobs, nil)
+	mockWfRepo.EXPECT().AdvanceStage(gomock.Any(), int32(0), nil).DoAndReturn(
+		func(advWf realdomain.Workflow, expected int32, nextJob *realdomain.Job) (bool, api_error.ApiErr) {
+			assert.EqualValues(t, realdomain.WorkflowFailed, advWf.Status)
+			return true, nil
+		})
+
+	err := wfService.AdvanceWorkflows()
+
+	assert.Nil(t, err)
+}